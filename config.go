@@ -21,6 +21,7 @@ const (
 	defaultLogFilename    = "godcr.log"
 	defaultLogLevel       = "info"
 	defaultLogDirname     = "logs"
+	defaultRPCListen      = "127.0.0.1:9117"
 )
 
 var (
@@ -40,6 +41,11 @@ type config struct {
 	Quiet            bool   `short:"q" long:"quiet" description:"Easy way to set debuglevel to error"`
 	SpendUnconfirmed bool   `long:"spendunconfirmed" description:"Allow the multiwallet to use transactions that have not been confirmed"`
 	Profile          int    `long:"profile" description:"Runs local web server for profiling"`
+	Headless         bool   `long:"headless" description:"Run without a window and serve the wallet over JSON-RPC"`
+	RPC              bool   `long:"rpc" description:"Alias for --headless"`
+	RPCListen        string `long:"rpclisten" description:"Address to listen on for JSON-RPC connections in headless mode"`
+	RPCUser          string `long:"rpcuser" description:"Username for JSON-RPC connections"`
+	RPCPass          string `long:"rpcpass" default-mask:"-" description:"Password for JSON-RPC connections"`
 }
 
 var defaultConfig = config{
@@ -48,6 +54,7 @@ var defaultConfig = config{
 	ConfigFile: defaultConfigFilename,
 	LogDir:     defaultLogDir,
 	DebugLevel: defaultLogLevel,
+	RPCListen:  defaultRPCListen,
 }
 
 // validLogLevel returns whether or not logLevel is a valid debug log level.
@@ -276,6 +283,15 @@ func loadConfig() (*config, error) {
 		return loadConfigError(err)
 	}
 
+	// The JSON-RPC server exposes spending commands, so refuse to start it
+	// without credentials.
+	cfg.Headless = cfg.Headless || cfg.RPC
+	if cfg.Headless && (cfg.RPCUser == "" || cfg.RPCPass == "") {
+		err := fmt.Errorf("%s: --rpcuser and --rpcpass are required in headless mode", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return loadConfigError(err)
+	}
+
 	log.Debugf("Log folder: %s", cfg.LogDir)
 	log.Debugf("Config file: %s", configFile)
 
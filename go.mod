@@ -3,15 +3,22 @@ module github.com/planetdecred/godcr
 go 1.13
 
 require (
+	decred.org/dcrwallet v1.6.0
 	gioui.org v0.0.0-20210418151603-3b69b5ed0512
 	github.com/JohannesKaufmann/html-to-markdown v1.2.1
 	github.com/PuerkitoBio/goquery v1.6.1
 	github.com/ararog/timeago v0.0.0-20160328174124-e9969cf18b8d
 	github.com/decred/dcrd/chaincfg v1.5.2 // indirect
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.3-0.20200921185235-6d75c7ec1199
+	github.com/decred/dcrd/chaincfg/v3 v3.0.0
+	github.com/decred/dcrd/dcrec v1.0.1-0.20200921185235-6d75c7ec1199
 	github.com/decred/dcrd/dcrutil v1.4.0
 	github.com/decred/dcrd/dcrutil/v2 v2.0.1
 	github.com/decred/dcrd/dcrutil/v3 v3.0.0
+	github.com/decred/dcrd/hdkeychain/v3 v3.0.0
+	github.com/decred/dcrd/txscript/v3 v3.0.0
+	github.com/decred/dcrd/wire v1.4.0
+	github.com/decred/politeia v1.0.0
 	github.com/decred/slog v1.1.0
 	github.com/gen2brain/beeep v0.0.0-20200526185328-e9c15c258e28
 	github.com/gomarkdown/markdown v0.0.0-20210208175418-bda154fe17d8
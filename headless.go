@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/planetdecred/godcr/rpcserver"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+// runHeadless loads the wallets and serves them over JSON-RPC until the
+// process is interrupted. No window is created.
+func runHeadless(cfg *config, wal *wallet.Wallet) {
+	loaded, err := wal.LoadWalletsCtx(context.Background())
+	if err != nil {
+		log.Errorf("Could not load wallets: %v", err)
+		return
+	}
+	defer wal.Shutdown()
+
+	server, err := rpcserver.New(rpcserver.Config{
+		Listen:   cfg.RPCListen,
+		Username: cfg.RPCUser,
+		Password: cfg.RPCPass,
+	}, wal)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	if err := server.Start(); err != nil {
+		log.Errorf("Could not start JSON-RPC server: %v", err)
+		return
+	}
+	defer server.Stop()
+
+	// Wallets protected by a startup passphrase are opened and synced
+	// through the openwallets and startsync methods.
+	if loaded.Count > 0 && !loaded.StartUpSecuritySet {
+		if err := wal.StartSync(); err != nil {
+			log.Errorf("Could not start sync: %v", err)
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	<-interrupt
+	log.Info("Shutting down")
+}
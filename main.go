@@ -33,6 +33,23 @@ func main() {
 
 	dcrlibwallet.SetLogLevels(cfg.DebugLevel)
 
+	var confirms int32 = dcrlibwallet.DefaultRequiredConfirmations
+
+	if cfg.SpendUnconfirmed {
+		confirms = 0
+	}
+
+	wal, err := wallet.NewWallet(cfg.HomeDir, cfg.Network, make(chan wallet.Response, 3), confirms)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	if cfg.Headless {
+		runHeadless(cfg, wal)
+		return
+	}
+
 	absoluteWdPath, err := ui.GetAbsolutePath()
 	if err != nil {
 		panic(err)
@@ -60,18 +77,6 @@ func main() {
 		log.Warn(err)
 	}
 
-	var confirms int32 = dcrlibwallet.DefaultRequiredConfirmations
-
-	if cfg.SpendUnconfirmed {
-		confirms = 0
-	}
-
-	wal, err := wallet.NewWallet(cfg.HomeDir, cfg.Network, make(chan wallet.Response, 3), confirms)
-	if err != nil {
-		log.Error(err)
-		return
-	}
-
 	shutdown := make(chan int)
 	go func() {
 		<-shutdown
@@ -0,0 +1,237 @@
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type handlerFunc func(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error)
+
+// handlers maps each JSON-RPC method to the wallet command it runs.
+var handlers map[string]handlerFunc
+
+func init() {
+	handlers = map[string]handlerFunc{
+		"openwallets":          handleOpenWallets,
+		"startsync":            handleStartSync,
+		"getmultiwalletinfo":   handleGetMultiWalletInfo,
+		"getalltransactions":   handleGetAllTransactions,
+		"gettransaction":       handleGetTransaction,
+		"getalltickets":        handleGetAllTickets,
+		"ticketprice":          handleTicketPrice,
+		"currentaddress":       handleCurrentAddress,
+		"nextaddress":          handleNextAddress,
+		"validateaddress":      handleValidateAddress,
+		"signmessage":          handleSignMessage,
+		"verifymessage":        handleVerifyMessage,
+		"broadcasttransaction": handleBroadcastTransaction,
+		"purchaseticket":       handlePurchaseTicket,
+	}
+}
+
+// parseParams decodes the named params of a request into v. Methods
+// without params accept a missing or null params field.
+func parseParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &Error{Code: ErrCodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func invalidParams(format string, a ...interface{}) error {
+	return &Error{Code: ErrCodeInvalidParams, Message: fmt.Sprintf(format, a...)}
+}
+
+type accountParams struct {
+	WalletID int   `json:"walletid"`
+	Account  int32 `json:"account"`
+}
+
+func handleOpenWallets(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Passphrase string `json:"passphrase"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := s.wallet.OpenWalletsCtx(ctx, p.Passphrase); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func handleStartSync(_ context.Context, s *Server, _ json.RawMessage) (interface{}, error) {
+	if err := s.wallet.StartSync(); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func handleGetMultiWalletInfo(ctx context.Context, s *Server, _ json.RawMessage) (interface{}, error) {
+	return s.wallet.GetMultiWalletInfoCtx(ctx)
+}
+
+func handleGetAllTransactions(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Offset int32 `json:"offset"`
+		Limit  int32 `json:"limit"`
+		Filter int32 `json:"filter"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	return s.wallet.GetAllTransactionsCtx(ctx, p.Offset, p.Limit, p.Filter)
+}
+
+func handleGetTransaction(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		WalletID int    `json:"walletid"`
+		Hash     string `json:"hash"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Hash == "" {
+		return nil, invalidParams("hash is required")
+	}
+
+	return s.wallet.GetTransactionCtx(ctx, p.WalletID, p.Hash)
+}
+
+func handleGetAllTickets(ctx context.Context, s *Server, _ json.RawMessage) (interface{}, error) {
+	return s.wallet.GetAllTicketsCtx(ctx)
+}
+
+func handleTicketPrice(_ context.Context, s *Server, _ json.RawMessage) (interface{}, error) {
+	price, _, err := s.wallet.TicketPrice()
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+func handleCurrentAddress(_ context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p accountParams
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	return s.wallet.CurrentAddress(p.WalletID, p.Account)
+}
+
+func handleNextAddress(_ context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p accountParams
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	return s.wallet.NextAddress(p.WalletID, p.Account)
+}
+
+func handleValidateAddress(_ context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Address string `json:"address"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	valid, err := s.wallet.IsAddressValid(p.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		IsValid    bool   `json:"isvalid"`
+		IsMine     bool   `json:"ismine"`
+		WalletName string `json:"walletname,omitempty"`
+	}{IsValid: valid}
+	if valid {
+		result.IsMine, result.WalletName = s.wallet.HaveAddress(p.Address)
+	}
+
+	return result, nil
+}
+
+func handleSignMessage(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		WalletID   int    `json:"walletid"`
+		Passphrase string `json:"passphrase"`
+		Address    string `json:"address"`
+		Message    string `json:"message"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	return s.wallet.SignMessageCtx(ctx, p.WalletID, []byte(p.Passphrase), p.Address, p.Message)
+}
+
+func handleVerifyMessage(_ context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Address   string `json:"address"`
+		Message   string `json:"message"`
+		Signature string `json:"signature"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	return s.wallet.VerifyMessage(p.Address, p.Message, p.Signature)
+}
+
+func handleBroadcastTransaction(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		WalletID   int    `json:"walletid"`
+		Account    int32  `json:"account"`
+		Address    string `json:"address"`
+		Amount     int64  `json:"amount"`
+		SendMax    bool   `json:"sendmax"`
+		Passphrase string `json:"passphrase"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Address == "" {
+		return nil, invalidParams("address is required")
+	}
+
+	txAuthor, err := s.wallet.CreateTransactionCtx(ctx, p.WalletID, p.Account)
+	if err != nil {
+		return nil, err
+	}
+	if err := txAuthor.AddSendDestination(p.Address, p.Amount, p.SendMax); err != nil {
+		return nil, err
+	}
+
+	return s.wallet.BroadcastTransactionCtx(ctx, txAuthor, []byte(p.Passphrase))
+}
+
+func handlePurchaseTicket(ctx context.Context, s *Server, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		WalletID   int    `json:"walletid"`
+		Account    int32  `json:"account"`
+		Tickets    uint32 `json:"tickets"`
+		VSPHost    string `json:"vsphost"`
+		Passphrase string `json:"passphrase"`
+	}
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Tickets == 0 {
+		return nil, invalidParams("tickets must be greater than zero")
+	}
+
+	vspd, err := s.wallet.NewVSPD(p.VSPHost, p.WalletID, p.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.wallet.PurchaseTicketCtx(ctx, p.WalletID, p.Account, p.Tickets, []byte(p.Passphrase), vspd); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
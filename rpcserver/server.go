@@ -0,0 +1,244 @@
+// Package rpcserver exposes the wallet package over an authenticated
+// JSON-RPC interface so godcr can be driven without a window.
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/planetdecred/godcr/wallet"
+)
+
+// Standard JSON-RPC 2.0 error codes.
+const (
+	ErrCodeParse          = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+
+	// ErrCodeWallet is returned when the wallet backend fails a command.
+	ErrCodeWallet = -1
+)
+
+// maxRequestSize caps the size of a request body read by the server.
+const maxRequestSize = 1 << 20
+
+// Config holds the options used to start a Server.
+type Config struct {
+	// Listen is the address the server listens on. Addresses starting
+	// with a "/" are treated as unix socket paths.
+	Listen   string
+	Username string
+	Password string
+}
+
+// Server serves wallet commands as JSON-RPC methods over HTTP.
+type Server struct {
+	cfg     Config
+	wallet  *wallet.Wallet
+	authSha [sha256.Size]byte
+
+	httpServer *http.Server
+	quit       chan struct{}
+}
+
+// Request is a JSON-RPC request.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// Response is a JSON-RPC response. Exactly one of Result and Error is set.
+type Response struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// New returns a Server for wal. Commands are run through the blocking wallet
+// API, so wallet.Send is left alone. The wallet's Sync channel is consumed
+// by the server once it is started.
+func New(cfg Config, wal *wallet.Wallet) (*Server, error) {
+	if cfg.Username == "" || cfg.Password == "" {
+		return nil, errors.New("rpc username and password are required")
+	}
+
+	return &Server{
+		cfg:     cfg,
+		wallet:  wal,
+		authSha: authHash(cfg.Username, cfg.Password),
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+func authHash(username, password string) [sha256.Size]byte {
+	login := username + ":" + password
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	return sha256.Sum256([]byte(auth))
+}
+
+// Start begins listening for connections. It returns once the listener is
+// ready, serving requests in the background until Stop is called.
+func (s *Server) Start() error {
+	network := "tcp"
+	if len(s.cfg.Listen) > 0 && s.cfg.Listen[0] == '/' {
+		network = "unix"
+	}
+
+	listener, err := net.Listen(network, s.cfg.Listen)
+	if err != nil {
+		return err
+	}
+
+	s.httpServer = &http.Server{
+		Handler:     s,
+		ReadTimeout: 10 * time.Second,
+	}
+
+	go s.drainSync()
+	go func() {
+		log.Infof("JSON-RPC server listening on %s", listener.Addr())
+		err := s.httpServer.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("JSON-RPC server stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop shuts the server down, waiting for in-flight requests to complete.
+func (s *Server) Stop() {
+	close(s.quit)
+	if s.httpServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		log.Errorf("Error stopping JSON-RPC server: %v", err)
+	}
+}
+
+// drainSync logs sync progress. The wallet listener blocks on Sync when
+// nothing reads it, which would stall the multiwallet.
+func (s *Server) drainSync() {
+	for {
+		select {
+		case update := <-s.wallet.Sync:
+			switch update.Stage {
+			case wallet.SyncStarted:
+				log.Info("Sync started")
+			case wallet.SyncCompleted:
+				log.Info("Sync completed")
+			case wallet.SyncCanceled:
+				log.Info("Sync canceled")
+			case wallet.BlockAttached:
+				log.Debugf("Block attached: %d", update.BlockInfo.Height)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Server) checkAuth(r *http.Request) bool {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return false
+	}
+
+	authSha := sha256.Sum256([]byte(authHeader))
+	return subtle.ConstantTimeCompare(authSha[:], s.authSha[:]) == 1
+}
+
+// ServeHTTP handles a single JSON-RPC request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.checkAuth(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="godcr RPC"`)
+		http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "400 bad request", http.StatusBadRequest)
+		return
+	}
+
+	resp := Response{JSONRPC: "2.0"}
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp.Error = &Error{Code: ErrCodeParse, Message: err.Error()}
+		writeResponse(w, resp)
+		return
+	}
+	resp.ID = req.ID
+
+	result, rpcErr := s.handle(r.Context(), &req)
+	if rpcErr != nil {
+		resp.Error = rpcErr
+	} else {
+		resp.Result = result
+	}
+	writeResponse(w, resp)
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf("Failed to write JSON-RPC response: %v", err)
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req *Request) (interface{}, *Error) {
+	if req.Method == "" {
+		return nil, &Error{Code: ErrCodeInvalidRequest, Message: "method is required"}
+	}
+
+	handler, ok := handlers[req.Method]
+	if !ok {
+		return nil, &Error{Code: ErrCodeMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+
+	log.Debugf("Received JSON-RPC request: %s", req.Method)
+	result, err := handler(ctx, s, req.Params)
+	if err != nil {
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		return nil, &Error{Code: ErrCodeWallet, Message: err.Error()}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package rpcserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/rpcserver"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const (
+	testnet  = "testnet3"
+	rpcUser  = "user"
+	rpcPass  = "pass"
+	walletPW = "password"
+)
+
+var (
+	testDir string
+	wal     *wallet.Wallet
+	ts      *httptest.Server
+)
+
+func call(user, pass, method string, params interface{}) (*http.Response, Response) {
+	p, err := json.Marshal(params)
+	Expect(err).To(BeNil())
+	body, err := json.Marshal(Request{JSONRPC: "2.0", ID: 1, Method: method, Params: p})
+	Expect(err).To(BeNil())
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(body))
+	Expect(err).To(BeNil())
+	req.SetBasicAuth(user, pass)
+
+	httpResp, err := http.DefaultClient.Do(req)
+	Expect(err).To(BeNil())
+	defer httpResp.Body.Close()
+
+	var resp Response
+	if httpResp.StatusCode == http.StatusOK {
+		Expect(json.NewDecoder(httpResp.Body).Decode(&resp)).To(Succeed())
+	}
+	return httpResp, resp
+}
+
+func result(method string, params interface{}, target interface{}) {
+	_, resp := call(rpcUser, rpcPass, method, params)
+	Expect(resp.Error).To(BeNil())
+	b, err := json.Marshal(resp.Result)
+	Expect(err).To(BeNil())
+	Expect(json.Unmarshal(b, target)).To(Succeed())
+}
+
+var _ = BeforeSuite(func() {
+	var err error
+	testDir, err = ioutil.TempDir("", "godcr_rpc_test")
+	Expect(err).To(BeNil())
+
+	wal, err = wallet.NewWallet(testDir, testnet, make(chan wallet.Response, 3), 2)
+	Expect(err).To(BeNil())
+	_, err = wal.LoadWalletsCtx(context.Background())
+	Expect(err).To(BeNil())
+	_, err = wal.CreateWalletCtx(context.Background(), "rpc", walletPW)
+	Expect(err).To(BeNil())
+
+	server, err := New(Config{Username: rpcUser, Password: rpcPass}, wal)
+	Expect(err).To(BeNil())
+	ts = httptest.NewServer(server)
+})
+
+var _ = AfterSuite(func() {
+	ts.Close()
+	wal.Shutdown()
+	os.RemoveAll(testDir)
+})
+
+var _ = Describe("Server", func() {
+	It("requires credentials", func() {
+		_, err := New(Config{}, wal)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("rejects unauthenticated requests", func() {
+		httpResp, _ := call(rpcUser, "wrong", "getmultiwalletinfo", nil)
+		Expect(httpResp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("reports unknown methods", func() {
+		_, resp := call(rpcUser, rpcPass, "nosuchmethod", nil)
+		Expect(resp.Error).ToNot(BeNil())
+		Expect(resp.Error.Code).To(Equal(ErrCodeMethodNotFound))
+	})
+
+	It("reports invalid params", func() {
+		_, resp := call(rpcUser, rpcPass, "nextaddress", "not an object")
+		Expect(resp.Error).ToNot(BeNil())
+		Expect(resp.Error.Code).To(Equal(ErrCodeInvalidParams))
+	})
+
+	It("can get the multi wallet info", func() {
+		var info wallet.MultiWalletInfo
+		result("getmultiwalletinfo", nil, &info)
+		Expect(info.LoadedWallets).To(Equal(1))
+		Expect(info.Wallets[0].Name).To(Equal("rpc"))
+	})
+
+	It("can create and validate an address", func() {
+		var addr string
+		result("nextaddress", map[string]interface{}{"walletid": 1, "account": 0}, &addr)
+		Expect(addr).ToNot(BeEmpty())
+
+		var validation struct {
+			IsValid bool `json:"isvalid"`
+			IsMine  bool `json:"ismine"`
+		}
+		result("validateaddress", map[string]string{"address": addr}, &validation)
+		Expect(validation.IsValid).To(BeTrue())
+		Expect(validation.IsMine).To(BeTrue())
+	})
+
+	It("reports signing failures", func() {
+		var addr string
+		result("currentaddress", map[string]interface{}{"walletid": 1, "account": 0}, &addr)
+
+		_, resp := call(rpcUser, rpcPass, "signmessage", map[string]interface{}{
+			"walletid": 1, "passphrase": "wrong", "address": addr, "message": "godcr",
+		})
+		Expect(resp.Error).ToNot(BeNil())
+		Expect(resp.Error.Code).To(Equal(ErrCodeWallet))
+
+		_, resp = call(rpcUser, rpcPass, "signmessage", map[string]interface{}{
+			"walletid": 99, "passphrase": walletPW, "address": addr, "message": "godcr",
+		})
+		Expect(resp.Error).ToNot(BeNil())
+		Expect(resp.Error.Message).To(Equal(wallet.ErrIDNotExist.Error()))
+	})
+})
@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const PageAgendas = "Agendas"
+
+// agendaItem holds the widgets of an agenda.
+type agendaItem struct {
+	choice *widget.Enum
+	update decredmaterial.Button
+}
+
+type agendasPage struct {
+	th             *decredmaterial.Theme
+	common         *pageCommon
+	backButton     decredmaterial.IconButton
+	list           layout.List
+	walletDropDown *decredmaterial.DropDown
+	wallets        []*dcrlibwallet.Wallet
+
+	walletID int
+	agendas  *wallet.Agendas
+	items    []agendaItem
+}
+
+func AgendasPage(c *pageCommon) Page {
+	pg := &agendasPage{
+		th:      c.theme,
+		common:  c,
+		list:    layout.List{Axis: layout.Vertical},
+		wallets: c.multiWallet.AllWallets(),
+	}
+	pg.backButton, _ = c.SubPageHeaderButtons()
+	return pg
+}
+
+func (pg *agendasPage) OnResume() {
+	pg.wallets = pg.common.multiWallet.AllWallets()
+	pg.common.createOrUpdateWalletDropDown(&pg.walletDropDown, pg.wallets)
+	pg.loadAgendas()
+}
+
+func (pg *agendasPage) loadAgendas() {
+	pg.walletID = pg.wallets[pg.walletDropDown.SelectedIndex()].ID
+	agendas, err := pg.common.wallet.Agendas(pg.walletID)
+	if err != nil {
+		pg.common.notify(err.Error(), false)
+		return
+	}
+
+	pg.agendas = agendas
+	pg.items = make([]agendaItem, len(agendas.Agendas))
+	for i, agenda := range agendas.Agendas {
+		pg.items[i] = agendaItem{
+			choice: &widget.Enum{Value: agenda.Choice},
+			update: pg.th.Button(new(widget.Clickable), "Update"),
+		}
+		pg.items[i].update.TextSize = values.TextSize12
+	}
+}
+
+func (pg *agendasPage) setVoteChoice(agenda wallet.Agenda, choice string) {
+	c := pg.common
+	walletID := pg.walletID
+	newPasswordModal(c).
+		title(fmt.Sprintf("Vote %s on %s", choice, agenda.ID)).
+		negativeButton("Cancel", func() {}).
+		positiveButton("Confirm", func(password string, pm *passwordModal) bool {
+			id := c.wallet.SetVoteChoice(walletID, agenda.ID, choice, []byte(password))
+			c.onResponse(id, func(resp wallet.Response) {
+				pm.setLoading(false)
+				if resp.Err != nil {
+					if resp.Err.Error() == dcrlibwallet.ErrInvalidPassphrase {
+						pm.setError(translateErr(resp.Err))
+					} else {
+						pm.setError(resp.Err.Error())
+					}
+					return
+				}
+				pm.Dismiss()
+
+				set := resp.Resp.(*wallet.VoteChoiceSet)
+				if len(set.Failed) > 0 {
+					c.notify(fmt.Sprintf("Vote choice saved, %d VSP tickets updated, %d failed", set.Updated, len(set.Failed)), false)
+				} else {
+					c.notify(fmt.Sprintf("Vote choice saved, %d VSP tickets updated", set.Updated), true)
+				}
+				if walletID == pg.walletID {
+					pg.loadAgendas()
+				}
+			})
+			return false
+		}).Show()
+}
+
+func (pg *agendasPage) Handle() {
+	if pg.agendas == nil {
+		return
+	}
+	if pg.wallets[pg.walletDropDown.SelectedIndex()].ID != pg.walletID {
+		pg.loadAgendas()
+		return
+	}
+
+	for i, agenda := range pg.agendas.Agendas {
+		item := pg.items[i]
+		if item.update.Button.Clicked() && item.choice.Value != agenda.Choice {
+			pg.setVoteChoice(agenda, item.choice.Value)
+		}
+	}
+}
+
+func (pg *agendasPage) OnClose() {}
+
+func (pg *agendasPage) Layout(gtx layout.Context) layout.Dimensions {
+	c := pg.common
+	c.createOrUpdateWalletDropDown(&pg.walletDropDown, pg.wallets)
+	body := func(gtx C) D {
+		page := SubPage{
+			title:      "Consensus changes",
+			backButton: pg.backButton,
+			back: func() {
+				c.changePage(PageTickets)
+			},
+			body: func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(pg.walletDropDown.Layout),
+					layout.Rigid(func(gtx C) D {
+						if pg.agendas == nil || len(pg.agendas.Agendas) == 0 {
+							txt := c.theme.Body1("No agendas to vote on")
+							txt.Color = c.theme.Color.Gray2
+							return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, txt.Layout)
+						}
+						return pg.list.Layout(gtx, len(pg.agendas.Agendas), func(gtx C, i int) D {
+							return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+								return c.theme.Card().Layout(gtx, func(gtx C) D {
+									gtx.Constraints.Min.X = gtx.Constraints.Max.X
+									return layout.UniformInset(values.MarginPadding16).Layout(gtx, func(gtx C) D {
+										return pg.agendaLayout(gtx, pg.agendas.Agendas[i], pg.items[i])
+									})
+								})
+							})
+						})
+					}),
+				)
+			},
+		}
+		return c.SubPageLayout(gtx, page)
+	}
+	return c.UniformPadding(gtx, body)
+}
+
+func (pg *agendasPage) agendaLayout(gtx layout.Context, agenda wallet.Agenda, item agendaItem) layout.Dimensions {
+	status := agenda.Status(time.Now())
+	statusLabel := pg.th.Label(values.TextSize14, strings.Title(status))
+	switch status {
+	case wallet.AgendaVoting:
+		statusLabel.Color = pg.th.Color.Success
+	case wallet.AgendaUpcoming:
+		statusLabel.Color = pg.th.Color.Primary
+	default:
+		statusLabel.Color = pg.th.Color.Gray
+	}
+
+	rows := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			title := pg.th.Label(values.TextSize16, agenda.ID)
+			title.Font.Weight = text.Bold
+			return endToEndRow(gtx, title.Layout, statusLabel.Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, pg.th.Body2(agenda.Description).Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			txt := pg.th.Caption(fmt.Sprintf("Version %d, voting %s to %s", agenda.Version,
+				agenda.StartTime.Format("Jan 2, 2006"), agenda.ExpireTime.Format("Jan 2, 2006")))
+			txt.Color = pg.th.Color.Gray
+			return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, txt.Layout)
+		}),
+	}
+	for _, choice := range agenda.Choices {
+		label := choice.ID
+		if choice.Description != "" {
+			label = fmt.Sprintf("%s: %s", choice.ID, choice.Description)
+		}
+		rb := pg.th.RadioButton(item.choice, choice.ID, label)
+		rows = append(rows, layout.Rigid(rb.Layout))
+	}
+	rows = append(rows, layout.Rigid(func(gtx C) D {
+		if item.choice.Value == agenda.Choice {
+			return layout.Dimensions{}
+		}
+		return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+			return layout.E.Layout(gtx, item.update.Layout)
+		})
+	}))
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
@@ -0,0 +1,93 @@
+package decredmaterial
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+)
+
+// Bar is a single column of a BarChart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart draws values as columns with a label under each one. Negative
+// values are drawn as empty columns.
+type BarChart struct {
+	Bars   []Bar
+	Color  color.NRGBA
+	Height unit.Value
+
+	trackColor color.NRGBA
+	label      Label
+}
+
+const barChartRadius = 2
+
+func (t *Theme) BarChart(bars []Bar) BarChart {
+	bc := BarChart{
+		Bars:       bars,
+		Color:      t.Color.Primary,
+		Height:     unit.Dp(80),
+		trackColor: t.Color.Gray1,
+		label:      t.Caption(""),
+	}
+	bc.label.Color = t.Color.Gray
+	bc.label.Alignment = text.Middle
+	return bc
+}
+
+func (b BarChart) Layout(gtx layout.Context) layout.Dimensions {
+	var max float64
+	for _, bar := range b.Bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+
+	columns := make([]layout.FlexChild, len(b.Bars))
+	for i := range b.Bars {
+		bar := b.Bars[i]
+		columns[i] = layout.Flexed(1, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return b.column(gtx, bar.Value, max)
+				}),
+				layout.Rigid(func(gtx C) D {
+					label := b.label
+					label.Text = bar.Label
+					return label.Layout(gtx)
+				}),
+			)
+		})
+	}
+	return layout.Flex{}.Layout(gtx, columns...)
+}
+
+func (b BarChart) column(gtx C, value, max float64) D {
+	height := gtx.Px(b.Height)
+	width := gtx.Constraints.Max.X * 3 / 5
+	rr := float32(gtx.Px(unit.Dp(barChartRadius)))
+
+	fill := func(top int, col color.NRGBA) {
+		rect := f32.Rect(0, float32(top), float32(width), float32(height))
+		st := op.Save(gtx.Ops)
+		clip.UniformRRect(rect, rr).Add(gtx.Ops)
+		paint.ColorOp{Color: col}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		st.Load()
+	}
+	fill(0, b.trackColor)
+	if max > 0 && value > 0 {
+		fill(height-int(float64(height)*value/max), b.Color)
+	}
+	return layout.Dimensions{Size: image.Point{X: width, Y: height}}
+}
@@ -54,12 +54,14 @@ func (mp *mainPage) OnNewProposal(proposal *dcrlibwallet.Proposal) {
 }
 
 func (mp *mainPage) OnProposalVoteStarted(proposal *dcrlibwallet.Proposal) {
+	mp.wallet.NotifyProposalVote(proposal, false)
 	mp.notificationsUpdate <- wallet.Proposal{
 		ProposalStatus: wallet.VoteStarted,
 		Proposal:       proposal,
 	}
 }
 func (mp *mainPage) OnProposalVoteFinished(proposal *dcrlibwallet.Proposal) {
+	mp.wallet.NotifyProposalVote(proposal, true)
 	mp.notificationsUpdate <- wallet.Proposal{
 		ProposalStatus: wallet.VoteFinished,
 		Proposal:       proposal,
@@ -24,10 +24,6 @@ import (
 	"golang.org/x/text/message"
 )
 
-type DCRUSDTBittrex struct {
-	LastTradeRate string
-}
-
 type Toast struct {
 	text    string
 	success bool
@@ -75,10 +71,9 @@ type Load struct {
 	Printer  *message.Printer
 	Network  string
 
-	Icons          Icons
-	Page           *string
-	ReturnPage     *string
-	DcrUsdtBittrex *DCRUSDTBittrex
+	Icons      Icons
+	Page       *string
+	ReturnPage *string
 
 	Toast *Toast
 
@@ -88,6 +83,7 @@ type Load struct {
 
 	ToggleSync       func()
 	RefreshWindow    func()
+	OnResponse       func(id wallet.RequestID, handler func(wallet.Response))
 	ShowModal        func(Modal)
 	DismissModal     func(Modal)
 	ChangeWindowPage func(page Page, keepBackStack bool)
@@ -202,7 +198,6 @@ func NewLoad(th *decredmaterial.Theme, decredIcons map[string]image.Image) *Load
 
 		Printer: message.NewPrinter(language.English),
 	}
-	fetchExchangeValue(l.DcrUsdtBittrex)
 
 	return l
 }
@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"strconv"
-
 	"gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget"
@@ -13,6 +11,7 @@ import (
 	"github.com/planetdecred/godcr/ui/load"
 	"github.com/planetdecred/godcr/ui/page"
 	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
 )
 
 const PageMain = "Main"
@@ -156,23 +155,37 @@ func (mp *mainPage) OnResume() {
 
 func (mp *mainPage) updateBalance() {
 	currencyExchangeValue := mp.wallet.ReadStringConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey)
-	mp.usdExchangeSet = currencyExchangeValue == page.USDExchangeValue
+	_, _, mp.usdExchangeSet = wallet.ParseCurrencyConversion(currencyExchangeValue)
 
 	totalBalance, err := mp.calculateTotalWalletsBalance()
 	if err == nil {
 		mp.totalBalance = totalBalance
 
-		if mp.usdExchangeSet && mp.dcrUsdtBittrex.LastTradeRate != "" {
-			usdExchangeRate, err := strconv.ParseFloat(mp.dcrUsdtBittrex.LastTradeRate, 64)
-			if err == nil {
-				balanceInUSD := totalBalance.ToCoin() * usdExchangeRate
-				mp.totalBalanceUSD = formatUSDBalance(mp.printer, balanceInUSD)
-			}
+		if mp.usdExchangeSet {
+			mp.updateFiatBalance(totalBalance)
 		}
-
 	}
 }
 
+// updateFiatBalance values totalBalance in the currency selected for currency
+// conversion. The rate is fetched in the background as it may hit the network
+// and the balance is updated from the window loop once it arrives.
+func (mp *mainPage) updateFiatBalance(totalBalance dcrutil.Amount) {
+	id := mp.wallet.ExchangeRate()
+	mp.onResponse(id, func(resp wallet.Response) {
+		if resp.Err != nil {
+			mp.totalBalanceUSD = ""
+			return
+		}
+
+		rate := resp.Resp.(*wallet.ExchangeRate)
+		mp.totalBalanceUSD = page.FormatFiatBalance(mp.printer, totalBalance.ToCoin()*rate.Rate, rate.Currency)
+		if rate.Stale {
+			mp.totalBalanceUSD += " " + values.String(values.StrStale)
+		}
+	})
+}
+
 func (mp *mainPage) calculateTotalWalletsBalance() (dcrutil.Amount, error) {
 	totalBalance := int64(0)
 	for _, wallet := range mp.sortedWalletList() {
@@ -424,7 +437,7 @@ func (mp *mainPage) layoutTopBar(gtx layout.Context) layout.Dimensions {
 func (mp *mainPage) layoutUSDBalance(gtx layout.Context) layout.Dimensions {
 	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 		layout.Rigid(func(gtx C) D {
-			if mp.usdExchangeSet && mp.dcrUsdtBittrex.LastTradeRate != "" {
+			if mp.usdExchangeSet && mp.totalBalanceUSD != "" {
 				inset := layout.Inset{
 					Top:  values.MarginPadding3,
 					Left: values.MarginPadding8,
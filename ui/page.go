@@ -1,10 +1,8 @@
 package ui
 
 import (
-	"encoding/json"
 	"image"
 	"image/color"
-	"net/http"
 	"sort"
 
 	"golang.org/x/exp/shiny/materialdesign/icons"
@@ -73,10 +71,6 @@ type walletAccount struct {
 	number       int32
 }
 
-type DCRUSDTBittrex struct {
-	LastTradeRate string
-}
-
 type pageCommon struct {
 	printer             *message.Printer
 	multiWallet         *dcrlibwallet.MultiWallet
@@ -91,7 +85,6 @@ type pageCommon struct {
 	icons               pageIcons
 	page                *string
 	returnPage          *string
-	dcrUsdtBittrex      DCRUSDTBittrex
 	navTab              *decredmaterial.Tabs
 	keyEvents           chan *key.Event
 	toast               **toast
@@ -109,10 +102,13 @@ type pageCommon struct {
 	showModal           func(Modal)
 	dismissModal        func(Modal)
 	toggleSync          func()
+	onResponse          func(wallet.RequestID, func(wallet.Response))
 
 	testButton decredmaterial.Button
 
 	selectedUTXO map[int]map[int32]map[string]*wallet.UnspentOutput
+	// utxoAccount is the account whose outputs are listed on the UTXO page.
+	utxoAccount *dcrlibwallet.Account
 
 	refreshWindow    func()
 	changeWindowPage func(Page, bool)
@@ -234,16 +230,13 @@ func (win *Window) newPageCommon(decredIcons map[string]image.Image) *pageCommon
 		changeWindowPage: win.changePage,
 		popWindowPage:    win.popPage,
 		refreshWindow:    win.refreshWindow,
+		onResponse:       win.onResponse,
 
 		selectedUTXO: make(map[int]map[int32]map[string]*wallet.UnspentOutput),
 		toast:        &win.toast,
 		internalLog:  &win.internalLog,
 	}
 
-	if common.fetchExchangeValue(&common.dcrUsdtBittrex) != nil {
-		log.Info("Error fetching exchange value")
-	}
-
 	return common
 }
 
@@ -260,6 +253,8 @@ func loadPages(common *pageCommon, l *load.Load) map[string]Page {
 	pages[page.SeedBackupPageID] = page.NewBackupPage(l)
 	pages[page.SettingsPageID] = page.NewSettingsPage(l)
 	pages[page.SecurityToolsPageID] = page.NewSecurityToolsPage(l)
+	pages[page.OfflineSigningPageID] = page.NewOfflineSigningPage(l)
+	pages[page.AddressBookPageID] = page.NewAddressBookPage(l)
 	pages[page.DebugPageID] = page.NewDebugPage(l)
 	pages[page.LogPageID] = page.NewLogPage(l)
 	pages[page.StatisticsPageID] = page.NewStatPage(l)
@@ -270,6 +265,9 @@ func loadPages(common *pageCommon, l *load.Load) map[string]Page {
 	pages[page.ValidateAddressPageID] = page.NewValidateAddressPage(l)
 	pages[PageTicketsList] = TicketPageList(common)
 	pages[PageTicketsActivity] = TicketActivityPage(common)
+	pages[PageVSPs] = VSPPage(common)
+	pages[PageAgendas] = AgendasPage(common)
+	pages[PageTreasury] = TreasuryPage(common)
 
 	return pages
 }
@@ -281,23 +279,6 @@ func (common *pageCommon) refreshTheme() {
 	}
 }
 
-func (common *pageCommon) fetchExchangeValue(target interface{}) error {
-	url := "https://api.bittrex.com/v3/markets/DCR-USDT/ticker"
-	res, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-
-	defer res.Body.Close()
-
-	err = json.NewDecoder(res.Body).Decode(target)
-	if err != nil {
-		log.Error(err)
-	}
-
-	return nil
-}
-
 func (common *pageCommon) notify(text string, success bool) {
 	*common.toast = &toast{
 		text:    text,
@@ -37,6 +37,7 @@ type AcctDetailsPage struct {
 	immatureStakeGen string
 	hdPath           string
 	keys             string
+	fiatValue        string
 }
 
 func NewAcctDetailsPage(l *load.Load, account *dcrlibwallet.Account) *AcctDetailsPage {
@@ -78,6 +79,11 @@ func (pg *AcctDetailsPage) OnResume() {
 	internal := pg.account.InternalKeyCount
 	imp := pg.account.ImportedKeyCount
 	pg.keys = fmt.Sprintf("%d external, %d internal, %d imported", ext, internal, imp)
+
+	pg.fiatValue = ""
+	if value, currency, day, ok := pg.WL.Wallet.LatestFiatValue(balance.Total); ok {
+		pg.fiatValue = fmt.Sprintf("%s on %s", FormatFiatBalance(pg.Printer, value, currency), day.Format("Jan 2, 2006"))
+	}
 }
 
 func (pg *AcctDetailsPage) Layout(gtx layout.Context) layout.Dimensions {
@@ -242,6 +248,14 @@ func (pg *AcctDetailsPage) accountInfoLayout(gtx layout.Context) layout.Dimensio
 					return pg.acctInfoLayout(gtx, "Keys", pg.keys)
 				})
 			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.fiatValue == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Bottom: m}.Layout(gtx, func(gtx C) D {
+					return pg.acctInfoLayout(gtx, "Balance Value", pg.fiatValue)
+				})
+			}),
 		)
 	})
 }
@@ -0,0 +1,349 @@
+package page
+
+import (
+	"fmt"
+	"image/color"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/load"
+	"github.com/planetdecred/godcr/ui/modal"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const AddressBookPageID = "AddressBook"
+
+type contactItem struct {
+	contact wallet.Contact
+	edit    *widget.Clickable
+	delete  decredmaterial.IconButton
+}
+
+type AddressBookPage struct {
+	*load.Load
+	wallet        *wallet.Wallet
+	pageContainer layout.List
+
+	labelEditor   decredmaterial.Editor
+	addressEditor decredmaterial.Editor
+	notesEditor   decredmaterial.Editor
+
+	saveBtn, clearBtn    decredmaterial.Button
+	importBtn, exportBtn decredmaterial.Button
+	contacts             []contactItem
+	addressWarning       string
+
+	backButton decredmaterial.IconButton
+}
+
+func NewAddressBookPage(l *load.Load) *AddressBookPage {
+	pg := &AddressBookPage{
+		Load:          l,
+		wallet:        l.WL.Wallet,
+		pageContainer: layout.List{Axis: layout.Vertical},
+
+		saveBtn:   l.Theme.Button(new(widget.Clickable), "Save"),
+		clearBtn:  l.Theme.Button(new(widget.Clickable), "Clear"),
+		importBtn: l.Theme.Button(new(widget.Clickable), "Import"),
+		exportBtn: l.Theme.Button(new(widget.Clickable), "Export"),
+	}
+
+	pg.backButton, _ = subpageHeaderButtons(l)
+
+	pg.labelEditor = l.Theme.Editor(new(widget.Editor), "Label")
+	pg.labelEditor.Editor.SingleLine = true
+	pg.addressEditor = l.Theme.Editor(new(widget.Editor), "Address")
+	pg.addressEditor.Editor.SingleLine = true
+	pg.notesEditor = l.Theme.Editor(new(widget.Editor), "Notes")
+	pg.notesEditor.IsRequired = false
+
+	for _, btn := range []*decredmaterial.Button{&pg.saveBtn, &pg.clearBtn, &pg.importBtn, &pg.exportBtn} {
+		btn.TextSize = values.TextSize14
+		btn.Font.Weight = text.Bold
+	}
+	for _, btn := range []*decredmaterial.Button{&pg.clearBtn, &pg.importBtn, &pg.exportBtn} {
+		btn.Color = pg.Theme.Color.Primary
+		btn.Background = color.NRGBA{}
+	}
+
+	return pg
+}
+
+func (pg *AddressBookPage) OnResume() {
+	pg.loadContacts()
+}
+
+func (pg *AddressBookPage) loadContacts() {
+	contacts := pg.wallet.Contacts()
+	pg.contacts = make([]contactItem, len(contacts))
+	for i, c := range contacts {
+		deleteButton := pg.Theme.PlainIconButton(new(widget.Clickable), pg.Icons.ContentClear)
+		deleteButton.Color = pg.Theme.Color.Gray3
+		deleteButton.Size = values.MarginPadding20
+		deleteButton.Inset = layout.UniformInset(values.MarginPadding0)
+		pg.contacts[i] = contactItem{
+			contact: c,
+			edit:    new(widget.Clickable),
+			delete:  deleteButton,
+		}
+	}
+}
+
+func (pg *AddressBookPage) Layout(gtx layout.Context) layout.Dimensions {
+	body := func(gtx C) D {
+		sp := SubPage{
+			Load:       pg.Load,
+			title:      "Address Book",
+			backButton: pg.backButton,
+			back: func() {
+				pg.ChangePage(MorePageID)
+			},
+			body: func(gtx C) D {
+				sections := []layout.Widget{pg.contactForm, pg.contactList}
+				return pg.pageContainer.Layout(gtx, len(sections), func(gtx C, i int) D {
+					return sections[i](gtx)
+				})
+			},
+		}
+		return sp.Layout(gtx)
+	}
+	return uniformPadding(gtx, body)
+}
+
+func (pg *AddressBookPage) contactForm(gtx layout.Context) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(pg.labelEditor.Layout),
+			layout.Rigid(pg.addressEditor.Layout),
+			layout.Rigid(func(gtx C) D {
+				if pg.addressWarning == "" {
+					return layout.Dimensions{}
+				}
+				txt := pg.Theme.Caption(pg.addressWarning)
+				txt.Color = pg.Theme.Color.Orange
+				return txt.Layout(gtx)
+			}),
+			layout.Rigid(pg.notesEditor.Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{}.Layout(gtx,
+						layout.Rigid(pg.importBtn.Layout),
+						layout.Rigid(pg.exportBtn.Layout),
+						layout.Flexed(1, func(gtx C) D {
+							return layout.E.Layout(gtx, func(gtx C) D {
+								return layout.Flex{}.Layout(gtx,
+									layout.Rigid(func(gtx C) D {
+										return layout.Inset{Right: values.MarginPadding10}.Layout(gtx, pg.clearBtn.Layout)
+									}),
+									layout.Rigid(pg.saveBtn.Layout),
+								)
+							})
+						}),
+					)
+				})
+			}),
+		)
+	})
+}
+
+func (pg *AddressBookPage) contactList(gtx layout.Context) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		if len(pg.contacts) == 0 {
+			txt := pg.Theme.Body1("No saved addresses yet")
+			txt.Color = pg.Theme.Color.Gray
+			return txt.Layout(gtx)
+		}
+
+		rows := make([]layout.FlexChild, 0, len(pg.contacts))
+		for i := range pg.contacts {
+			i, item := i, pg.contacts[i]
+			rows = append(rows, layout.Rigid(func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						if i == 0 {
+							return layout.Dimensions{}
+						}
+						m := values.MarginPadding10
+						return layout.Inset{Top: m, Bottom: m}.Layout(gtx, pg.Theme.Separator().Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+							layout.Flexed(1, func(gtx C) D {
+								return decredmaterial.Clickable(gtx, item.edit, func(gtx C) D {
+									gtx.Constraints.Min.X = gtx.Constraints.Max.X
+									return pg.contactRow(gtx, item.contact)
+								})
+							}),
+							layout.Rigid(item.delete.Layout),
+						)
+					}),
+				)
+			}))
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+	})
+}
+
+func (pg *AddressBookPage) contactRow(gtx layout.Context, contact wallet.Contact) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(pg.Theme.Body1(contact.Label).Layout),
+				layout.Rigid(func(gtx C) D {
+					if contact.Network == pg.wallet.Net {
+						return layout.Dimensions{}
+					}
+					return layout.Inset{Left: values.MarginPadding5}.Layout(gtx, func(gtx C) D {
+						txt := pg.Theme.Caption(contact.Network)
+						txt.Color = pg.Theme.Color.Orange
+						return txt.Layout(gtx)
+					})
+				}),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			txt := pg.Theme.Caption(contact.Address)
+			txt.Color = pg.Theme.Color.Gray
+			return txt.Layout(gtx)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if contact.Notes == "" {
+				return layout.Dimensions{}
+			}
+			return pg.Theme.Caption(contact.Notes).Layout(gtx)
+		}),
+	)
+}
+
+func (pg *AddressBookPage) pageSections(gtx layout.Context, body layout.Widget) layout.Dimensions {
+	return layout.Inset{Bottom: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+		return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.UniformInset(values.MarginPadding15).Layout(gtx, body)
+		})
+	})
+}
+
+func (pg *AddressBookPage) Handle() {
+	for range pg.addressEditor.Editor.Events() {
+		pg.checkAddress()
+	}
+
+	if pg.saveBtn.Button.Clicked() {
+		pg.saveContact()
+	}
+
+	if pg.clearBtn.Button.Clicked() {
+		pg.clearInputs()
+	}
+
+	if pg.exportBtn.Button.Clicked() {
+		path, err := pg.wallet.ExportContactsFile()
+		if err != nil {
+			pg.CreateToast(err.Error(), false)
+		} else {
+			pg.CreateToast(fmt.Sprintf("Address book exported to %s", path), true)
+		}
+	}
+
+	if pg.importBtn.Button.Clicked() {
+		pg.showImportDialog()
+	}
+
+	for _, item := range pg.contacts {
+		if item.edit.Clicked() {
+			pg.labelEditor.Editor.SetText(item.contact.Label)
+			pg.addressEditor.Editor.SetText(item.contact.Address)
+			pg.notesEditor.Editor.SetText(item.contact.Notes)
+			pg.checkAddress()
+		}
+
+		if item.delete.Button.Clicked() {
+			if err := pg.wallet.DeleteContact(item.contact.Address); err != nil {
+				pg.CreateToast(err.Error(), false)
+			}
+			pg.loadContacts()
+			break
+		}
+	}
+}
+
+// checkAddress warns when the address being saved belongs to another network
+// or to one of the loaded wallets.
+func (pg *AddressBookPage) checkAddress() {
+	pg.addressEditor.SetError("")
+	check := pg.wallet.CheckAddress(pg.addressEditor.Editor.Text())
+	switch {
+	case check.WrongNetwork:
+		pg.addressWarning = fmt.Sprintf("This is a %s address", check.Network)
+	case check.OwnWallet != "":
+		pg.addressWarning = fmt.Sprintf("This address belongs to your wallet %s", check.OwnWallet)
+	default:
+		pg.addressWarning = ""
+	}
+}
+
+func (pg *AddressBookPage) saveContact() {
+	pg.labelEditor.SetError("")
+	pg.addressEditor.SetError("")
+
+	if pg.labelEditor.Editor.Text() == "" {
+		pg.labelEditor.SetError("Please enter a label")
+		return
+	}
+	if _, ok := wallet.AddressNetwork(pg.addressEditor.Editor.Text()); !ok {
+		pg.addressEditor.SetError("Invalid address")
+		return
+	}
+
+	err := pg.wallet.SaveContact(wallet.Contact{
+		Label:   pg.labelEditor.Editor.Text(),
+		Address: pg.addressEditor.Editor.Text(),
+		Notes:   pg.notesEditor.Editor.Text(),
+	})
+	if err != nil {
+		pg.CreateToast(err.Error(), false)
+		return
+	}
+
+	pg.CreateToast("Address saved", true)
+	pg.clearInputs()
+	pg.loadContacts()
+}
+
+func (pg *AddressBookPage) showImportDialog() {
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint("JSON file path").
+		PositiveButton(values.String(values.StrImport), func(path string, tim *modal.TextInputModal) bool {
+			if path == "" {
+				return true
+			}
+			n, err := pg.wallet.ImportContactsFile(path)
+			if err != nil {
+				pg.CreateToast(err.Error(), false)
+				return true
+			}
+			pg.CreateToast(fmt.Sprintf("%d addresses imported", n), true)
+			pg.loadContacts()
+			return true
+		})
+
+	textModal.Title("Import address book").
+		NegativeButton(values.String(values.StrCancel), func() {})
+	textModal.Show()
+}
+
+func (pg *AddressBookPage) clearInputs() {
+	pg.labelEditor.Editor.SetText("")
+	pg.addressEditor.Editor.SetText("")
+	pg.notesEditor.Editor.SetText("")
+	pg.labelEditor.SetError("")
+	pg.addressEditor.SetError("")
+	pg.addressWarning = ""
+}
+
+func (pg *AddressBookPage) OnClose() {}
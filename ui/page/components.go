@@ -4,6 +4,8 @@
 package page
 
 import (
+	"strings"
+
 	"github.com/planetdecred/godcr/ui/load"
 
 	"gioui.org/layout"
@@ -13,6 +15,7 @@ import (
 	"github.com/planetdecred/dcrlibwallet"
 	"github.com/planetdecred/godcr/ui/decredmaterial"
 	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
 )
 
 type (
@@ -20,6 +23,11 @@ type (
 		transaction dcrlibwallet.Transaction
 		index       int
 		showBadge   bool
+		// fiatValue values the transaction at the time it was made. The
+		// value is not shown if it is nil.
+		fiatValue wallet.FiatValueFunc
+		// note is the user's note and tags on the transaction.
+		note wallet.TxNote
 	}
 )
 
@@ -109,6 +117,26 @@ func transactionRow(gtx layout.Context, l *load.Load, row TransactionRow) layout
 											layout.Rigid(func(gtx C) D {
 												return layoutBalance(gtx, l, dcrutil.Amount(row.transaction.Amount).String(), true)
 											}),
+											layout.Rigid(func(gtx C) D {
+												if row.fiatValue == nil {
+													return layout.Dimensions{}
+												}
+												value, currency, ok := row.fiatValue(row.transaction.Amount, row.transaction.Timestamp)
+												if !ok {
+													return layout.Dimensions{}
+												}
+												txt := l.Theme.Caption(FormatFiatBalance(l.Printer, value, currency))
+												txt.Color = l.Theme.Color.Gray
+												return txt.Layout(gtx)
+											}),
+											layout.Rigid(func(gtx C) D {
+												if row.note.IsEmpty() {
+													return layout.Dimensions{}
+												}
+												txt := l.Theme.Caption(strings.TrimSpace(row.note.Note + " " + row.note.TagsString()))
+												txt.Color = l.Theme.Color.Gray
+												return txt.Layout(gtx)
+											}),
 											layout.Rigid(func(gtx C) D {
 												if row.showBadge {
 													return walletLabel(gtx, l, wal.Name)
@@ -35,6 +35,11 @@ func NewMorePage(l *load.Load) *MorePage {
 			image:     l.Icons.SecurityIcon,
 			page:      SecurityToolsPageID,
 		},
+		{
+			clickable: new(widget.Clickable),
+			image:     l.Icons.AccountIcon,
+			page:      AddressBookPageID,
+		},
 		{
 			clickable: new(widget.Clickable),
 			image:     l.Icons.HelpIcon,
@@ -116,6 +121,8 @@ func (pg *MorePage) layoutMoreItems(gtx layout.Context) layout.Dimensions {
 														page := pg.morePageListItems[i].page
 														if page == SecurityToolsPageID {
 															page = "Security Tools"
+														} else if page == AddressBookPageID {
+															page = "Address Book"
 														}
 														return pg.Theme.Body1(page).Layout(gtx)
 													})
@@ -0,0 +1,532 @@
+package page
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/planetdecred/dcrlibwallet"
+	qrcode "github.com/yeqown/go-qrcode"
+
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/load"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const OfflineSigningPageID = "OfflineSigning"
+
+// OfflineSigningPage builds unsigned transactions from watch-only wallets,
+// signs them with the seed of the wallet they watch on an offline machine and
+// broadcasts them back from the watch-only wallet.
+type OfflineSigningPage struct {
+	*load.Load
+	pageContainer layout.List
+
+	walletGroup   *widget.Enum
+	feeRateGroup  *widget.Enum
+	needsXpub     bool
+	changeAddress string
+
+	xpubEditor, addressEditor, amountEditor, customFeeEditor decredmaterial.Editor
+	unsignedPathEditor, seedEditor, signedPathEditor         decredmaterial.Editor
+
+	saveXpubBtn, createBtn, loadUnsignedBtn, signBtn, broadcastBtn decredmaterial.Button
+
+	unsignedTx *wallet.UnsignedTx
+	outputs    []wallet.UnsignedTxOutput
+	fee        int64
+
+	// exported is the path of the last exported transaction and qrImage its
+	// QR code, nil if the transaction is too large for one.
+	exported string
+	qrImage  *image.Image
+
+	broadcasting bool
+
+	backButton decredmaterial.IconButton
+}
+
+func NewOfflineSigningPage(l *load.Load) *OfflineSigningPage {
+	pg := &OfflineSigningPage{
+		Load:          l,
+		pageContainer: layout.List{Axis: layout.Vertical},
+		walletGroup:   new(widget.Enum),
+		feeRateGroup:  new(widget.Enum),
+
+		xpubEditor:         l.Theme.Editor(new(widget.Editor), "Extended public key"),
+		addressEditor:      l.Theme.Editor(new(widget.Editor), "Destination address"),
+		amountEditor:       l.Theme.Editor(new(widget.Editor), "Amount (DCR)"),
+		customFeeEditor:    l.Theme.Editor(new(widget.Editor), "Fee rate (atoms/kB)"),
+		unsignedPathEditor: l.Theme.Editor(new(widget.Editor), "Unsigned transaction file path"),
+		seedEditor:         l.Theme.Editor(new(widget.Editor), "Seed words or hex"),
+		signedPathEditor:   l.Theme.Editor(new(widget.Editor), "Signed transaction file path"),
+
+		saveXpubBtn:     l.Theme.Button(new(widget.Clickable), "Save key"),
+		createBtn:       l.Theme.Button(new(widget.Clickable), "Create unsigned transaction"),
+		loadUnsignedBtn: l.Theme.Button(new(widget.Clickable), "Load"),
+		signBtn:         l.Theme.Button(new(widget.Clickable), "Sign"),
+		broadcastBtn:    l.Theme.Button(new(widget.Clickable), "Broadcast"),
+	}
+
+	for _, e := range []*decredmaterial.Editor{&pg.xpubEditor, &pg.addressEditor, &pg.amountEditor,
+		&pg.customFeeEditor, &pg.unsignedPathEditor, &pg.signedPathEditor} {
+		e.Editor.SingleLine = true
+	}
+	for _, btn := range []*decredmaterial.Button{&pg.saveXpubBtn, &pg.createBtn, &pg.loadUnsignedBtn, &pg.signBtn, &pg.broadcastBtn} {
+		btn.TextSize = values.TextSize14
+		btn.Font.Weight = text.Bold
+	}
+	pg.loadUnsignedBtn.Color = pg.Theme.Color.Primary
+	pg.loadUnsignedBtn.Background = color.NRGBA{}
+
+	pg.backButton, _ = subpageHeaderButtons(l)
+
+	return pg
+}
+
+func (pg *OfflineSigningPage) OnResume() {
+	wallets := pg.watchOnlyWallets()
+	if len(wallets) > 0 && pg.walletID() == -1 {
+		pg.walletGroup.Value = strconv.Itoa(wallets[0].ID)
+	}
+	pg.walletChanged()
+
+	rate := pg.WL.Wallet.UnsignedTxFeeRate()
+	pg.feeRateGroup.Value = string(rate.Preset)
+	if rate.Preset == wallet.FeeRateCustom {
+		pg.customFeeEditor.Editor.SetText(strconv.FormatInt(rate.AtomsPerKB, 10))
+	}
+}
+
+func (pg *OfflineSigningPage) watchOnlyWallets() []*dcrlibwallet.Wallet {
+	var wallets []*dcrlibwallet.Wallet
+	for _, w := range pg.WL.SortedWalletList() {
+		if w.IsWatchingOnlyWallet() {
+			wallets = append(wallets, w)
+		}
+	}
+	return wallets
+}
+
+// walletID returns the ID of the selected watch-only wallet, or -1.
+func (pg *OfflineSigningPage) walletID() int {
+	id, err := strconv.Atoi(pg.walletGroup.Value)
+	if err != nil || pg.WL.MultiWallet.WalletWithID(id) == nil {
+		return -1
+	}
+	return id
+}
+
+// walletChanged loads the change address of the selected wallet, or asks for
+// its extended public key if the wallet was imported before it was saved.
+func (pg *OfflineSigningPage) walletChanged() {
+	pg.needsXpub, pg.changeAddress = false, ""
+	id := pg.walletID()
+	if id == -1 {
+		return
+	}
+	address, err := pg.WL.Wallet.ChangeAddress(id, 0)
+	switch {
+	case errors.Is(err, wallet.ErrNoAccountXpub):
+		pg.needsXpub = true
+	case err != nil:
+		log.Error("Error deriving change address:", err)
+	default:
+		pg.changeAddress = address
+	}
+}
+
+func (pg *OfflineSigningPage) Layout(gtx layout.Context) layout.Dimensions {
+	body := func(gtx C) D {
+		sp := SubPage{
+			Load:       pg.Load,
+			title:      "Offline Signing",
+			backButton: pg.backButton,
+			back: func() {
+				pg.clearSeed()
+				pg.ChangePage(*pg.ReturnPage)
+			},
+			body: func(gtx C) D {
+				sections := []layout.Widget{pg.createSection, pg.signSection, pg.broadcastSection, pg.exportedSection}
+				return pg.pageContainer.Layout(gtx, len(sections), func(gtx C, i int) D {
+					return sections[i](gtx)
+				})
+			},
+		}
+		return sp.Layout(gtx)
+	}
+	return uniformPadding(gtx, body)
+}
+
+func (pg *OfflineSigningPage) createSection(gtx layout.Context) layout.Dimensions {
+	return pg.pageSections(gtx, "1. Create on the watch-only wallet", func(gtx C) D {
+		wallets := pg.watchOnlyWallets()
+		if len(wallets) == 0 {
+			return pg.hint("Import a watch-only wallet to create transactions for offline signing.")(gtx)
+		}
+
+		children := []layout.FlexChild{layout.Rigid(pg.walletSelector(wallets))}
+		if pg.needsXpub {
+			children = append(children,
+				layout.Rigid(pg.hint("Enter the extended public key this wallet was imported with.")),
+				layout.Rigid(pg.xpubEditor.Layout),
+				layout.Rigid(pg.rightAligned(pg.saveXpubBtn.Layout)),
+			)
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		}
+
+		children = append(children,
+			layout.Rigid(pg.addressEditor.Layout),
+			layout.Rigid(pg.amountEditor.Layout),
+			layout.Rigid(pg.feeRateSelector),
+			layout.Rigid(func(gtx C) D {
+				if pg.changeAddress == "" {
+					return layout.Dimensions{}
+				}
+				return pg.hint("Change goes to " + pg.changeAddress)(gtx)
+			}),
+			layout.Rigid(pg.rightAligned(pg.createBtn.Layout)),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (pg *OfflineSigningPage) walletSelector(wallets []*dcrlibwallet.Wallet) layout.Widget {
+	return func(gtx C) D {
+		buttons := make([]layout.FlexChild, len(wallets))
+		for i, w := range wallets {
+			rb := pg.Theme.RadioButton(pg.walletGroup, strconv.Itoa(w.ID), w.Name)
+			buttons[i] = layout.Rigid(rb.Layout)
+		}
+		return layout.Flex{}.Layout(gtx, buttons...)
+	}
+}
+
+func (pg *OfflineSigningPage) feeRateSelector(gtx layout.Context) layout.Dimensions {
+	buttons := make([]layout.FlexChild, 0, 4)
+	for _, rate := range wallet.FeeRatePresets() {
+		label := fmt.Sprintf("%s (%s)", strings.Title(string(rate.Preset)), rate)
+		buttons = append(buttons, layout.Rigid(pg.Theme.RadioButton(pg.feeRateGroup, string(rate.Preset), label).Layout))
+	}
+	buttons = append(buttons, layout.Rigid(pg.Theme.RadioButton(pg.feeRateGroup, string(wallet.FeeRateCustom), "Custom").Layout))
+
+	return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(pg.Theme.Body2("Fee rate").Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(gtx, buttons...)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.feeRateGroup.Value != string(wallet.FeeRateCustom) {
+					return layout.Dimensions{}
+				}
+				return pg.customFeeEditor.Layout(gtx)
+			}),
+		)
+	})
+}
+
+func (pg *OfflineSigningPage) signSection(gtx layout.Context) layout.Dimensions {
+	return pg.pageSections(gtx, "2. Sign on the offline machine", func(gtx C) D {
+		children := []layout.FlexChild{
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, pg.unsignedPathEditor.Layout),
+					layout.Rigid(pg.loadUnsignedBtn.Layout),
+				)
+			}),
+		}
+		if pg.unsignedTx != nil {
+			for _, out := range pg.outputs {
+				format := "Pay %s to %s"
+				if out.Change {
+					format = "Change %s to %s, checked against the seed when signing"
+				}
+				children = append(children, layout.Rigid(pg.Theme.Body1(fmt.Sprintf(format,
+					dcrutil.Amount(out.Amount), out.Address)).Layout))
+			}
+			children = append(children,
+				layout.Rigid(pg.Theme.Body1(fmt.Sprintf("Fee %s, unverified: input amounts cannot be checked offline",
+					dcrutil.Amount(pg.fee))).Layout),
+				layout.Rigid(pg.seedEditor.Layout),
+				layout.Rigid(pg.rightAligned(pg.signBtn.Layout)),
+			)
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (pg *OfflineSigningPage) broadcastSection(gtx layout.Context) layout.Dimensions {
+	return pg.pageSections(gtx, "3. Broadcast from the watch-only wallet", func(gtx C) D {
+		if pg.walletID() == -1 {
+			return pg.hint("Import a watch-only wallet to broadcast signed transactions.")(gtx)
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(pg.signedPathEditor.Layout),
+			layout.Rigid(pg.rightAligned(pg.broadcastBtn.Layout)),
+		)
+	})
+}
+
+func (pg *OfflineSigningPage) exportedSection(gtx layout.Context) layout.Dimensions {
+	if pg.exported == "" {
+		return layout.Dimensions{}
+	}
+	return pg.pageSections(gtx, "Exported transaction", func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(pg.Theme.Body2(pg.exported).Layout),
+			layout.Rigid(func(gtx C) D {
+				if pg.qrImage == nil {
+					return pg.hint("The transaction is too large for a QR code, copy the file instead.")(gtx)
+				}
+				return pg.Theme.ImageIcon(gtx, *pg.qrImage, 360)
+			}),
+		)
+	})
+}
+
+func (pg *OfflineSigningPage) hint(txt string) layout.Widget {
+	return func(gtx C) D {
+		label := pg.Theme.Caption(txt)
+		label.Color = pg.Theme.Color.Gray
+		return layout.Inset{Top: values.MarginPadding5, Bottom: values.MarginPadding5}.Layout(gtx, label.Layout)
+	}
+}
+
+func (pg *OfflineSigningPage) rightAligned(w layout.Widget) layout.Widget {
+	return func(gtx C) D {
+		return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.E.Layout(gtx, w)
+		})
+	}
+}
+
+func (pg *OfflineSigningPage) pageSections(gtx layout.Context, title string, body layout.Widget) layout.Dimensions {
+	return layout.Inset{Bottom: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+		return pg.Theme.Card().Layout(gtx, func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.UniformInset(values.MarginPadding15).Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Bottom: values.MarginPadding10}.Layout(gtx, pg.Theme.Body1(title).Layout)
+					}),
+					layout.Rigid(body),
+				)
+			})
+		})
+	})
+}
+
+func (pg *OfflineSigningPage) Handle() {
+	if pg.walletGroup.Changed() {
+		pg.walletChanged()
+	}
+
+	if pg.saveXpubBtn.Button.Clicked() {
+		pg.xpubEditor.SetError("")
+		err := pg.WL.Wallet.SetAccountXpub(pg.walletID(), strings.TrimSpace(pg.xpubEditor.Editor.Text()))
+		if err != nil {
+			pg.xpubEditor.SetError(err.Error())
+			return
+		}
+		pg.xpubEditor.Editor.SetText("")
+		pg.walletChanged()
+	}
+
+	if pg.createBtn.Button.Clicked() {
+		pg.createUnsignedTx()
+	}
+
+	if pg.loadUnsignedBtn.Button.Clicked() {
+		pg.loadUnsignedTx()
+	}
+
+	if pg.signBtn.Button.Clicked() {
+		pg.signUnsignedTx()
+	}
+
+	if pg.broadcastBtn.Button.Clicked() && !pg.broadcasting {
+		pg.broadcastSignedTx()
+	}
+}
+
+// feeRate returns the selected fee rate, or false if the custom rate entered
+// is invalid.
+func (pg *OfflineSigningPage) feeRate() (wallet.FeeRate, bool) {
+	pg.customFeeEditor.SetError("")
+	var custom int64
+	preset := wallet.FeeRatePreset(pg.feeRateGroup.Value)
+	if preset == wallet.FeeRateCustom {
+		var err error
+		custom, err = strconv.ParseInt(strings.TrimSpace(pg.customFeeEditor.Editor.Text()), 10, 64)
+		if err != nil {
+			pg.customFeeEditor.SetError("Invalid fee rate")
+			return wallet.FeeRate{}, false
+		}
+	}
+	rate, err := wallet.NewFeeRate(preset, custom)
+	if err != nil {
+		pg.customFeeEditor.SetError(err.Error())
+		return wallet.FeeRate{}, false
+	}
+	return rate, true
+}
+
+func (pg *OfflineSigningPage) createUnsignedTx() {
+	pg.addressEditor.SetError("")
+	pg.amountEditor.SetError("")
+
+	address := strings.TrimSpace(pg.addressEditor.Editor.Text())
+	if valid, _ := pg.WL.Wallet.IsAddressValid(address); !valid {
+		pg.addressEditor.SetError("Invalid address")
+		return
+	}
+	dcr, err := strconv.ParseFloat(strings.TrimSpace(pg.amountEditor.Editor.Text()), 64)
+	if err != nil || dcr <= 0 {
+		pg.amountEditor.SetError("Invalid amount")
+		return
+	}
+	amount, err := dcrutil.NewAmount(dcr)
+	if err != nil {
+		pg.amountEditor.SetError("Invalid amount")
+		return
+	}
+
+	rate, ok := pg.feeRate()
+	if !ok {
+		return
+	}
+	if err := pg.WL.Wallet.SetUnsignedTxFeeRate(rate); err != nil {
+		pg.CreateToast(err.Error(), false)
+		return
+	}
+
+	recipients := []wallet.Recipient{{Address: address, Amount: int64(amount)}}
+	tx, err := pg.WL.Wallet.CreateUnsignedTx(pg.walletID(), 0, recipients)
+	if err != nil {
+		pg.CreateToast(err.Error(), false)
+		return
+	}
+	path, err := pg.WL.Wallet.ExportUnsignedTxFile(tx)
+	if err != nil {
+		pg.CreateToast(err.Error(), false)
+		return
+	}
+
+	pg.addressEditor.Editor.SetText("")
+	pg.amountEditor.Editor.SetText("")
+	pg.walletChanged()
+	pg.showExported(path, tx)
+	pg.CreateToast("Unsigned transaction exported", true)
+}
+
+func (pg *OfflineSigningPage) loadUnsignedTx() {
+	pg.unsignedPathEditor.SetError("")
+	pg.unsignedTx, pg.outputs = nil, nil
+
+	tx, err := wallet.ReadUnsignedTxFile(strings.TrimSpace(pg.unsignedPathEditor.Editor.Text()))
+	if err != nil {
+		pg.unsignedPathEditor.SetError(err.Error())
+		return
+	}
+	outputs, err := tx.Outputs()
+	if err != nil {
+		pg.unsignedPathEditor.SetError(err.Error())
+		return
+	}
+	fee, err := tx.Fee()
+	if err != nil {
+		pg.unsignedPathEditor.SetError(err.Error())
+		return
+	}
+	pg.unsignedTx, pg.outputs, pg.fee = tx, outputs, fee
+}
+
+func (pg *OfflineSigningPage) signUnsignedTx() {
+	pg.seedEditor.SetError("")
+	signed, err := wallet.SignUnsignedTx(pg.unsignedTx, strings.TrimSpace(pg.seedEditor.Editor.Text()))
+	if err != nil {
+		pg.seedEditor.SetError(err.Error())
+		return
+	}
+	pg.clearSeed()
+
+	path, err := pg.WL.Wallet.ExportSignedTxFile(signed)
+	if err != nil {
+		pg.CreateToast(err.Error(), false)
+		return
+	}
+	pg.unsignedTx, pg.outputs = nil, nil
+	pg.unsignedPathEditor.Editor.SetText("")
+	pg.showExported(path, signed)
+	pg.CreateToast("Signed transaction exported", true)
+}
+
+func (pg *OfflineSigningPage) broadcastSignedTx() {
+	pg.signedPathEditor.SetError("")
+	signed, err := wallet.ReadSignedTxFile(strings.TrimSpace(pg.signedPathEditor.Editor.Text()))
+	if err != nil {
+		pg.signedPathEditor.SetError(err.Error())
+		return
+	}
+
+	pg.broadcasting = true
+	id := pg.WL.Wallet.BroadcastSignedTx(pg.walletID(), signed)
+	pg.OnResponse(id, func(resp wallet.Response) {
+		pg.broadcasting = false
+		if resp.Err != nil {
+			pg.CreateToast(resp.Err.Error(), false)
+			return
+		}
+		pg.signedPathEditor.Editor.SetText("")
+		pg.CreateToast("Transaction broadcast: "+resp.Resp.(*wallet.Broadcast).TxHash, true)
+	})
+}
+
+// showExported shows the path of an exported transaction and its JSON as a QR
+// code, when it fits in one.
+func (pg *OfflineSigningPage) showExported(path string, tx interface{}) {
+	pg.exported, pg.qrImage = path, nil
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	qrCode, err := qrcode.New(string(data))
+	if err != nil {
+		// The transaction is too large for a QR code.
+		return
+	}
+	var buff bytes.Buffer
+	if err := qrCode.SaveTo(&buff); err != nil {
+		log.Error(err.Error())
+		return
+	}
+	img, _, err := image.Decode(&buff)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	pg.qrImage = &img
+}
+
+func (pg *OfflineSigningPage) clearSeed() {
+	pg.seedEditor.Editor.SetText("")
+	pg.seedEditor.SetError("")
+}
+
+func (pg *OfflineSigningPage) OnClose() {
+	pg.clearSeed()
+}
@@ -1,6 +1,7 @@
 package page
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
@@ -41,6 +42,7 @@ type OverviewPage struct {
 
 	allWallets   []*dcrlibwallet.Wallet
 	transactions []dcrlibwallet.Transaction
+	fiatValue    wallet.FiatValueFunc
 
 	toTransactions    decredmaterial.TextAndIconButton
 	sync              decredmaterial.Button
@@ -128,7 +130,24 @@ func (pg *OverviewPage) OnResume() {
 	pg.bestBlock = pg.WL.MultiWallet.GetBestBlock()
 
 	pg.loadTransactions()
+	pg.fiatValue = pg.WL.Wallet.HistoricalFiatValuer()
 	pg.listenForSyncNotifications()
+	go pg.syncRateHistory()
+}
+
+// syncRateHistory fetches the daily rates needed to value the wallet
+// transactions at the time they were made.
+func (pg *OverviewPage) syncRateHistory() {
+	n, err := pg.WL.Wallet.SyncRateHistoryCtx(context.Background())
+	if err != nil {
+		if err != wallet.ErrNoExchangeRate {
+			log.Errorf("Error fetching rate history: %v", err)
+		}
+		return
+	}
+	if n > 0 {
+		pg.RefreshWindow()
+	}
 }
 
 func (pg *OverviewPage) loadTransactions() {
@@ -216,6 +235,7 @@ func (pg *OverviewPage) recentTransactionsSection(gtx layout.Context) layout.Dim
 							transaction: pg.transactions[i],
 							index:       i,
 							showBadge:   len(pg.allWallets) > 1,
+							fiatValue:   pg.fiatValue,
 						}
 						return layout.Inset{Left: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
 							return transactionRow(gtx, pg.Load, row)
@@ -5,6 +5,7 @@ import (
 	"image"
 	"image/color"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/planetdecred/godcr/ui/load"
@@ -17,9 +18,11 @@ import (
 	"gioui.org/unit"
 	"gioui.org/widget"
 
+	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/planetdecred/dcrlibwallet"
 	"github.com/planetdecred/godcr/ui/decredmaterial"
 	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
 	qrcode "github.com/yeqown/go-qrcode"
 	"golang.org/x/exp/shiny/materialdesign/icons"
 )
@@ -39,6 +42,14 @@ type ReceivePage struct {
 	receiveAddress    decredmaterial.Label
 	gtx               *layout.Context
 
+	// optional payment request details encoded in the QR code
+	amountEditor  decredmaterial.Editor
+	labelEditor   decredmaterial.Editor
+	messageEditor decredmaterial.Editor
+
+	// noteEditor edits the note carried over to payments to the address.
+	noteEditor *txNoteEditor
+
 	selector *accountSelector
 
 	backdrop   *widget.Clickable
@@ -86,6 +97,16 @@ func NewReceivePage(l *load.Load) *ReceivePage {
 	pg.backButton, pg.infoButton = subpageHeaderButtons(l)
 	pg.backButton.Icon = pg.Icons.ContentClear
 
+	pg.amountEditor = l.Theme.Editor(new(widget.Editor), "Amount (DCR)")
+	pg.labelEditor = l.Theme.Editor(new(widget.Editor), "Label")
+	pg.messageEditor = l.Theme.Editor(new(widget.Editor), "Message")
+	for _, e := range []*decredmaterial.Editor{&pg.amountEditor, &pg.labelEditor, &pg.messageEditor} {
+		e.Editor.SingleLine = true
+		e.IsRequired = false
+	}
+
+	pg.noteEditor = newTxNoteEditor(l)
+
 	pg.selector = newAccountSelector(pg.Load).
 		title("Receiving account").
 		accountSelected(func(selectedAccount *dcrlibwallet.Account) {
@@ -97,6 +118,7 @@ func NewReceivePage(l *load.Load) *ReceivePage {
 				pg.currentAddress = currentAddress
 			}
 
+			pg.loadAddressNote()
 			pg.generateQRForAddress()
 		}).
 		accountValidator(func(account *dcrlibwallet.Account) bool {
@@ -117,6 +139,11 @@ func (pg *ReceivePage) OnResume() {
 	pg.selector.selectFirstWalletValidAccount()
 }
 
+func (pg *ReceivePage) loadAddressNote() {
+	notes := pg.WL.Wallet.TxNotes(pg.selector.selectedAccount.WalletID)
+	pg.noteEditor.setNote(notes.Addresses[pg.currentAddress])
+}
+
 func (pg *ReceivePage) generateQRForAddress() {
 	absoluteWdPath, err := GetAbsolutePath()
 	if err != nil {
@@ -124,7 +151,7 @@ func (pg *ReceivePage) generateQRForAddress() {
 	}
 
 	opt := qrcode.WithLogoImageFilePNG(filepath.Join(absoluteWdPath, "ui/assets/decredicons/qrcodeSymbol.png"))
-	qrCode, err := qrcode.New(pg.currentAddress, opt)
+	qrCode, err := qrcode.New(pg.paymentURI(), opt)
 	if err != nil {
 		log.Error("Error generating address qrCode: " + err.Error())
 		return
@@ -146,6 +173,42 @@ func (pg *ReceivePage) generateQRForAddress() {
 	pg.qrImage = &imgdec
 }
 
+// paymentRequest returns the payment request for the current address, or
+// false if the amount entered is invalid.
+func (pg *ReceivePage) paymentRequest() (wallet.PaymentRequest, bool) {
+	req := wallet.PaymentRequest{
+		Address: pg.currentAddress,
+		Label:   pg.labelEditor.Editor.Text(),
+		Message: pg.messageEditor.Editor.Text(),
+	}
+
+	pg.amountEditor.SetError("")
+	if text := pg.amountEditor.Editor.Text(); text != "" {
+		dcr, err := strconv.ParseFloat(text, 64)
+		if err != nil || dcr < 0 {
+			pg.amountEditor.SetError("Invalid amount")
+			return req, false
+		}
+		amount, err := dcrutil.NewAmount(dcr)
+		if err != nil {
+			pg.amountEditor.SetError("Invalid amount")
+			return req, false
+		}
+		req.Amount = int64(amount)
+	}
+	return req, true
+}
+
+// paymentURI returns what the QR code and copy button share: the bare address
+// or a decred: payment request URI if an amount, label or message is set.
+func (pg *ReceivePage) paymentURI() string {
+	req, ok := pg.paymentRequest()
+	if !ok || (req.Amount == 0 && req.Label == "" && req.Message == "") {
+		return pg.currentAddress
+	}
+	return req.URI()
+}
+
 func (pg *ReceivePage) Layout(gtx layout.Context) layout.Dimensions {
 	if pg.gtx == nil {
 		pg.gtx = &gtx
@@ -186,12 +249,27 @@ func (pg *ReceivePage) Layout(gtx layout.Context) layout.Dimensions {
 
 									return pg.Theme.ImageIcon(gtx, *pg.qrImage, 360)
 								}),
+								layout.Rigid(func(gtx C) D {
+									uri := pg.paymentURI()
+									if uri == pg.currentAddress {
+										return layout.Dimensions{}
+									}
+									txt := pg.Theme.Caption(uri)
+									txt.Color = pg.Theme.Color.Gray
+									return txt.Layout(gtx)
+								}),
 							)
 						})
 					}),
 				)
 			})
 		},
+		func(gtx C) D {
+			return pg.Theme.Separator().Layout(gtx)
+		},
+		func(gtx C) D {
+			return pg.pageSections(gtx, pg.paymentRequestLayout)
+		},
 	}
 
 	dims := uniformPadding(gtx, func(gtx C) D {
@@ -221,6 +299,25 @@ func (pg *ReceivePage) pageSections(gtx layout.Context, body layout.Widget) layo
 	})
 }
 
+func (pg *ReceivePage) paymentRequestLayout(gtx layout.Context) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			txt := pg.Theme.Body2("Payment request (optional)")
+			txt.Color = pg.Theme.Color.Gray
+			return txt.Layout(gtx)
+		}),
+		layout.Rigid(pg.amountEditor.Layout),
+		layout.Rigid(pg.labelEditor.Layout),
+		layout.Rigid(pg.messageEditor.Layout),
+		layout.Rigid(func(gtx C) D {
+			txt := pg.Theme.Body2(values.String(values.StrAddressNote))
+			txt.Color = pg.Theme.Color.Gray
+			return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, txt.Layout)
+		}),
+		layout.Rigid(pg.noteEditor.layout),
+	)
+}
+
 // pageBackdropLayout layout of background overlay when the popup button generate new address is show,
 // click outside of the generate new address button to hide the button
 func (pg *ReceivePage) pageBackdropLayout(gtx layout.Context) {
@@ -341,6 +438,7 @@ func (pg *ReceivePage) Handle() {
 		}
 
 		pg.currentAddress = newAddr
+		pg.loadAddressNote()
 		pg.generateQRForAddress()
 		pg.isNewAddr = false
 	}
@@ -357,9 +455,26 @@ func (pg *ReceivePage) Handle() {
 		pg.ChangePage(*pg.ReturnPage)
 	}
 
+	for _, e := range []*decredmaterial.Editor{&pg.amountEditor, &pg.labelEditor, &pg.messageEditor} {
+		for _, evt := range e.Editor.Events() {
+			if _, ok := evt.(widget.ChangeEvent); ok {
+				pg.generateQRForAddress()
+			}
+		}
+	}
+
+	if pg.noteEditor.submitted() && pg.currentAddress != "" {
+		err := pg.WL.Wallet.SetAddressNote(pg.selector.selectedAccount.WalletID, pg.currentAddress, pg.noteEditor.note())
+		if err != nil {
+			pg.CreateToast(err.Error(), false)
+		} else {
+			pg.CreateToast(values.String(values.StrNoteSaved), true)
+		}
+	}
+
 	if pg.copy.Button.Clicked() {
 
-		clipboard.WriteOp{Text: pg.currentAddress}.Add(gtx.Ops)
+		clipboard.WriteOp{Text: pg.paymentURI()}.Add(gtx.Ops)
 
 		pg.copy.Text = "Copied!"
 		pg.copy.Color = pg.Theme.Color.Success
@@ -20,6 +20,7 @@ type SecurityToolsPage struct {
 	*load.Load
 	verifyMessage   *widget.Clickable
 	validateAddress *widget.Clickable
+	offlineSigning  *widget.Clickable
 
 	backButton decredmaterial.IconButton
 	infoButton decredmaterial.IconButton
@@ -30,6 +31,7 @@ func NewSecurityToolsPage(l *load.Load) *SecurityToolsPage {
 		Load:            l,
 		verifyMessage:   new(widget.Clickable),
 		validateAddress: new(widget.Clickable),
+		offlineSigning:  new(widget.Clickable),
 	}
 
 	pg.backButton, pg.infoButton = subpageHeaderButtons(l)
@@ -54,13 +56,18 @@ func (pg *SecurityToolsPage) Layout(gtx layout.Context) layout.Dimensions {
 			},
 			body: func(gtx C) D {
 				return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, func(gtx C) D {
-					return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx,
-						layout.Flexed(.5, pg.message()),
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 						layout.Rigid(func(gtx C) D {
-							size := image.Point{X: 15, Y: gtx.Constraints.Min.Y}
-							return layout.Dimensions{Size: size}
+							return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx,
+								layout.Flexed(.5, pg.message()),
+								layout.Rigid(func(gtx C) D {
+									size := image.Point{X: 15, Y: gtx.Constraints.Min.Y}
+									return layout.Dimensions{Size: size}
+								}),
+								layout.Flexed(.5, pg.address()),
+							)
 						}),
-						layout.Flexed(.5, pg.address()),
+						layout.Rigid(pg.signing()),
 					)
 				})
 			},
@@ -83,6 +90,12 @@ func (pg *SecurityToolsPage) address() layout.Widget {
 	}
 }
 
+func (pg *SecurityToolsPage) signing() layout.Widget {
+	return func(gtx C) D {
+		return pg.pageSections(gtx, pg.Icons.WatchOnlyWalletIcon, pg.offlineSigning, pg.Theme.Body1("Offline Signing").Layout)
+	}
+}
+
 func (pg *SecurityToolsPage) pageSections(gtx layout.Context, icon *widget.Image, action *widget.Clickable, body layout.Widget) layout.Dimensions {
 	return layout.Inset{Bottom: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
 		return pg.Theme.Card().Layout(gtx, func(gtx C) D {
@@ -116,6 +129,11 @@ func (pg *SecurityToolsPage) Handle() {
 		pg.SetReturnPage(SecurityToolsPageID)
 		pg.ChangePage(ValidateAddressPageID)
 	}
+
+	if pg.offlineSigning.Clicked() {
+		pg.SetReturnPage(SecurityToolsPageID)
+		pg.ChangePage(OfflineSigningPageID)
+	}
 }
 
 func (pg *SecurityToolsPage) OnClose() {}
@@ -38,6 +38,7 @@ type SettingsPage struct {
 	updateConnectToPeer *widget.Clickable
 	updateUserAgent     *widget.Clickable
 	changeStartupPass   *widget.Clickable
+	importRateHistory   *widget.Clickable
 	chevronRightIcon    *widget.Icon
 	confirm             decredmaterial.Button
 	cancel              decredmaterial.Button
@@ -86,6 +87,7 @@ func NewSettingsPage(l *load.Load) *SettingsPage {
 		updateConnectToPeer: new(widget.Clickable),
 		updateUserAgent:     new(widget.Clickable),
 		changeStartupPass:   new(widget.Clickable),
+		importRateHistory:   new(widget.Clickable),
 
 		confirm: l.Theme.Button(new(widget.Clickable), "Ok"),
 		cancel:  l.Theme.Button(new(widget.Clickable), values.String(values.StrCancel)),
@@ -104,6 +106,12 @@ func NewSettingsPage(l *load.Load) *SettingsPage {
 
 	currencyMap := make(map[string]string)
 	currencyMap[DefaultExchangeValue] = values.StrNone
+	for _, source := range pg.wal.ExchangeRates.Sources() {
+		for _, currency := range source.Currencies() {
+			value := wallet.CurrencyConversionValue(source.Name(), currency)
+			currencyMap[value] = values.StringF(values.StrCurrencySource, currency, values.String(source.Name()))
+		}
+	}
 	currencyMap[USDExchangeValue] = values.StrUsdBittrex
 
 	currencyPreference := preference.NewListPreference(pg.WL.Wallet, pg.Theme,
@@ -188,6 +196,18 @@ func (pg *SettingsPage) general() layout.Widget {
 					}
 					return pg.clickableRow(gtx, currencyConversionRow)
 				}),
+				layout.Rigid(func(gtx C) D {
+					_, ok := pg.wal.FiatCurrency()
+					return pg.conditionalDisplay(gtx, ok, func(gtx C) D {
+						importRateHistoryRow := row{
+							title:     values.String(values.StrImportRateHistory),
+							clickable: pg.importRateHistory,
+							icon:      pg.chevronRightIcon,
+							label:     pg.theme.Body2(""),
+						}
+						return pg.clickableRow(gtx, importRateHistoryRow)
+					})
+				}),
 				layout.Rigid(pg.lineSeparator()),
 				layout.Rigid(func(gtx C) D {
 					languageRow := row{
@@ -486,6 +506,11 @@ func (pg *SettingsPage) Handle() {
 		break
 	}
 
+	for pg.importRateHistory.Clicked() {
+		pg.showImportRateHistoryDialog()
+		break
+	}
+
 	userAgentKey := dcrlibwallet.UserAgentConfigKey
 	for pg.updateUserAgent.Clicked() {
 		pg.showUserAgentDialog()
@@ -542,6 +567,30 @@ func (pg *SettingsPage) showUserAgentDialog() {
 	textModal.Show()
 }
 
+// showImportRateHistoryDialog imports daily rates of the selected currency
+// from a CSV file of date and rate records.
+func (pg *SettingsPage) showImportRateHistoryDialog() {
+	textModal := modal.NewTextInputModal(pg.Load).
+		Hint(values.String(values.StrCSVFilePath)).
+		PositiveButton(values.String(values.StrImport), func(path string, tim *modal.TextInputModal) bool {
+			currency, ok := pg.wal.FiatCurrency()
+			if path == "" || !ok {
+				return true
+			}
+			n, err := pg.wal.ImportRateHistoryCSV(path, currency)
+			if err != nil {
+				pg.CreateToast(err.Error(), false)
+				return true
+			}
+			pg.CreateToast(values.StringF(values.StrRatesImported, n, currency), true)
+			return true
+		})
+
+	textModal.Title(values.String(values.StrImportRateHistory)).
+		NegativeButton(values.String(values.StrCancel), func() {})
+	textModal.Show()
+}
+
 func (pg *SettingsPage) updateSettingOptions() {
 	isPassword := pg.wal.IsStartupSecuritySet()
 	pg.startupPassword.Value = false
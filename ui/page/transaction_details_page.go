@@ -16,6 +16,7 @@ import (
 	"github.com/planetdecred/dcrlibwallet"
 	"github.com/planetdecred/godcr/ui/decredmaterial"
 	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
 )
 
 const TransactionDetailsPageID = "TransactionDetails"
@@ -35,10 +36,13 @@ type TransactionDetailsPage struct {
 	inputsCollapsible               *decredmaterial.Collapsible
 	backButton                      decredmaterial.IconButton
 	infoButton                      decredmaterial.IconButton
+	rebroadcastBtn                  decredmaterial.Button
+	noteEditor                      *txNoteEditor
 	gtx                             *layout.Context
 
 	transaction *dcrlibwallet.Transaction
 	wallet      *dcrlibwallet.Wallet
+	fiatValue   wallet.FiatValueFunc
 
 	txSourceAccount      string
 	txDestinationAddress string
@@ -74,6 +78,10 @@ func NewTransactionDetailsPage(l *load.Load, transaction *dcrlibwallet.Transacti
 
 	pg.copyTextBtn = make([]decredmaterial.Button, 0)
 
+	pg.rebroadcastBtn = l.Theme.Button(new(widget.Clickable), values.String(values.StrRebroadcast))
+	pg.noteEditor = newTxNoteEditor(l)
+	pg.noteEditor.setNote(l.WL.Wallet.TxNotes(transaction.WalletID).For(transaction))
+
 	pg.dot = l.Icons.ImageBrightness1
 	pg.dot.Color = l.Theme.Color.Gray
 
@@ -105,7 +113,7 @@ func NewTransactionDetailsPage(l *load.Load, transaction *dcrlibwallet.Transacti
 }
 
 func (pg *TransactionDetailsPage) OnResume() {
-
+	pg.fiatValue = pg.WL.Wallet.HistoricalFiatValuer()
 }
 
 func (pg *TransactionDetailsPage) Layout(gtx layout.Context) layout.Dimensions {
@@ -136,6 +144,12 @@ func (pg *TransactionDetailsPage) Layout(gtx layout.Context) layout.Dimensions {
 					func(gtx C) D {
 						return pg.separator(gtx)
 					},
+					func(gtx C) D {
+						return pg.pageSections(gtx, pg.noteEditor.layout)
+					},
+					func(gtx C) D {
+						return pg.separator(gtx)
+					},
 					func(gtx C) D {
 						return pg.txnInputs(gtx)
 					},
@@ -152,6 +166,9 @@ func (pg *TransactionDetailsPage) Layout(gtx layout.Context) layout.Dimensions {
 						return pg.viewTxn(gtx)
 					},
 				}
+				if pg.transaction.BlockHeight == -1 {
+					widgets = append(widgets, pg.separator, pg.unconfirmedActions)
+				}
 				return pg.Theme.Card().Layout(gtx, func(gtx C) D {
 					return pg.transactionDetailsPageContainer.Layout(gtx, len(widgets), func(gtx C, i int) D {
 						return layout.Inset{}.Layout(gtx, widgets[i])
@@ -269,6 +286,18 @@ func (pg *TransactionDetailsPage) txnTypeAndID(gtx layout.Context) layout.Dimens
 					return pg.txnInfoSection(gtx, values.String(values.StrFee), dcrutil.Amount(transaction.Fee).String(), false, nil)
 				})
 			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.fiatValue == nil {
+					return layout.Dimensions{}
+				}
+				value, currency, ok := pg.fiatValue(transaction.Amount, transaction.Timestamp)
+				if !ok {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Bottom: m}.Layout(gtx, func(gtx C) D {
+					return pg.txnInfoSection(gtx, values.String(values.StrValueAtTheTime), FormatFiatBalance(pg.Printer, value, currency), false, nil)
+				})
+			}),
 			layout.Rigid(func(gtx C) D {
 				if transaction.BlockHeight != -1 {
 					return pg.txnInfoSection(gtx, values.String(values.StrIncludedInBlock), fmt.Sprintf("%d", transaction.BlockHeight), false, nil)
@@ -463,6 +492,14 @@ func (pg *TransactionDetailsPage) viewTxn(gtx layout.Context) layout.Dimensions
 	})
 }
 
+// unconfirmedActions lays out the rebroadcast button of an unconfirmed
+// transaction.
+func (pg *TransactionDetailsPage) unconfirmedActions(gtx layout.Context) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		return layout.W.Layout(gtx, pg.rebroadcastBtn.Layout)
+	})
+}
+
 func (pg *TransactionDetailsPage) pageSections(gtx layout.Context, body layout.Widget) layout.Dimensions {
 	m := values.MarginPadding20
 	mtb := values.MarginPadding5
@@ -493,6 +530,26 @@ func (pg *TransactionDetailsPage) Handle() {
 	for pg.destAddressClickable.Clicked() {
 		clipboard.WriteOp{Text: pg.txDestinationAddress}.Add(gtx.Ops)
 	}
+
+	if pg.noteEditor.submitted() {
+		err := pg.WL.Wallet.SetTxNote(pg.transaction.WalletID, pg.transaction.Hash, pg.noteEditor.note())
+		if err != nil {
+			pg.CreateToast(err.Error(), false)
+		} else {
+			pg.CreateToast(values.String(values.StrNoteSaved), true)
+		}
+	}
+
+	if pg.rebroadcastBtn.Button.Clicked() {
+		id := pg.WL.Wallet.RebroadcastTxs(pg.transaction.WalletID)
+		pg.OnResponse(id, func(resp wallet.Response) {
+			if resp.Err != nil {
+				pg.CreateToast(resp.Err.Error(), false)
+				return
+			}
+			pg.CreateToast(values.String(values.StrTxsRebroadcast), true)
+		})
+	}
 }
 
 func (pg *TransactionDetailsPage) OnClose() {}
@@ -2,6 +2,8 @@ package page
 
 import (
 	"image"
+	"strconv"
+	"strings"
 	"time"
 
 	"gioui.org/gesture"
@@ -9,6 +11,7 @@ import (
 	"gioui.org/layout"
 	"gioui.org/widget"
 
+	"github.com/decred/dcrd/dcrutil"
 	"github.com/planetdecred/dcrlibwallet"
 	"github.com/planetdecred/godcr/ui/decredmaterial"
 	"github.com/planetdecred/godcr/ui/load"
@@ -18,6 +21,12 @@ import (
 
 const Transactions = "Transactions"
 
+// txPageSize is the number of transactions loaded at a time.
+const txPageSize = 50
+
+// filterDateFormat is the format of the from and to date filters.
+const filterDateFormat = "2006-01-02"
+
 type transactionWdg struct {
 	statusIcon           *widget.Image
 	direction            *widget.Image
@@ -33,12 +42,37 @@ type TransactionsPage struct {
 	separator    decredmaterial.Line
 	theme        *decredmaterial.Theme
 
-	orderDropDown  *decredmaterial.DropDown
-	txTypeDropDown *decredmaterial.DropDown
-	walletDropDown *decredmaterial.DropDown
+	orderDropDown   *decredmaterial.DropDown
+	txTypeDropDown  *decredmaterial.DropDown
+	walletDropDown  *decredmaterial.DropDown
+	accountDropDown *decredmaterial.DropDown
+
+	searchEditor    decredmaterial.Editor
+	filtersButton   decredmaterial.Button
+	showFilters     bool
+	minAmountEditor decredmaterial.Editor
+	maxAmountEditor decredmaterial.Editor
+	fromEditor      decredmaterial.Editor
+	toEditor        decredmaterial.Editor
+	addressEditor   decredmaterial.Editor
+	hashEditor      decredmaterial.Editor
+	loadMoreButton  decredmaterial.Button
+
+	exportFormatDropDown *decredmaterial.DropDown
+	exportButton         decredmaterial.Button
+	isExporting          bool
 
 	transactions []dcrlibwallet.Transaction
-	wallets      []*dcrlibwallet.Wallet
+	// hasMore is set if more transactions match than are loaded, cursor is
+	// where the next page starts.
+	hasMore bool
+	cursor  wallet.TxCursor
+	// queryID is the request of the latest query, the responses to earlier
+	// queries are dropped.
+	queryID  wallet.RequestID
+	txNotes  *wallet.TxNotes
+	wallets  []*dcrlibwallet.Wallet
+	accounts []*dcrlibwallet.Account
 }
 
 func NewTransactionsPage(l *load.Load) *TransactionsPage {
@@ -70,47 +104,184 @@ func NewTransactionsPage(l *load.Load) *TransactionsPage {
 		},
 	}, 1)
 
+	pg.searchEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrSearchTransactions))
+	pg.minAmountEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrMinAmount))
+	pg.maxAmountEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrMaxAmount))
+	pg.fromEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrFromDate))
+	pg.toEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrToDate))
+	pg.addressEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrAddress))
+	pg.hashEditor = l.Theme.Editor(new(widget.Editor), values.String(values.StrTxHashPrefix))
+	for _, e := range pg.filterEditors() {
+		e.Editor.SingleLine = true
+		e.IsRequired = false
+	}
+	pg.filtersButton = l.Theme.Button(new(widget.Clickable), values.String(values.StrFilters))
+	pg.loadMoreButton = l.Theme.Button(new(widget.Clickable), values.String(values.StrLoadMore))
+
+	pg.exportFormatDropDown = l.Theme.DropDown([]decredmaterial.DropDownItem{
+		{Text: "CSV"},
+		{Text: "JSON"},
+		{Text: "OFX"},
+	}, 1)
+	pg.exportButton = l.Theme.Button(new(widget.Clickable), values.String(values.StrExport))
+
 	return pg
 }
 
 func (pg *TransactionsPage) OnResume() {
 	pg.wallets = pg.WL.SortedWalletList()
 	createOrUpdateWalletDropDown(pg.Load, &pg.walletDropDown, pg.wallets)
+	pg.loadAccounts()
 	pg.listenForTxNotifications()
 	pg.loadTransactions()
 }
 
-func (pg *TransactionsPage) loadTransactions() {
+// filterEditors returns the search box and the editors of the filters.
+func (pg *TransactionsPage) filterEditors() []*decredmaterial.Editor {
+	return []*decredmaterial.Editor{&pg.searchEditor, &pg.minAmountEditor, &pg.maxAmountEditor,
+		&pg.fromEditor, &pg.toEditor, &pg.addressEditor, &pg.hashEditor}
+}
+
+// loadAccounts lists the accounts of the selected wallet in the account
+// filter.
+func (pg *TransactionsPage) loadAccounts() {
 	selectedWallet := pg.wallets[pg.walletDropDown.SelectedIndex()]
-	newestFirst := pg.orderDropDown.SelectedIndex() == 0
+	items := []decredmaterial.DropDownItem{{Text: values.String(values.StrAllAccounts)}}
+	pg.accounts = nil
+	if accountsResult, err := selectedWallet.GetAccountsRaw(); err != nil {
+		log.Error("Error loading accounts:", err)
+	} else {
+		for _, account := range accountsResult.Acc {
+			pg.accounts = append(pg.accounts, account)
+			items = append(items, decredmaterial.DropDownItem{Text: account.Name})
+		}
+	}
+	pg.accountDropDown = pg.Theme.DropDown(items, 1)
+}
+
+func parseAmountFilter(editor *decredmaterial.Editor) int64 {
+	editor.SetError("")
+	text := strings.TrimSpace(editor.Editor.Text())
+	if text == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value < 0 {
+		editor.SetError(values.String(values.StrInvalidAmount))
+		return 0
+	}
+	amount, err := dcrutil.NewAmount(value)
+	if err != nil {
+		editor.SetError(values.String(values.StrInvalidAmount))
+		return 0
+	}
+	return int64(amount)
+}
+
+// parseDateFilter returns the date in the editor as local time, plus days.
+func parseDateFilter(editor *decredmaterial.Editor, days int) time.Time {
+	editor.SetError("")
+	text := strings.TrimSpace(editor.Editor.Text())
+	if text == "" {
+		return time.Time{}
+	}
+	date, err := time.ParseInLocation(filterDateFormat, text, time.Local)
+	if err != nil {
+		editor.SetError(values.String(values.StrInvalidDate))
+		return time.Time{}
+	}
+	return date.AddDate(0, 0, days)
+}
+
+// txQuery returns the query of the filters, skipping those that do not
+// parse.
+func (pg *TransactionsPage) txQuery() wallet.TxQuery {
+	selectedWallet := pg.wallets[pg.walletDropDown.SelectedIndex()]
+	query := wallet.TxQuery{
+		WalletIDs:   []int{selectedWallet.ID},
+		Account:     -1,
+		NewestFirst: pg.orderDropDown.SelectedIndex() == 0,
+		Search:      pg.searchEditor.Editor.Text(),
+		MinAmount:   parseAmountFilter(&pg.minAmountEditor),
+		MaxAmount:   parseAmountFilter(&pg.maxAmountEditor),
+		From:        parseDateFilter(&pg.fromEditor, 0),
+		// The to date is included in the range.
+		To:         parseDateFilter(&pg.toEditor, 1),
+		Address:    strings.TrimSpace(pg.addressEditor.Editor.Text()),
+		HashPrefix: strings.TrimSpace(pg.hashEditor.Editor.Text()),
+		Limit:      txPageSize,
+	}
+	if i := pg.accountDropDown.SelectedIndex(); i > 0 && i <= len(pg.accounts) {
+		query.Account = pg.accounts[i-1].Number
+	}
 
-	txFilter := dcrlibwallet.TxFilterAll
 	switch pg.txTypeDropDown.SelectedIndex() {
 	case 1:
-		txFilter = dcrlibwallet.TxFilterSent
+		query.TxFilter = dcrlibwallet.TxFilterSent
 	case 2:
-		txFilter = dcrlibwallet.TxFilterReceived
+		query.TxFilter = dcrlibwallet.TxFilterReceived
 	case 3:
-		txFilter = dcrlibwallet.TxFilterTransferred
+		query.TxFilter = dcrlibwallet.TxFilterTransferred
 	case 4:
-		txFilter = dcrlibwallet.TxFilterStaking
+		query.TxFilter = dcrlibwallet.TxFilterStaking
 	}
+	return query
+}
 
-	wallTxs, err := selectedWallet.GetTransactionsRaw(0, 0, txFilter, newestFirst) //TODO
-	if err != nil {
-		log.Error("Error loading transactions:", err)
-	} else {
-		pg.transactions = wallTxs
-	}
+// loadTransactions loads the first page of transactions matching the
+// filters.
+func (pg *TransactionsPage) loadTransactions() {
+	pg.queryTransactions(nil)
+}
+
+// loadMoreTransactions loads the page of transactions after those loaded.
+func (pg *TransactionsPage) loadMoreTransactions() {
+	pg.queryTransactions(pg.cursor)
+}
+
+// queryTransactions loads the page of transactions after cursor, or the
+// first page if cursor is nil.
+func (pg *TransactionsPage) queryTransactions(cursor wallet.TxCursor) {
+	query := pg.txQuery()
+	query.After = cursor
+	walletID := query.WalletIDs[0]
+
+	id := pg.WL.Wallet.QueryTransactions(query)
+	pg.queryID = id
+	pg.OnResponse(id, func(resp wallet.Response) {
+		if id != pg.queryID {
+			return
+		}
+		if resp.Err != nil {
+			log.Error("Error loading transactions:", resp.Err)
+			return
+		}
+		page := resp.Resp.(*wallet.TxPage)
+		if cursor == nil {
+			pg.transactions = page.Txs
+			pg.txNotes = pg.WL.Wallet.TxNotes(walletID)
+		} else {
+			pg.transactions = append(pg.transactions, page.Txs...)
+		}
+		pg.hasMore, pg.cursor = page.HasMore, page.Cursor
+	})
 }
 
 func (pg *TransactionsPage) Layout(gtx layout.Context) layout.Dimensions {
 	container := func(gtx C) D {
 		wallTxs := pg.transactions
+		if pg.txNotes == nil {
+			// the first page is not loaded yet
+			wallTxs = nil
+		}
 		return layout.Stack{Alignment: layout.N}.Layout(gtx,
 			layout.Expanded(func(gtx C) D {
+				top := values.MarginPadding60
+				if pg.showFilters {
+					top = values.MarginPadding200
+				}
 				return layout.Inset{
-					Top: values.MarginPadding60,
+					Top: top,
 				}.Layout(gtx, func(gtx C) D {
 					return pg.Theme.Card().Layout(gtx, func(gtx C) D {
 						padding := values.MarginPadding16
@@ -130,7 +301,13 @@ func (pg *TransactionsPage) Layout(gtx layout.Context) layout.Dimensions {
 									pg.toTxnDetails = createClickGestures(len(wallTxs))
 								}
 
-								return pg.txsList.Layout(gtx, len(wallTxs), func(gtx C, index int) D {
+								return pg.txsList.Layout(gtx, len(wallTxs)+1, func(gtx C, index int) D {
+									if index == len(wallTxs) {
+										if !pg.hasMore {
+											return layout.Dimensions{}
+										}
+										return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, pg.loadMoreButton.Layout)
+									}
 									click := pg.toTxnDetails[index]
 									pointer.Rect(image.Rectangle{Max: gtx.Constraints.Max}).Add(gtx.Ops)
 									click.Add(gtx.Ops)
@@ -139,6 +316,7 @@ func (pg *TransactionsPage) Layout(gtx layout.Context) layout.Dimensions {
 										transaction: wallTxs[index],
 										index:       index,
 										showBadge:   false,
+										note:        pg.txNotes.For(&wallTxs[index]),
 									}
 									return transactionRow(gtx, pg.Load, row)
 								})
@@ -146,7 +324,12 @@ func (pg *TransactionsPage) Layout(gtx layout.Context) layout.Dimensions {
 					})
 				})
 			}),
-			layout.Stacked(pg.dropDowns),
+			layout.Stacked(func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(pg.dropDowns),
+					layout.Rigid(pg.filters),
+				)
+			}),
 		)
 	}
 	return uniformPadding(gtx, container)
@@ -160,7 +343,19 @@ func (pg *TransactionsPage) dropDowns(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceBetween}.Layout(gtx,
 			layout.Rigid(pg.walletDropDown.Layout),
 			layout.Rigid(func(gtx C) D {
-				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						gtx.Constraints.Max.X = gtx.Px(values.MarginPadding200)
+						gtx.Constraints.Min.X = gtx.Constraints.Max.X
+						return layout.Inset{
+							Left: values.MarginPadding5,
+						}.Layout(gtx, pg.searchEditor.Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{
+							Left: values.MarginPadding5,
+						}.Layout(gtx, pg.filtersButton.Layout)
+					}),
 					layout.Rigid(func(gtx C) D {
 						return layout.Inset{
 							Left: values.MarginPadding5,
@@ -171,12 +366,55 @@ func (pg *TransactionsPage) dropDowns(gtx layout.Context) layout.Dimensions {
 							Left: values.MarginPadding5,
 						}.Layout(gtx, pg.orderDropDown.Layout)
 					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{
+							Left: values.MarginPadding5,
+						}.Layout(gtx, pg.exportFormatDropDown.Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{
+							Left: values.MarginPadding5,
+						}.Layout(gtx, pg.exportButton.Layout)
+					}),
 				)
 			}),
 		)
 	})
 }
 
+// filters lays out the account, amount, date, address and hash filters when
+// they are shown.
+func (pg *TransactionsPage) filters(gtx layout.Context) layout.Dimensions {
+	if !pg.showFilters {
+		return layout.Dimensions{}
+	}
+	field := func(editor *decredmaterial.Editor) layout.FlexChild {
+		return layout.Flexed(1, func(gtx C) D {
+			return layout.Inset{Right: values.MarginPadding5}.Layout(gtx, editor.Layout)
+		})
+	}
+	gtx.Constraints.Min.X = gtx.Constraints.Max.X
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Right: values.MarginPadding5}.Layout(gtx, pg.accountDropDown.Layout)
+				}),
+				field(&pg.minAmountEditor),
+				field(&pg.maxAmountEditor),
+				field(&pg.fromEditor),
+				field(&pg.toEditor),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{}.Layout(gtx,
+				field(&pg.addressEditor),
+				field(&pg.hashEditor),
+			)
+		}),
+	)
+}
+
 func (pg *TransactionsPage) Handle() {
 	for pg.txTypeDropDown.Changed() {
 		pg.loadTransactions()
@@ -187,8 +425,73 @@ func (pg *TransactionsPage) Handle() {
 	}
 
 	for pg.walletDropDown.Changed() {
+		pg.loadAccounts()
 		pg.loadTransactions()
 	}
+
+	for pg.accountDropDown.Changed() {
+		pg.loadTransactions()
+	}
+
+	for _, e := range pg.filterEditors() {
+		for _, evt := range e.Editor.Events() {
+			if _, ok := evt.(widget.ChangeEvent); ok {
+				pg.loadTransactions()
+			}
+		}
+	}
+
+	if pg.filtersButton.Button.Clicked() {
+		pg.showFilters = !pg.showFilters
+	}
+
+	if pg.loadMoreButton.Button.Clicked() {
+		pg.loadMoreTransactions()
+	}
+
+	if pg.exportButton.Button.Clicked() {
+		pg.exportTransactions()
+	}
+}
+
+// exportTransactions writes the transactions of the selected wallet matching
+// the type, account and date filters to a file in the app data directory, in
+// the selected format.
+func (pg *TransactionsPage) exportTransactions() {
+	if pg.isExporting {
+		return
+	}
+
+	query := pg.txQuery()
+	formats := []wallet.ExportFormat{wallet.ExportCSV, wallet.ExportJSON, wallet.ExportOFX}
+	opts := wallet.TxExport{
+		Format: formats[pg.exportFormatDropDown.SelectedIndex()],
+		Filter: wallet.TxExportFilter{
+			WalletID: query.WalletIDs[0],
+			Account:  query.Account,
+			From:     query.From,
+			To:       query.To,
+			TxFilter: query.TxFilter,
+		},
+	}
+	if currency, ok := pg.WL.Wallet.FiatCurrency(); ok {
+		if history, err := pg.WL.Wallet.RateHistory(); err == nil {
+			opts.FiatCurrency = currency
+			opts.FiatRate = history.RateFunc(currency)
+		}
+	}
+
+	pg.isExporting = true
+	id := pg.WL.Wallet.ExportTransactions(opts, nil)
+	pg.OnResponse(id, func(resp wallet.Response) {
+		pg.isExporting = false
+		if resp.Err != nil {
+			pg.CreateToast(resp.Err.Error(), false)
+			return
+		}
+		exported := resp.Resp.(*wallet.TransactionsExported)
+		pg.CreateToast(values.StringF(values.StrTransactionsExported, exported.Count, exported.Path), true)
+	})
 }
 
 func (pg *TransactionsPage) goToTxnDetails(events []gesture.ClickEvent, txn *dcrlibwallet.Transaction) {
@@ -0,0 +1,64 @@
+package page
+
+import (
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/load"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+// txNoteEditor edits the note and tags of a transaction or receive address.
+// Changes are saved when either editor is submitted.
+type txNoteEditor struct {
+	noteEditor decredmaterial.Editor
+	tagsEditor decredmaterial.Editor
+}
+
+func newTxNoteEditor(l *load.Load) *txNoteEditor {
+	e := &txNoteEditor{
+		noteEditor: l.Theme.Editor(new(widget.Editor), values.String(values.StrNote)),
+		tagsEditor: l.Theme.Editor(new(widget.Editor), values.String(values.StrTags)),
+	}
+	for _, editor := range []*decredmaterial.Editor{&e.noteEditor, &e.tagsEditor} {
+		editor.Editor.SingleLine = true
+		editor.Editor.Submit = true
+		editor.IsRequired = false
+	}
+	return e
+}
+
+// setNote shows note in the editors.
+func (e *txNoteEditor) setNote(note wallet.TxNote) {
+	e.noteEditor.Editor.SetText(note.Note)
+	e.tagsEditor.Editor.SetText(strings.Join(note.Tags, ", "))
+}
+
+// note returns the note in the editors.
+func (e *txNoteEditor) note() wallet.TxNote {
+	return wallet.NewTxNote(e.noteEditor.Editor.Text(), wallet.ParseTags(e.tagsEditor.Editor.Text()))
+}
+
+// submitted reports whether either editor was submitted.
+func (e *txNoteEditor) submitted() bool {
+	var submitted bool
+	for _, editor := range []*decredmaterial.Editor{&e.noteEditor, &e.tagsEditor} {
+		for _, evt := range editor.Editor.Events() {
+			if _, ok := evt.(widget.SubmitEvent); ok {
+				submitted = true
+			}
+		}
+	}
+	return submitted
+}
+
+func (e *txNoteEditor) layout(gtx layout.Context) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(e.noteEditor.Layout),
+		layout.Rigid(e.tagsEditor.Layout),
+	)
+}
@@ -135,6 +135,15 @@ func formatUSDBalance(p *message.Printer, balance float64) string {
 	return p.Sprintf("$%.2f", balance)
 }
 
+// FormatFiatBalance formats a balance in a fiat currency, with a dollar sign
+// for USD.
+func FormatFiatBalance(p *message.Printer, balance float64, currency string) string {
+	if currency == "USD" {
+		return formatUSDBalance(p, balance)
+	}
+	return p.Sprintf("%.2f %s", balance, currency)
+}
+
 func goToURL(url string) {
 	var err error
 
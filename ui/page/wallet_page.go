@@ -294,7 +294,7 @@ func (pg *WalletPage) showImportWatchOnlyWalletModal(l *load.Load) {
 	modal.NewCreateWatchOnlyModal(l).
 		WatchOnlyCreated(func(walletName, extPubKey string, m *modal.CreateWatchOnlyModal) bool {
 			go func() {
-				_, err := pg.multiWallet.CreateWatchOnlyWallet(walletName, extPubKey)
+				err := l.WL.Wallet.ImportWatchOnlyWallet(walletName, extPubKey)
 				if err != nil {
 					l.CreateToast(err.Error(), false)
 					m.SetError(err.Error())
@@ -162,7 +162,12 @@ func (lp *ListPreference) layoutItems() []layout.FlexChild {
 
 	items := make([]layout.FlexChild, 0)
 	for _, k := range lp.itemKeys {
-		radioItem := layout.Rigid(lp.theme.RadioButton(lp.optionsRadioGroup, k, values.String(lp.items[k])).Layout)
+		// items that are not string keys are shown as they are
+		label := values.String(lp.items[k])
+		if label == "" {
+			label = lp.items[k]
+		}
+		radioItem := layout.Rigid(lp.theme.RadioButton(lp.optionsRadioGroup, k, label).Layout)
 
 		items = append(items, radioItem)
 	}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"gioui.org/font/gofont"
@@ -15,6 +16,7 @@ import (
 	"github.com/planetdecred/godcr/ui/decredmaterial"
 	"github.com/planetdecred/godcr/ui/renderers"
 	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
 )
 
 const (
@@ -26,6 +28,13 @@ type proposalItemWidgets struct {
 	clickables map[string]*widget.Clickable
 }
 
+// proposalWalletVote is a wallet with tickets that can vote on the proposal.
+type proposalWalletVote struct {
+	walletID int
+	name     string
+	vote     decredmaterial.Button
+}
+
 type proposalDetails struct {
 	theme              *decredmaterial.Theme
 	loadingDescription bool
@@ -40,8 +49,22 @@ type proposalDetails struct {
 	downloadIcon       *widget.Image
 	timerIcon          *widget.Image
 	successIcon        *widget.Icon
-	vote               decredmaterial.Button
 	backButton         decredmaterial.IconButton
+
+	proposalVote *wallet.ProposalVote
+	loadingVote  bool
+	voteErr      string
+	walletVotes  []*proposalWalletVote
+
+	bookmark   *widget.Clickable
+	bookmarked bool
+
+	// previousVersion is the latest cached version before the current one,
+	// empty when no earlier version was cached.
+	previousVersion string
+	changes         *wallet.ProposalDiff
+	showChanges     *widget.Clickable
+	changesVisible  bool
 }
 
 func ProposalDetailsPage(common *pageCommon, proposal dcrlibwallet.Proposal) Page {
@@ -59,30 +82,75 @@ func ProposalDetailsPage(common *pageCommon, proposal dcrlibwallet.Proposal) Pag
 		rejectedIcon:       common.icons.navigationCancel,
 		successIcon:        common.icons.actionCheckCircle,
 		timerIcon:          common.icons.timerIcon,
+		bookmark:           new(widget.Clickable),
+		bookmarked:         common.wallet.IsProposalBookmarked(proposal.Token),
+		showChanges:        new(widget.Clickable),
 	}
 
 	pg.downloadIcon.Scale = 1
 	pg.backButton, _ = common.SubPageHeaderButtons()
 
-	pg.vote = common.theme.Button(new(widget.Clickable), "Vote")
-	pg.vote.TextSize = values.TextSize14
-	pg.vote.Background = common.theme.Color.Primary
-	pg.vote.Color = common.theme.Color.Surface
-	pg.vote.CornerRadius = values.MarginPadding8
-	pg.vote.Inset = layout.Inset{
+	return pg
+}
+
+func (pg *proposalDetails) voteButton() decredmaterial.Button {
+	vote := pg.theme.Button(new(widget.Clickable), "Vote")
+	vote.TextSize = values.TextSize14
+	vote.Background = pg.theme.Color.Primary
+	vote.Color = pg.theme.Color.Surface
+	vote.CornerRadius = values.MarginPadding8
+	vote.Inset = layout.Inset{
 		Top:    values.MarginPadding8,
 		Bottom: values.MarginPadding8,
 		Left:   values.MarginPadding12,
 		Right:  values.MarginPadding12,
 	}
-
-	return pg
+	return vote
 }
 
 func (pg *proposalDetails) OnResume() {
 
 }
 
+// hasVote reports whether the proposal's vote has started.
+func (pg *proposalDetails) hasVote() bool {
+	switch pg.proposal.Category {
+	case dcrlibwallet.ProposalCategoryActive, dcrlibwallet.ProposalCategoryApproved, dcrlibwallet.ProposalCategoryRejected:
+		return true
+	}
+	return false
+}
+
+// fetchVote loads the proposal's vote with the tickets of the wallets that
+// can vote on it.
+func (pg *proposalDetails) fetchVote() {
+	pg.loadingVote = true
+	id := pg.common.wallet.ProposalVote(pg.proposal.Token)
+	pg.common.onResponse(id, func(resp wallet.Response) {
+		pg.loadingVote = false
+		if resp.Err != nil {
+			pg.voteErr = resp.Err.Error()
+			return
+		}
+
+		pg.voteErr = ""
+		pg.proposalVote = resp.Resp.(*wallet.ProposalVote)
+		pg.walletVotes = nil
+		for _, info := range pg.common.info.Wallets {
+			unvoted := len(pg.proposalVote.Unvoted(info.ID))
+			voted := pg.proposalVote.Voted(info.ID)
+			if unvoted == 0 && len(voted) == 0 {
+				continue
+			}
+			pg.walletVotes = append(pg.walletVotes, &proposalWalletVote{
+				walletID: info.ID,
+				name:     info.Name,
+				vote:     pg.voteButton(),
+			})
+		}
+	})
+}
+
 func (pg *proposalDetails) Handle() {
 	for token := range pg.proposalItems {
 		for location, clickable := range pg.proposalItems[token].clickables {
@@ -91,6 +159,48 @@ func (pg *proposalDetails) Handle() {
 			}
 		}
 	}
+
+	if pg.proposalVote == nil && !pg.loadingVote && pg.voteErr == "" && pg.hasVote() {
+		pg.fetchVote()
+	}
+
+	for _, wv := range pg.walletVotes {
+		if wv.vote.Button.Clicked() {
+			newvoteModal(pg.common, wv.walletID, pg.proposalVote, pg.fetchVote).Show()
+		}
+	}
+
+	if pg.bookmark.Clicked() {
+		pg.bookmarked = !pg.bookmarked
+		pg.common.wallet.BookmarkProposal(pg.proposal.Token, pg.bookmarked)
+	}
+
+	if pg.showChanges.Clicked() {
+		pg.changesVisible = !pg.changesVisible
+		if pg.changesVisible && pg.changes == nil {
+			diff, err := pg.common.wallet.ProposalDiff(pg.proposal.Token, pg.previousVersion, pg.proposal.Version)
+			if err != nil {
+				pg.common.notify(err.Error(), false)
+				pg.changesVisible = false
+			} else {
+				pg.changes = diff
+			}
+		}
+	}
+}
+
+// loadPreviousVersion finds the latest cached version of the proposal
+// before the one shown.
+func (pg *proposalDetails) loadPreviousVersion() {
+	current, err := strconv.Atoi(pg.proposal.Version)
+	if err != nil {
+		return
+	}
+	for _, version := range pg.common.wallet.CachedProposalVersions(pg.proposal.Token) {
+		if v, err := strconv.Atoi(version); err == nil && v < current {
+			pg.previousVersion = version
+		}
+	}
 }
 
 func (pg *proposalDetails) layoutProposalVoteBar(gtx C) D {
@@ -109,12 +219,107 @@ func (pg *proposalDetails) layoutProposalVoteAction(gtx C) D {
 	proposal := pg.proposal
 	gtx.Constraints.Min.X = gtx.Constraints.Max.X
 	txt := pg.theme.Label(values.TextSize14, fmt.Sprintf("%d eligible tickets", proposal.EligibleTickets))
+	rows := []layout.FlexChild{layout.Rigid(txt.Layout)}
+
+	var status decredmaterial.Label
+	switch {
+	case pg.voteErr != "":
+		status = pg.theme.Body2(pg.voteErr)
+		status.Color = pg.theme.Color.Danger
+	case pg.proposalVote == nil:
+		status = pg.theme.Body2("Loading your tickets...")
+		status.Color = pg.theme.Color.Gray
+	case len(pg.walletVotes) == 0:
+		status = pg.theme.Body2("None of your tickets are eligible to vote on this proposal")
+		status.Color = pg.theme.Color.Gray
+	}
+	if status.Text != "" {
+		rows = append(rows, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, status.Layout)
+		}))
+	}
+
+	for _, wv := range pg.walletVotes {
+		wv := wv
+		rows = append(rows, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding10}.Layout(gtx, func(gtx C) D {
+				return pg.layoutWalletVote(gtx, wv)
+			})
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
+
+// layoutWalletVote shows how a wallet's eligible tickets voted, with a button
+// to vote with the rest while the vote is active.
+func (pg *proposalDetails) layoutWalletVote(gtx C, wv *proposalWalletVote) D {
+	unvoted := len(pg.proposalVote.Unvoted(wv.walletID))
+	voted := pg.proposalVote.Voted(wv.walletID)
+	summary := pg.theme.Caption(fmt.Sprintf("%d yes, %d no, %d not voted", voted["yes"], voted["no"], unvoted))
+	summary.Color = pg.theme.Color.Gray
+
+	gtx.Constraints.Min.X = gtx.Constraints.Max.X
 	return layout.Flex{Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
-		layout.Rigid(pg.vote.Layout),
-		layout.Rigid(txt.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(pg.theme.Body1(wv.name).Layout),
+				layout.Rigid(summary.Layout),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if unvoted == 0 || pg.proposal.Category != dcrlibwallet.ProposalCategoryActive {
+				return D{}
+			}
+			return wv.vote.Layout(gtx)
+		}),
 	)
 }
 
+// ticketVoteWidgets lists the vote of each eligible ticket of the wallets.
+func (pg *proposalDetails) ticketVoteWidgets() []layout.Widget {
+	if pg.proposalVote == nil || len(pg.proposalVote.Tickets) == 0 {
+		return nil
+	}
+
+	title := pg.theme.Body1("Your tickets")
+	title.Font.Weight = text.Bold
+	w := []layout.Widget{title.Layout}
+	for _, ticket := range pg.proposalVote.Tickets {
+		ticket := ticket
+		w = append(w, func(gtx C) D {
+			status := pg.theme.Body2("Not voted")
+			switch ticket.Vote {
+			case "":
+				status.Color = pg.theme.Color.Gray
+				if pg.proposal.Category != dcrlibwallet.ProposalCategoryActive {
+					status.Text = "Did not vote"
+				}
+			case "yes":
+				status.Text = "Voted yes"
+				status.Color = pg.theme.Color.Success
+			case "no":
+				status.Text = "Voted no"
+				status.Color = pg.theme.Color.Danger
+			default:
+				status.Text = "Voted " + ticket.Vote
+			}
+
+			name := ""
+			if wal := pg.common.multiWallet.WalletWithID(ticket.WalletID); wal != nil {
+				name = wal.Name + ": "
+			}
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(pg.theme.Body2(name+truncateString(ticket.Hash, 24)).Layout),
+					layout.Rigid(status.Layout),
+				)
+			})
+		})
+	}
+	return append(w, pg.lineSeparator(layout.Inset{Top: values.MarginPadding16, Bottom: values.MarginPadding16}))
+}
+
 func (pg *proposalDetails) layoutInDiscussionState(gtx C) D {
 	stateText1 := "Waiting for author to authorize voting"
 	stateText2 := "Waiting for admin to trigger the start of voting"
@@ -243,7 +448,7 @@ func (pg *proposalDetails) layoutNormalTitle(gtx C) D {
 		layout.Rigid(pg.lineSeparator(layout.Inset{Top: values.MarginPadding10, Bottom: values.MarginPadding10})),
 		layout.Rigid(pg.layoutProposalVoteBar),
 		layout.Rigid(func(gtx C) D {
-			if proposal.Category != dcrlibwallet.ProposalCategoryActive {
+			if !pg.hasVote() {
 				return D{}
 			}
 			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
@@ -312,9 +517,11 @@ func (pg *proposalDetails) layoutDescription(gtx C) D {
 		},
 		pg.lineSeparator(layout.Inset{Top: values.MarginPadding16, Bottom: values.MarginPadding16}),
 	}
+	w = append(w, pg.ticketVoteWidgets()...)
 
 	_, ok := pg.proposalItems[proposal.Token]
 	if ok {
+		w = append(w, pg.changesWidgets()...)
 		w = append(w, pg.proposalItems[proposal.Token].widgets...)
 	} else {
 		th := material.NewTheme(gofont.Collection())
@@ -377,19 +584,14 @@ func (pg *proposalDetails) Layout(gtx C) D {
 	if !ok && !pg.loadingDescription {
 		pg.loadingDescription = true
 		go func() {
-			var proposalDescription string
-			if proposal.IndexFile != "" && proposal.IndexFileVersion == proposal.Version {
-				proposalDescription = proposal.IndexFile
-			} else {
-				var err error
-				proposalDescription, err = common.wallet.FetchProposalDescription(proposal.Token)
-				if err != nil {
-					log.Infof("Error loading proposal description: %v", err)
-					time.Sleep(7 * time.Second)
-					pg.loadingDescription = false
-					return
-				}
+			proposalDescription, err := common.wallet.ProposalDescription(proposal)
+			if err != nil {
+				log.Infof("Error loading proposal description: %v", err)
+				time.Sleep(7 * time.Second)
+				pg.loadingDescription = false
+				return
 			}
+			pg.loadPreviousVersion()
 
 			r := renderers.RenderMarkdown(gtx, pg.theme, proposalDescription)
 			proposalWidgets, proposalClickables := r.Layout()
@@ -417,11 +619,14 @@ func (pg *proposalDetails) Layout(gtx C) D {
 					layout.Rigid(pg.layoutDescription),
 				)
 			},
+			extraItem: pg.bookmark,
 			extra: func(gtx C) D {
-				return layout.Inset{}.Layout(gtx, func(gtx C) D {
-					pg.redirectIcon.Scale = 1
-					return layout.E.Layout(gtx, pg.redirectIcon.Layout)
-				})
+				lbl := pg.theme.Body2("Bookmark")
+				if pg.bookmarked {
+					lbl.Text = "Remove bookmark"
+				}
+				lbl.Color = pg.theme.Color.Primary
+				return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, lbl.Layout)
 			},
 		}
 		return common.SubPageLayout(gtx, page)
@@ -430,3 +635,72 @@ func (pg *proposalDetails) Layout(gtx C) D {
 }
 
 func (pg *proposalDetails) OnClose() {}
+
+// changesContext is the number of unchanged lines kept around each change
+// when the changes since the previous version are shown.
+const changesContext = 2
+
+// changesWidgets returns the toggle and, when shown, the lines changed since
+// the previous cached version of the proposal.
+func (pg *proposalDetails) changesWidgets() []layout.Widget {
+	if pg.previousVersion == "" {
+		return nil
+	}
+
+	w := []layout.Widget{
+		func(gtx C) D {
+			lbl := pg.theme.Body2("Show changes since version " + pg.previousVersion)
+			if pg.changesVisible {
+				lbl.Text = "Hide changes since version " + pg.previousVersion
+			}
+			lbl.Color = pg.theme.Color.Primary
+			return layout.Inset{Bottom: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+				return decredmaterial.Clickable(gtx, pg.showChanges, lbl.Layout)
+			})
+		},
+	}
+	if !pg.changesVisible || pg.changes == nil {
+		return w
+	}
+
+	lines := pg.changes.Lines
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line.Kind == wallet.DiffSame {
+			// Collapse long runs of unchanged lines.
+			end := i
+			for end < len(lines) && lines[end].Kind == wallet.DiffSame {
+				end++
+			}
+			if end-i > 2*changesContext+1 {
+				for _, l := range lines[i : i+changesContext] {
+					w = append(w, pg.changedLine(l))
+				}
+				skipped := end - i - 2*changesContext
+				w = append(w, pg.changedLine(wallet.DiffLine{Kind: wallet.DiffSame,
+					Text: fmt.Sprintf("... %d unchanged lines ...", skipped)}))
+				i = end - changesContext - 1
+				continue
+			}
+		}
+		w = append(w, pg.changedLine(line))
+	}
+	return append(w, pg.lineSeparator(layout.Inset{Top: values.MarginPadding16, Bottom: values.MarginPadding16}))
+}
+
+func (pg *proposalDetails) changedLine(line wallet.DiffLine) layout.Widget {
+	return func(gtx C) D {
+		lbl := pg.theme.Body2("  " + line.Text)
+		switch line.Kind {
+		case wallet.DiffAdded:
+			lbl.Text = "+ " + line.Text
+			lbl.Color = pg.theme.Color.Success
+		case wallet.DiffRemoved:
+			lbl.Text = "- " + line.Text
+			lbl.Color = pg.theme.Color.Danger
+		default:
+			lbl.Color = pg.theme.Color.Gray
+		}
+		return lbl.Layout(gtx)
+	}
+}
@@ -8,14 +8,17 @@ import (
 	"gioui.org/layout"
 	"gioui.org/text"
 	"gioui.org/widget"
+	"github.com/planetdecred/dcrlibwallet"
 	"github.com/planetdecred/godcr/ui/decredmaterial"
 	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
 )
 
 const ModalInputVote = "input_vote_modal"
 
 type inputVoteOptionsWidgets struct {
 	label      string
+	optionID   string
 	background color.NRGBA
 	input      decredmaterial.Editor
 	increment  decredmaterial.IconButton
@@ -23,21 +26,30 @@ type inputVoteOptionsWidgets struct {
 	max        decredmaterial.Button
 }
 
+// voteModal casts the votes of a wallet's eligible tickets on a proposal.
 type voteModal struct {
 	*pageCommon
 	randomID       string
 	modal          decredmaterial.Modal
 	passwordEditor decredmaterial.Editor
-	callback       func(password string, m *voteModal) bool // return true to dismiss dialog
 	btnPositve     decredmaterial.Button
 	btnNegative    decredmaterial.Button
 	yesVote        inputVoteOptionsWidgets
 	noVote         inputVoteOptionsWidgets
+
+	walletID int
+	vote     *wallet.ProposalVote
+	// eligible is the number of the wallet's tickets that have not voted.
+	eligible int
+	isVoting bool
+	// onVoted is called after votes were submitted to reload the vote.
+	onVoted func()
 }
 
-func newInputVoteOptions(c *pageCommon, label string) inputVoteOptionsWidgets {
+func newInputVoteOptions(c *pageCommon, label, optionID string) inputVoteOptionsWidgets {
 	i := inputVoteOptionsWidgets{
 		label:      label,
+		optionID:   optionID,
 		background: c.theme.Color.LightGray,
 		input:      c.theme.Editor(new(widget.Editor), ""),
 		increment:  c.theme.PlainIconButton(new(widget.Clickable), c.icons.contentAdd),
@@ -56,13 +68,17 @@ func newInputVoteOptions(c *pageCommon, label string) inputVoteOptionsWidgets {
 	return i
 }
 
-func newvoteModal(common *pageCommon) *voteModal {
+func newvoteModal(common *pageCommon, walletID int, vote *wallet.ProposalVote, onVoted func()) *voteModal {
 	cm := &voteModal{
 		pageCommon:  common,
 		randomID:    fmt.Sprintf("%s-%d", ModalInputVote, generateRandomNumber()),
 		modal:       *common.theme.ModalFloatTitle(),
 		btnPositve:  common.theme.Button(new(widget.Clickable), "Vote"),
 		btnNegative: common.theme.Button(new(widget.Clickable), "Cancel"),
+		walletID:    walletID,
+		vote:        vote,
+		eligible:    len(vote.Unvoted(walletID)),
+		onVoted:     onVoted,
 	}
 
 	cm.btnPositve.TextSize, cm.btnNegative.TextSize = values.TextSize16, values.TextSize16
@@ -73,9 +89,9 @@ func newvoteModal(common *pageCommon) *voteModal {
 	cm.passwordEditor = common.theme.EditorPassword(new(widget.Editor), "Spending password")
 	cm.passwordEditor.Editor.SingleLine, cm.passwordEditor.Editor.Submit = true, true
 
-	cm.yesVote = newInputVoteOptions(common, "Yes")
+	cm.yesVote = newInputVoteOptions(common, "Yes", "yes")
 	cm.yesVote.background = common.theme.Color.Success2
-	cm.noVote = newInputVoteOptions(common, "No")
+	cm.noVote = newInputVoteOptions(common, "No", "no")
 	return cm
 }
 
@@ -98,42 +114,94 @@ func (cm *voteModal) Dismiss() {
 	cm.dismissModal(cm)
 }
 
-func (i *inputVoteOptionsWidgets) handleVoteCountButtons() {
-	if i.increment.Button.Clicked() {
-		value, err := strconv.Atoi(i.input.Editor.Text())
-		if err != nil {
-			log.Error(err)
-			return
-		}
-		value++
-		i.input.Editor.SetText(fmt.Sprintf("%d", value))
+// count returns the number of votes entered for the option, or 0 if the
+// input is not a number.
+func (i *inputVoteOptionsWidgets) count() int {
+	value, err := strconv.Atoi(i.input.Editor.Text())
+	if err != nil || value < 0 {
+		return 0
 	}
+	return value
+}
 
-	if i.decrement.Button.Clicked() {
-		value, err := strconv.Atoi(i.input.Editor.Text())
-		if err != nil {
-			log.Error(err)
-			return
-		}
-		value--
-		if value < 0 {
-			return
-		}
-		i.input.Editor.SetText(fmt.Sprintf("%d", value))
+// handleVoteCountButtons changes the votes of the option, adding at most
+// remaining votes.
+func (i *inputVoteOptionsWidgets) handleVoteCountButtons(remaining int) {
+	if i.increment.Button.Clicked() && remaining > 0 {
+		i.input.Editor.SetText(fmt.Sprintf("%d", i.count()+1))
+	}
+
+	if i.decrement.Button.Clicked() && i.count() > 0 {
+		i.input.Editor.SetText(fmt.Sprintf("%d", i.count()-1))
 	}
 
 	if i.max.Button.Clicked() {
-		i.input.Editor.SetText("5")
+		i.input.Editor.SetText(fmt.Sprintf("%d", i.count()+remaining))
+	}
+}
+
+func (cm *voteModal) remaining() int {
+	return cm.eligible - cm.yesVote.count() - cm.noVote.count()
+}
+
+func (cm *voteModal) canVote() bool {
+	total := cm.yesVote.count() + cm.noVote.count()
+	return total > 0 && cm.remaining() >= 0 && cm.passwordEditor.Editor.Text() != "" && !cm.isVoting
+}
+
+func (cm *voteModal) sendVotes() {
+	cm.isVoting = true
+	cm.passwordEditor.SetError("")
+	counts := map[string]int{
+		cm.yesVote.optionID: cm.yesVote.count(),
+		cm.noVote.optionID:  cm.noVote.count(),
 	}
+	id := cm.wallet.CastProposalVotes(cm.walletID, cm.vote.Token, counts, []byte(cm.passwordEditor.Editor.Text()))
+	cm.onResponse(id, func(resp wallet.Response) {
+		cm.isVoting = false
+		if resp.Err != nil {
+			if resp.Err.Error() == dcrlibwallet.ErrInvalidPassphrase {
+				cm.passwordEditor.SetError(translateErr(resp.Err))
+				return
+			}
+			// Some votes may have been accepted before others were rejected.
+			cm.notify(resp.Err.Error(), false)
+			cm.onVoted()
+			return
+		}
+
+		cast := resp.Resp.(*wallet.ProposalVotesCast)
+		cm.notify(fmt.Sprintf("%d votes cast", cast.Cast), true)
+		cm.Dismiss()
+		cm.onVoted()
+	})
 }
 
 func (cm *voteModal) Handle() {
-	if cm.btnNegative.Button.Clicked() {
+	if cm.btnNegative.Button.Clicked() && !cm.isVoting {
 		cm.Dismiss()
 	}
 
-	cm.yesVote.handleVoteCountButtons()
-	cm.noVote.handleVoteCountButtons()
+	cm.yesVote.handleVoteCountButtons(cm.remaining())
+	cm.noVote.handleVoteCountButtons(cm.remaining())
+
+	if cm.canVote() {
+		cm.btnPositve.Background = cm.theme.Color.Primary
+	} else {
+		cm.btnPositve.Background = cm.theme.Color.Gray1
+	}
+
+	for cm.btnPositve.Button.Clicked() {
+		if cm.canVote() {
+			cm.sendVotes()
+		}
+	}
+
+	for _, evt := range cm.passwordEditor.Editor.Events() {
+		if _, ok := evt.(widget.SubmitEvent); ok && cm.canVote() {
+			cm.sendVotes()
+		}
+	}
 }
 
 func (cm *voteModal) Layout(gtx layout.Context) D {
@@ -144,7 +212,11 @@ func (cm *voteModal) Layout(gtx layout.Context) D {
 			return t.Layout(gtx)
 		},
 		func(gtx C) D {
-			return cm.theme.Label(values.TextSize16, "You have 5 votes").Layout(gtx)
+			lbl := cm.theme.Label(values.TextSize16, fmt.Sprintf("You have %d votes", cm.eligible))
+			if cm.remaining() < 0 {
+				lbl.Color = cm.theme.Color.Danger
+			}
+			return lbl.Layout(gtx)
 		},
 
 		func(gtx C) D {
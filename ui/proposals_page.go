@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
@@ -57,8 +58,18 @@ type proposalsPage struct {
 
 	showSyncedCompleted bool
 	isSyncing           bool
+
+	searchEditor   decredmaterial.Editor
+	authorEditor   decredmaterial.Editor
+	fromEditor     decredmaterial.Editor
+	toEditor       decredmaterial.Editor
+	bookmarkedOnly decredmaterial.CheckBoxStyle
+	bookmarks      map[string]bool
 }
 
+// proposalDateLayout is the format of the date range filter.
+const proposalDateLayout = "2006-01-02"
+
 var (
 	proposalCategoryTitles = []string{"In discussion", "Voting", "Approved", "Rejected", "Abandoned"}
 	proposalCategories     = []int32{
@@ -87,6 +98,14 @@ func ProposalsPage(common *pageCommon) Page {
 		syncButton:            new(widget.Clickable),
 		startSyncIcon:         common.icons.restore,
 		timerIcon:             common.icons.timerIcon,
+		searchEditor:          common.theme.Editor(new(widget.Editor), "Search title and description"),
+		authorEditor:          common.theme.Editor(new(widget.Editor), "Author"),
+		fromEditor:            common.theme.Editor(new(widget.Editor), "From (YYYY-MM-DD)"),
+		toEditor:              common.theme.Editor(new(widget.Editor), "To (YYYY-MM-DD)"),
+		bookmarkedOnly:        common.theme.CheckBox(new(widget.Bool), "Bookmarked"),
+	}
+	for _, e := range []*widget.Editor{pg.searchEditor.Editor, pg.authorEditor.Editor, pg.fromEditor.Editor, pg.toEditor.Editor} {
+		e.SingleLine, e.Submit = true, true
 	}
 	pg.infoIcon.Color = common.theme.Color.Gray
 	pg.legendIcon.Color = common.theme.Color.InactiveGray
@@ -130,14 +149,64 @@ func (pg *proposalsPage) countProposals() {
 	pg.proposalMu.Unlock()
 }
 
+// filterDate parses the date of a date range editor, reporting invalid dates
+// on the editor.
+func filterDate(editor *decredmaterial.Editor) (time.Time, bool) {
+	editor.SetError("")
+	if editor.Editor.Text() == "" {
+		return time.Time{}, true
+	}
+	date, err := time.ParseInLocation(proposalDateLayout, editor.Editor.Text(), time.Local)
+	if err != nil {
+		editor.SetError("Invalid date")
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// filter returns the proposals filter of the category and the filter
+// editors, or false if a date is invalid.
+func (pg *proposalsPage) filter(category int) (wallet.ProposalFilter, bool) {
+	from, fromOk := filterDate(&pg.fromEditor)
+	to, toOk := filterDate(&pg.toEditor)
+	if !to.IsZero() {
+		// Include the whole end day.
+		to = to.AddDate(0, 0, 1)
+	}
+	return wallet.ProposalFilter{
+		Query:          pg.searchEditor.Editor.Text(),
+		Author:         pg.authorEditor.Editor.Text(),
+		From:           from,
+		To:             to,
+		Category:       proposalCategories[category],
+		BookmarkedOnly: pg.bookmarkedOnly.CheckBox.Value,
+	}, fromOk && toOk
+}
+
+// filtering reports whether any filter is set.
+func (pg *proposalsPage) filtering() bool {
+	for _, e := range []*widget.Editor{pg.searchEditor.Editor, pg.authorEditor.Editor, pg.fromEditor.Editor, pg.toEditor.Editor} {
+		if e.Text() != "" {
+			return true
+		}
+	}
+	return pg.bookmarkedOnly.CheckBox.Value
+}
+
 func (pg *proposalsPage) loadProposals(category int) {
-	proposals, err := pg.multiWallet.Politeia.GetProposalsRaw(proposalCategories[category], 0, 0, true)
+	filter, ok := pg.filter(category)
+	if !ok {
+		return
+	}
+	bookmarks := pg.wallet.ProposalBookmarks()
+	result, err := pg.wallet.SearchProposalsCtx(context.Background(), filter)
 	if err != nil {
 		log.Error("Error loading proposals:", err)
 		pg.proposalMu.Lock()
 		pg.proposalItems = make([]proposalItem, 0)
 		pg.proposalMu.Unlock()
 	} else {
+		proposals := result.Proposals
 		proposalItems := make([]proposalItem, len(proposals))
 		for i := 0; i < len(proposals); i++ {
 			proposal := proposals[i]
@@ -164,6 +233,7 @@ func (pg *proposalsPage) loadProposals(category int) {
 		pg.proposalMu.Lock()
 		pg.selectedCategoryIndex = category
 		pg.proposalItems = proposalItems
+		pg.bookmarks = bookmarks
 		pg.proposalMu.Unlock()
 	}
 }
@@ -174,6 +244,27 @@ func (pg *proposalsPage) Handle() {
 		go pg.loadProposals(selectedItem)
 	}
 
+	pg.proposalMu.Lock()
+	selectedCategory := pg.selectedCategoryIndex
+	pg.proposalMu.Unlock()
+	if selectedCategory != -1 {
+		// The query fetches descriptions so it is only searched on submit.
+		reload := pg.bookmarkedOnly.CheckBox.Changed()
+		for _, e := range []*widget.Editor{pg.searchEditor.Editor, pg.authorEditor.Editor, pg.fromEditor.Editor, pg.toEditor.Editor} {
+			for _, evt := range e.Events() {
+				switch evt.(type) {
+				case widget.SubmitEvent:
+					reload = true
+				case widget.ChangeEvent:
+					reload = reload || e != pg.searchEditor.Editor
+				}
+			}
+		}
+		if reload {
+			go pg.loadProposals(selectedCategory)
+		}
+	}
+
 	if clicked, selectedItem := pg.proposalsList.ItemClicked(); clicked {
 		pg.proposalMu.Lock()
 		selectedProposal := pg.proposalItems[selectedItem].proposal
@@ -261,11 +352,36 @@ func (pg *proposalsPage) layoutTabs(gtx C) D {
 	})
 }
 
+func (pg *proposalsPage) layoutFilters(gtx C) D {
+	return layout.Inset{Bottom: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(pg.searchEditor.Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, pg.authorEditor.Layout),
+					layout.Flexed(1, func(gtx C) D {
+						return layout.Inset{Left: values.MarginPadding8}.Layout(gtx, pg.fromEditor.Layout)
+					}),
+					layout.Flexed(1, func(gtx C) D {
+						return layout.Inset{Left: values.MarginPadding8}.Layout(gtx, pg.toEditor.Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Left: values.MarginPadding8}.Layout(gtx, pg.bookmarkedOnly.Layout)
+					}),
+				)
+			}),
+		)
+	})
+}
+
 func (pg *proposalsPage) layoutNoProposalsFound(gtx C) D {
 	pg.proposalMu.Lock()
 	selectedCategory := pg.selectedCategoryIndex
 	pg.proposalMu.Unlock()
 	str := fmt.Sprintf("No %s proposals", strings.ToLower(proposalCategoryTitles[selectedCategory]))
+	if pg.filtering() {
+		str = "No proposals match the filters"
+	}
 
 	gtx.Constraints.Min.X = gtx.Constraints.Max.X
 	return layout.Center.Layout(gtx, pg.theme.Body1(str).Layout)
@@ -377,7 +493,17 @@ func (pg *proposalsPage) layoutInfoTooltip(gtx C, rect image.Rectangle, item pro
 func (pg *proposalsPage) layoutTitle(gtx C, proposal dcrlibwallet.Proposal) D {
 	lbl := pg.theme.H6(proposal.Name)
 	lbl.Font.Weight = text.Bold
-	return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, lbl.Layout)
+	pg.proposalMu.Lock()
+	bookmarked := pg.bookmarks[proposal.Token]
+	pg.proposalMu.Unlock()
+	return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, func(gtx C) D {
+		if !bookmarked {
+			return lbl.Layout(gtx)
+		}
+		tag := pg.theme.Caption("Bookmarked")
+		tag.Color = pg.theme.Color.Primary
+		return endToEndRow(gtx, lbl.Layout, tag.Layout)
+	})
 }
 
 func (pg *proposalsPage) layoutProposalVoteBar(gtx C, item proposalItem) D {
@@ -505,7 +631,12 @@ func (pg *proposalsPage) Layout(gtx C) D {
 			)
 		}),
 		layout.Flexed(1, func(gtx C) D {
-			return pg.UniformPadding(gtx, pg.layoutContent)
+			return pg.UniformPadding(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(pg.layoutFilters),
+					layout.Flexed(1, pg.layoutContent),
+				)
+			})
 		}),
 	)
 }
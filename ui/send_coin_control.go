@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+)
+
+// selectedInputs returns the keys of the outputs chosen on the UTXO page for
+// the sending account.
+func (pg *sendPage) selectedInputs() []string {
+	acct := pg.sourceAccountSelector.selectedAccount
+	if acct == nil {
+		return nil
+	}
+	var keys []string
+	for key := range pg.common.selectedUTXO[acct.WalletID][acct.Number] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyCoinControl makes the tx author spend the chosen outputs, or picks
+// outputs around the frozen ones when none was chosen. It returns the total
+// amount of the inputs, or -1 when the wallet selects them itself.
+func (pg *sendPage) applyCoinControl() (int64, error) {
+	acct := pg.sourceAccountSelector.selectedAccount
+	if acct == nil {
+		return -1, nil
+	}
+	keys, err := pg.wallet.CoinControlInputs(acct.WalletID, acct.Number, pg.selectedInputs(),
+		pg.txAuthor.TotalSendAmount().AtomValue, pg.destinationCount, pg.hasSendMax())
+	if err != nil {
+		return 0, err
+	}
+	if err := pg.txAuthor.UseInputs(keys); err != nil || keys == nil {
+		return -1, err
+	}
+	return pg.wallet.InputsAmount(acct.WalletID, acct.Number, keys)
+}
+
+func (pg *sendPage) coinControlSummary() string {
+	if n := len(pg.selectedInputs()); n > 0 {
+		return fmt.Sprintf("%d selected", n)
+	}
+	return "Automatic"
+}
@@ -133,6 +133,9 @@ func (scm *sendConfirmModal) Layout(gtx layout.Context) D {
 									)
 								})
 							}
+							if len(scm.outputs) > 1 {
+								return scm.theme.Body2(fmt.Sprintf("%d recipients", len(scm.outputs))).Layout(gtx)
+							}
 							return scm.theme.Body2(scm.destinationAddress).Layout(gtx)
 						}),
 					)
@@ -144,6 +147,7 @@ func (scm *sendConfirmModal) Layout(gtx layout.Context) D {
 		},
 		func(gtx C) D {
 			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(scm.outputsLayout),
 				layout.Rigid(func(gtx C) D {
 					return scm.contentRow(gtx, "Sending from", sendAcct.Name, sendWallet.Name)
 				}),
@@ -211,6 +215,25 @@ func (scm *sendConfirmModal) Layout(gtx layout.Context) D {
 	return scm.modal.Layout(gtx, w, 900)
 }
 
+// outputsLayout lists every output when the transaction pays more than one
+// destination.
+func (scm *sendConfirmModal) outputsLayout(gtx layout.Context) layout.Dimensions {
+	if len(scm.outputs) < 2 {
+		return layout.Dimensions{}
+	}
+
+	rows := make([]layout.FlexChild, len(scm.outputs))
+	for i, output := range scm.outputs {
+		output := output
+		rows[i] = layout.Rigid(func(gtx C) D {
+			return layout.Inset{Bottom: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+				return scm.contentRow(gtx, output.address, output.amount, "")
+			})
+		})
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
+
 func (scm *sendConfirmModal) contentRow(gtx layout.Context, leftValue, rightValue, walletName string) layout.Dimensions {
 	return layout.Flex{}.Layout(gtx,
 		layout.Rigid(func(gtx C) D {
@@ -21,6 +21,10 @@ import (
 const (
 	PageSend               = "Send"
 	invalidPassphraseError = "error broadcasting transaction: " + dcrlibwallet.ErrInvalidPassphrase
+
+	// maxContactSuggestions is the number of address book entries suggested
+	// for the destination address.
+	maxContactSuggestions = 3
 )
 
 type amountValue struct {
@@ -53,14 +57,34 @@ type sendPage struct {
 	maxButton    decredmaterial.Button
 	sendToButton decredmaterial.Button
 	clearAllBtn  decredmaterial.Button
+	// coinControlBtn opens the UTXO page to choose the outputs to spend.
+	coinControlBtn decredmaterial.Button
 
 	accountSwitch    *decredmaterial.SwitchButtonText
 	confirmModal     *decredmaterial.Modal
 	txFeeCollapsible *decredmaterial.Collapsible
 	currencySwap     *widget.Clickable
 
+	contactSuggestions []wallet.Contact
+	suggestionButtons  []*widget.Clickable
+	addressCheck       wallet.AddressCheck
+	paymentRequest     wallet.PaymentRequest
+
+	recipients             []*recipientRow
+	addRecipientButton     decredmaterial.Button
+	importRecipientsButton decredmaterial.Button
+	recipientsCSVEditor    decredmaterial.Editor
+
+	// sendMax makes the first destination receive the max amount when there
+	// are other destinations.
+	sendMax bool
+	// destinationCount is the number of destinations added to txAuthor.
+	destinationCount int
+
 	remainingBalance int64
 	amountAtoms      int64
+	firstAmountAtoms int64
+	sendMaxAtoms     int64
 	txFee            int64
 	spendableBalance int64
 
@@ -70,6 +94,7 @@ type sendPage struct {
 	confirmTxModal *sendConfirmModal
 
 	usdExchangeRate float64
+	fiatCurrency    string
 	inputAmount     float64
 	amountUSDtoDCR  float64
 	amountDCRtoUSD  float64
@@ -123,6 +148,13 @@ type comfirmModalData struct {
 
 	// others
 	destinationAddress string //pg.destinationAddressEditor.Editor.Text()
+	outputs            []sendOutput
+}
+
+// sendOutput is an output listed in the confirm modal.
+type sendOutput struct {
+	address string
+	amount  string
 }
 
 func SendPage(common *pageCommon) Page {
@@ -142,9 +174,11 @@ func SendPage(common *pageCommon) Page {
 
 		leftExchangeValue:  "DCR",
 		rightExchangeValue: "USD",
+		fiatCurrency:       "USD",
 		noExchangeErrMsg:   "Exchange rate not fetched",
 		maxButton:          common.theme.Button(new(widget.Clickable), "MAX"),
 		clearAllBtn:        common.theme.Button(new(widget.Clickable), "Clear all fields"),
+		coinControlBtn:     common.theme.Button(new(widget.Clickable), "Coin control"),
 		txFeeCollapsible:   common.theme.Collapsible(),
 
 		confirmModal:              common.theme.Modal(),
@@ -196,6 +230,23 @@ func SendPage(common *pageCommon) Page {
 	pg.destinationAddressEditor.Editor.SingleLine = true
 	pg.destinationAddressEditor.Editor.SetText("")
 
+	pg.recipientsCSVEditor = common.theme.Editor(new(widget.Editor), "Recipients CSV file path")
+	pg.recipientsCSVEditor.Editor.SingleLine = true
+	pg.recipientsCSVEditor.IsRequired = false
+
+	pg.addRecipientButton = common.theme.Button(new(widget.Clickable), "Add recipient")
+	pg.importRecipientsButton = common.theme.Button(new(widget.Clickable), "Import CSV")
+	for _, btn := range []*decredmaterial.Button{&pg.addRecipientButton, &pg.importRecipientsButton} {
+		btn.TextSize = values.TextSize14
+		btn.Background = color.NRGBA{}
+		btn.Color = common.theme.Color.Primary
+	}
+
+	pg.suggestionButtons = make([]*widget.Clickable, maxContactSuggestions)
+	for i := range pg.suggestionButtons {
+		pg.suggestionButtons[i] = new(widget.Clickable)
+	}
+
 	pg.closeConfirmationModalButton.Background = color.NRGBA{}
 	pg.closeConfirmationModalButton.Color = common.theme.Color.Primary
 
@@ -218,6 +269,9 @@ func SendPage(common *pageCommon) Page {
 	pg.clearAllBtn.Background = common.theme.Color.Surface
 	pg.clearAllBtn.Color = common.theme.Color.Text
 	pg.clearAllBtn.Inset = layout.UniformInset(values.MarginPadding15)
+	pg.coinControlBtn.Background = pg.clearAllBtn.Background
+	pg.coinControlBtn.Color = pg.clearAllBtn.Color
+	pg.coinControlBtn.Inset = pg.clearAllBtn.Inset
 
 	// Source account picker
 	pg.sourceAccountSelector = newAccountSelector(common).
@@ -275,6 +329,8 @@ func (pg *sendPage) OnResume() {
 	pg.sourceAccountSelector.selectFirstWalletValidAccount()
 
 	pg.fetchExchangeValue()
+	// The outputs to spend may have been chosen on the UTXO page.
+	pg.calculateValues(false)
 }
 
 func (pg *sendPage) Layout(gtx layout.Context) layout.Dimensions {
@@ -322,7 +378,12 @@ func (pg *sendPage) Layout(gtx layout.Context) layout.Dimensions {
 						}
 						return inset.Layout(gtx, func(gtx C) D {
 							border := widget.Border{Color: pg.theme.Color.Background, CornerRadius: values.MarginPadding5, Width: values.MarginPadding1}
-							return border.Layout(gtx, pg.clearAllBtn.Layout)
+							return border.Layout(gtx, func(gtx C) D {
+								return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+									layout.Rigid(pg.clearAllBtn.Layout),
+									layout.Rigid(pg.coinControlBtn.Layout),
+								)
+							})
 						})
 					}
 					return layout.Dimensions{}
@@ -378,14 +439,14 @@ func (pg *sendPage) toSection(gtx layout.Context, common *pageCommon) layout.Dim
 					if pg.sendToOption == "My account" {
 						return pg.destinationAccountSelector.Layout(gtx)
 					}
-					return pg.destinationAddressEditor.Layout(gtx)
+					return pg.destinationAddressLayout(gtx)
 				})
 			}),
 			layout.Rigid(func(gtx C) D {
 				if pg.usdExchangeSet {
 					return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
 						layout.Flexed(0.45, func(gtx C) D {
-							pg.leftAmountEditor.Hint = fmt.Sprintf("Amount (%s)", pg.leftExchangeValue)
+							pg.leftAmountEditor.Hint = pg.amountHint(pg.leftExchangeValue)
 							return pg.leftAmountEditor.Layout(gtx)
 						}),
 						layout.Flexed(0.1, func(gtx C) D {
@@ -400,17 +461,116 @@ func (pg *sendPage) toSection(gtx layout.Context, common *pageCommon) layout.Dim
 							})
 						}),
 						layout.Flexed(0.45, func(gtx C) D {
-							pg.rightAmountEditor.Hint = fmt.Sprintf("Amount (%s)", pg.rightExchangeValue)
+							pg.rightAmountEditor.Hint = pg.amountHint(pg.rightExchangeValue)
 							return pg.rightAmountEditor.Layout(gtx)
 						}),
 					)
 				}
+				pg.leftAmountEditor.Hint = pg.amountHint(pg.leftExchangeValue)
 				return pg.leftAmountEditor.Layout(gtx)
 			}),
+			layout.Rigid(func(gtx C) D {
+				if pg.sendToOption == "My account" {
+					return layout.Dimensions{}
+				}
+				return pg.recipientsLayout(gtx)
+			}),
 		)
 	})
 }
 
+func (pg *sendPage) amountHint(currency string) string {
+	if pg.sendMax {
+		return "Max amount"
+	}
+	return fmt.Sprintf("Amount (%s)", currency)
+}
+
+// destinationAddressLayout lays out the destination address editor with the
+// address book entries matching its text and any warning about the address.
+func (pg *sendPage) destinationAddressLayout(gtx layout.Context) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(pg.destinationAddressEditor.Layout),
+		layout.Rigid(func(gtx C) D {
+			list := layout.List{Axis: layout.Vertical}
+			return list.Layout(gtx, len(pg.contactSuggestions), func(gtx C, i int) D {
+				contact := pg.contactSuggestions[i]
+				return decredmaterial.Clickable(gtx, pg.suggestionButtons[i], func(gtx C) D {
+					gtx.Constraints.Min.X = gtx.Constraints.Max.X
+					return layout.UniformInset(values.MarginPadding5).Layout(gtx, func(gtx C) D {
+						return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+							layout.Rigid(pg.theme.Body1(contact.Label).Layout),
+							layout.Rigid(func(gtx C) D {
+								txt := pg.theme.Caption(contact.Address)
+								txt.Color = pg.theme.Color.Gray
+								return txt.Layout(gtx)
+							}),
+						)
+					})
+				})
+			})
+		}),
+		layout.Rigid(func(gtx C) D {
+			check := pg.addressCheck
+			var txt decredmaterial.Label
+			switch {
+			case check.OwnWallet != "":
+				txt = pg.theme.Caption(fmt.Sprintf("This address belongs to your wallet %s", check.OwnWallet))
+				txt.Color = pg.theme.Color.Orange
+			case check.Contact != nil && !check.WrongNetwork:
+				txt = pg.theme.Caption(fmt.Sprintf("Address book: %s", check.Contact.Label))
+				txt.Color = pg.theme.Color.Gray
+			case pg.paymentRequest.Label != "" || pg.paymentRequest.Message != "":
+				note := pg.paymentRequest.Label
+				if pg.paymentRequest.Message != "" {
+					note = strings.TrimPrefix(note+": "+pg.paymentRequest.Message, ": ")
+				}
+				txt = pg.theme.Caption(fmt.Sprintf("Payment request: %s", note))
+				txt.Color = pg.theme.Color.Gray
+			default:
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, txt.Layout)
+		}),
+	)
+}
+
+// applyPaymentRequest fills the destination and amount from a pasted decred:
+// payment request URI. Invalid requests are left for address validation to
+// report.
+func (pg *sendPage) applyPaymentRequest(uri string) {
+	req, err := wallet.ParsePaymentURI(uri)
+	if err != nil {
+		return
+	}
+
+	pg.paymentRequest = req
+	pg.destinationAddressEditor.Editor.SetText(req.Address)
+	pg.destinationAddressEditor.Editor.SetCaret(len(req.Address), len(req.Address))
+	if req.Amount > 0 {
+		pg.sendMax = false
+		pg.leftExchangeValue, pg.rightExchangeValue = "DCR", pg.fiatCurrency
+		pg.leftAmountEditor.Editor.SetText(strconv.FormatFloat(dcrutil.Amount(req.Amount).ToCoin(), 'f', -1, 64))
+	}
+}
+
+// updateAddressSuggestions checks the destination address and suggests the
+// address book entries matching it until a complete address is entered.
+func (pg *sendPage) updateAddressSuggestions() {
+	address := strings.TrimSpace(pg.destinationAddressEditor.Editor.Text())
+	pg.addressCheck = pg.wallet.CheckAddress(address)
+	pg.contactSuggestions = nil
+	if pg.addressCheck.Network != "" {
+		return
+	}
+
+	suggestions := pg.wallet.SuggestContacts(address)
+	if len(suggestions) > maxContactSuggestions {
+		suggestions = suggestions[:maxContactSuggestions]
+	}
+	pg.contactSuggestions = suggestions
+}
+
 func (pg *sendPage) feeSection(gtx layout.Context) layout.Dimensions {
 	collapsibleHeader := func(gtx C) D {
 		return layout.Flex{}.Layout(gtx,
@@ -456,6 +616,11 @@ func (pg *sendPage) feeSection(gtx layout.Context) layout.Dimensions {
 						layout.Rigid(func(gtx C) D {
 							return pg.contentRow(gtx, "Fee rate", "10 atoms/Byte")
 						}),
+						layout.Rigid(func(gtx C) D {
+							return layout.Inset{Top: values.MarginPadding5}.Layout(gtx, func(gtx C) D {
+								return pg.contentRow(gtx, "Inputs", pg.coinControlSummary())
+							})
+						}),
 					)
 				})
 			})
@@ -562,8 +727,8 @@ func (pg *sendPage) contentRow(gtx layout.Context, leftValue, rightValue string)
 
 func (pg *sendPage) validate() bool {
 	if pg.sendToOption == "Address" {
-		isAmountValid := pg.validateLeftAmount()
-		if pg.rightAmountEditor.Editor.Focused() {
+		isAmountValid := pg.sendMax || pg.validateLeftAmount()
+		if !pg.sendMax && pg.rightAmountEditor.Editor.Focused() {
 			isAmountValid = pg.validateRightAmount()
 		}
 
@@ -580,7 +745,7 @@ func (pg *sendPage) validate() bool {
 			return false
 		}
 
-		if !isAmountValid {
+		if !isAmountValid || !pg.validateRecipients() {
 			pg.nextButton.Background = pg.theme.Color.Hint
 			return false
 		}
@@ -606,6 +771,10 @@ func (pg *sendPage) validateDestinationAddress() bool {
 	if pg.inputsNotEmpty(pg.destinationAddressEditor.Editor) {
 		isValid, _ := pg.wallet.IsAddressValid(pg.destinationAddressEditor.Editor.Text())
 		if !isValid {
+			if net, ok := wallet.AddressNetwork(pg.destinationAddressEditor.Editor.Text()); ok {
+				pg.destinationAddressEditor.SetError(fmt.Sprintf("This is a %s address", net))
+				return false
+			}
 			pg.destinationAddressEditor.SetError("Invalid address")
 			return false
 		}
@@ -658,7 +827,7 @@ func (pg *sendPage) inputsNotEmpty(editors ...*widget.Editor) bool {
 
 func (pg *sendPage) calculateValues(isUpdateAmountInput bool) {
 	defaultLeftValues := fmt.Sprintf("- %s", "DCR")
-	defaultRightValues := "($ -)"
+	defaultRightValues := fmt.Sprintf("(- %s)", pg.fiatCurrency)
 
 	pg.leftTransactionFeeValue = defaultLeftValues
 	pg.rightTransactionFeeValue = defaultRightValues
@@ -693,11 +862,16 @@ func (pg *sendPage) calculateValues(isUpdateAmountInput bool) {
 }
 
 func (pg *sendPage) updateAmountInputsValues(isUpdateAmountInput bool) {
+	if pg.sendMax {
+		pg.setDestinationAddr(0)
+		return
+	}
+
 	switch {
-	case pg.leftExchangeValue == "USD" && pg.LastTradeRate != "" && pg.leftAmountEditor.Editor.Focused():
+	case pg.leftExchangeValue == pg.fiatCurrency && pg.LastTradeRate != "" && pg.leftAmountEditor.Editor.Focused():
 		pg.rightAmountEditor.Editor.SetText(fmt.Sprintf("%f", pg.amountUSDtoDCR))
 		pg.setDestinationAddr(pg.amountUSDtoDCR)
-	case pg.leftExchangeValue == "USD" && pg.LastTradeRate != "" && pg.rightAmountEditor.Editor.Focused():
+	case pg.leftExchangeValue == pg.fiatCurrency && pg.LastTradeRate != "" && pg.rightAmountEditor.Editor.Focused():
 		pg.leftAmountEditor.Editor.SetText(fmt.Sprintf("%f", pg.amountDCRtoUSD))
 		pg.setDestinationAddr(pg.inputAmount)
 	case pg.leftExchangeValue == "DCR" && pg.LastTradeRate != "" && pg.rightAmountEditor.Editor.Focused():
@@ -734,11 +908,12 @@ func (pg *sendPage) setDestinationAddr(sendAmount float64) {
 	}
 
 	pg.amountAtoms = int64(amount)
-	if pg.amountAtoms == 0 {
+	pg.firstAmountAtoms = pg.amountAtoms
+	if pg.amountAtoms == 0 && !pg.sendMax {
 		return
 	}
 
-	pg.txAuthor.RemoveSendDestination(0)
+	pg.clearSendDestinations()
 	addr := pg.destinationAddressEditor.Editor.Text()
 	if pg.sendToOption == "My account" {
 		selectedAccount := pg.destinationAccountSelector.selectedAccount
@@ -750,30 +925,34 @@ func (pg *sendPage) setDestinationAddr(sendAmount float64) {
 			addr = address
 		}
 	}
-	pg.txAuthor.AddSendDestination(addr, pg.amountAtoms, false)
+	if err := pg.txAuthor.AddSendDestination(addr, pg.amountAtoms, pg.sendMax); err == nil {
+		pg.destinationCount++
+	}
+	pg.addRecipientDestinations()
 }
 
 func (pg *sendPage) amountValues() amountValue {
 	pg.confirmTxModal.totalCostDCR = pg.txFee + pg.amountAtoms
 	txFeeValueUSD := dcrutil.Amount(pg.txFee).ToCoin() * pg.usdExchangeRate
+	sendAmountUSD := dcrutil.Amount(pg.amountAtoms).ToCoin() * pg.usdExchangeRate
 	switch {
-	case pg.leftExchangeValue == "USD" && pg.LastTradeRate != "":
+	case pg.leftExchangeValue == pg.fiatCurrency && pg.LastTradeRate != "":
 		return amountValue{
 			sendAmountDCR:            dcrutil.Amount(pg.amountAtoms).String(),
-			sendAmountUSD:            fmt.Sprintf("$ %f", dcrutil.Amount(pg.amountAtoms).ToCoin()*pg.usdExchangeRate),
-			leftTransactionFeeValue:  fmt.Sprintf("%f USD", txFeeValueUSD),
+			sendAmountUSD:            fmt.Sprintf("%f %s", sendAmountUSD, pg.fiatCurrency),
+			leftTransactionFeeValue:  fmt.Sprintf("%f %s", txFeeValueUSD, pg.fiatCurrency),
 			rightTransactionFeeValue: fmt.Sprintf("(%s)", dcrutil.Amount(pg.txFee).String()),
-			leftTotalCostValue:       fmt.Sprintf("%s USD", strconv.FormatFloat(pg.inputAmount+txFeeValueUSD, 'f', 7, 64)),
+			leftTotalCostValue:       fmt.Sprintf("%s %s", strconv.FormatFloat(sendAmountUSD+txFeeValueUSD, 'f', 7, 64), pg.fiatCurrency),
 			rightTotalCostValue:      fmt.Sprintf("(%s )", dcrutil.Amount(pg.totalCostDCR).String()),
 		}
 	case pg.leftExchangeValue == "DCR" && pg.LastTradeRate != "":
 		return amountValue{
 			sendAmountDCR:            dcrutil.Amount(pg.amountAtoms).String(),
-			sendAmountUSD:            fmt.Sprintf("$ %s", strconv.FormatFloat(pg.amountDCRtoUSD, 'f', 2, 64)),
+			sendAmountUSD:            fmt.Sprintf("%s %s", strconv.FormatFloat(sendAmountUSD, 'f', 2, 64), pg.fiatCurrency),
 			leftTransactionFeeValue:  dcrutil.Amount(pg.txFee).String(),
-			rightTransactionFeeValue: fmt.Sprintf("($ %s)", strconv.FormatFloat(txFeeValueUSD, 'f', 2, 64)),
+			rightTransactionFeeValue: fmt.Sprintf("(%s %s)", strconv.FormatFloat(txFeeValueUSD, 'f', 2, 64), pg.fiatCurrency),
 			leftTotalCostValue:       dcrutil.Amount(pg.totalCostDCR).String(),
-			rightTotalCostValue:      fmt.Sprintf("($ %s)", strconv.FormatFloat(pg.amountDCRtoUSD+txFeeValueUSD, 'f', 2, 64)),
+			rightTotalCostValue:      fmt.Sprintf("(%s %s)", strconv.FormatFloat(sendAmountUSD+txFeeValueUSD, 'f', 2, 64), pg.fiatCurrency),
 		}
 	default:
 		return amountValue{
@@ -786,6 +965,12 @@ func (pg *sendPage) amountValues() amountValue {
 }
 
 func (pg *sendPage) getTxFee() {
+	inputsAmount, err := pg.applyCoinControl()
+	if err != nil {
+		pg.feeEstimationError(err.Error(), "inputs")
+		return
+	}
+
 	// calculate transaction fee
 	feeAndSize, err := pg.txAuthor.EstimateFeeAndSize()
 	if err != nil {
@@ -794,6 +979,20 @@ func (pg *sendPage) getTxFee() {
 	}
 
 	pg.txFee = feeAndSize.Fee.AtomValue
+	pg.sendMaxAtoms = 0
+	switch {
+	case !pg.hasSendMax():
+	case inputsAmount >= 0:
+		// the author of a transaction spending chosen inputs does not
+		// report which output is change, so the max amount is what the
+		// inputs leave after the other destinations and the fee
+		pg.sendMaxAtoms = inputsAmount - pg.txAuthor.TotalSendAmount().AtomValue - pg.txFee
+		pg.amountAtoms += pg.sendMaxAtoms
+	case feeAndSize.Change != nil:
+		// the destination receiving the max amount takes the place of change
+		pg.sendMaxAtoms = feeAndSize.Change.AtomValue
+		pg.amountAtoms += pg.sendMaxAtoms
+	}
 	pg.txFeeSize = fmt.Sprintf("%v Bytes", feeAndSize.EstimatedSignedSize)
 }
 
@@ -867,11 +1066,18 @@ func (pg *sendPage) updateDefaultValues() {
 
 func (pg *sendPage) resetFields() {
 	pg.destinationAddressEditor.SetError("")
+	pg.contactSuggestions = nil
+	pg.addressCheck = wallet.AddressCheck{}
+	pg.paymentRequest = wallet.PaymentRequest{}
 	pg.leftAmountEditor.Editor.SetText("")
 	pg.rightAmountEditor.Editor.SetText("")
 	pg.passwordEditor.Editor.SetText("")
 	pg.leftTotalCostValue = ""
 	pg.rightTotalCostValue = ""
+	pg.recipients = nil
+	pg.sendMax = false
+	pg.recipientsCSVEditor.Editor.SetText("")
+	pg.recipientsCSVEditor.SetError("")
 }
 
 func (pg *sendPage) resetErrorText() {
@@ -884,12 +1090,25 @@ func (pg *sendPage) resetErrorText() {
 }
 
 func (pg *sendPage) fetchExchangeValue() {
-	go func() {
-		err := pg.wallet.GetUSDExchangeValues(&pg)
-		if err != nil {
+	id := pg.wallet.ExchangeRate()
+	pg.common.onResponse(id, func(resp wallet.Response) {
+		if resp.Err != nil {
+			if resp.Err != wallet.ErrNoExchangeRate {
+				log.Errorf("Error fetching exchange rate: %v", resp.Err)
+			}
 			pg.updateExchangeError()
+			return
+		}
+
+		rate := resp.Resp.(*wallet.ExchangeRate)
+		if pg.leftExchangeValue == pg.fiatCurrency {
+			pg.leftExchangeValue = rate.Currency
+		} else {
+			pg.rightExchangeValue = rate.Currency
 		}
-	}()
+		pg.fiatCurrency = rate.Currency
+		pg.LastTradeRate = strconv.FormatFloat(rate.Rate, 'f', -1, 64)
+	})
 }
 
 func (pg *sendPage) setMaxAmount() {
@@ -928,6 +1147,25 @@ func (pg *sendPage) setMaxAmount() {
 	}
 }
 
+// maxAmountClicked fills in the max amount, or makes the first destination
+// receive the max amount when there are other destinations or the outputs to
+// spend were chosen.
+func (pg *sendPage) maxAmountClicked() {
+	if (pg.sendToOption == "Address" && len(pg.recipients) > 0) || len(pg.selectedInputs()) > 0 {
+		pg.setSendMax(-1)
+		return
+	}
+	pg.setMaxAmount()
+}
+
+// clearSendMax stops the first destination receiving the max amount once an
+// amount is typed in.
+func (pg *sendPage) clearSendMax(evt widget.EditorEvent) {
+	if _, ok := evt.(widget.ChangeEvent); ok {
+		pg.sendMax = false
+	}
+}
+
 func (pg *sendPage) updateAmountField(spendableBalanceDCR float64) {
 	if !pg.usdExchangeSet {
 		pg.leftAmountEditor.Editor.SetText(strconv.FormatFloat(spendableBalanceDCR, 'f', 7, 64))
@@ -937,7 +1175,7 @@ func (pg *sendPage) updateAmountField(spendableBalanceDCR float64) {
 		spendableBalanceUSD := spendableBalanceDCR * pg.usdExchangeRate
 
 		switch {
-		case pg.leftExchangeValue == "USD":
+		case pg.leftExchangeValue == pg.fiatCurrency:
 			pg.leftAmountEditor.Editor.SetText(strconv.FormatFloat(spendableBalanceUSD, 'f', 7, 64))
 			pg.rightAmountEditor.Editor.SetText(strconv.FormatFloat(spendableBalanceDCR, 'f', 7, 64))
 		case pg.leftExchangeValue == "DCR":
@@ -1002,36 +1240,52 @@ func (pg *sendPage) Handle() {
 	}
 
 	currencyExchangeValue := pg.wallet.ReadStringConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey)
-	pg.usdExchangeSet = false
-	if currencyExchangeValue == page.USDExchangeValue {
-		pg.usdExchangeSet = true
-	}
+	_, _, pg.usdExchangeSet = wallet.ParseCurrencyConversion(currencyExchangeValue)
 
 	for range pg.destinationAddressEditor.Editor.Events() {
+		address := pg.destinationAddressEditor.Editor.Text()
+		if wallet.IsPaymentURI(address) {
+			pg.applyPaymentRequest(address)
+		} else if address != pg.paymentRequest.Address {
+			pg.paymentRequest = wallet.PaymentRequest{}
+		}
+		pg.updateAddressSuggestions()
 		pg.calculateValues(true)
 	}
 
+	for i, contact := range pg.contactSuggestions {
+		for pg.suggestionButtons[i].Clicked() {
+			pg.destinationAddressEditor.Editor.SetText(contact.Address)
+			pg.updateAddressSuggestions()
+			pg.calculateValues(true)
+		}
+	}
+
 	for pg.currencySwap.Clicked() {
 		if pg.LastTradeRate != "" {
 			if pg.leftExchangeValue == "DCR" {
-				pg.leftExchangeValue = "USD"
+				pg.leftExchangeValue = pg.fiatCurrency
 				pg.rightExchangeValue = "DCR"
 			} else {
 				pg.leftExchangeValue = "DCR"
-				pg.rightExchangeValue = "USD"
+				pg.rightExchangeValue = pg.fiatCurrency
 			}
 		}
 		pg.calculateValues(true)
 	}
 
+	pg.handleRecipients()
+
 	for _, evt := range pg.leftAmountEditor.Editor.Events() {
 		if pg.leftAmountEditor.Editor.Focused() {
+			pg.clearSendMax(evt)
 			pg.handleEditorChange(evt)
 		}
 	}
 
 	for _, evt := range pg.rightAmountEditor.Editor.Events() {
 		if pg.rightAmountEditor.Editor.Focused() {
+			pg.clearSendMax(evt)
 			pg.handleEditorChange(evt)
 		}
 	}
@@ -1060,6 +1314,8 @@ func (pg *sendPage) Handle() {
 		pg.leftAmountEditor.Editor.SetText("")
 		pg.rightAmountEditor.Editor.SetText("")
 		pg.calculateErrorText = ""
+		pg.sendMax = false
+		pg.destinationCount = 0
 		c.wallet.CreateTransaction(sendAcct.WalletID, sendAcct.Number, pg.txAuthorErrChan)
 	}
 
@@ -1067,7 +1323,7 @@ func (pg *sendPage) Handle() {
 	if pg.rightAmountEditor.Editor.Focused() {
 		activeAmountEditor = pg.rightAmountEditor.Editor
 	}
-	if !pg.inputsNotEmpty(pg.destinationAddressEditor.Editor, activeAmountEditor) {
+	if !pg.sendMax && !pg.inputsNotEmpty(pg.destinationAddressEditor.Editor, activeAmountEditor) {
 		pg.balanceAfterSend(true)
 	}
 
@@ -1080,6 +1336,7 @@ func (pg *sendPage) Handle() {
 	for pg.nextButton.Button.Clicked() {
 		if pg.validate() && pg.calculateErrorText == "" {
 			pg.comfirmModalData.destinationAddress = pg.destinationAddressEditor.Editor.Text()
+			pg.comfirmModalData.outputs = pg.confirmOutputs()
 			pg.confirmTxModal.Show()
 			pg.passwordEditor.Editor.Focus()
 		}
@@ -1093,6 +1350,12 @@ func (pg *sendPage) Handle() {
 		pg.resetFields()
 	}
 
+	for pg.coinControlBtn.Button.Clicked() {
+		pg.isMoreOption = false
+		c.utxoAccount = sendAcct
+		c.changePage(PageUTXO)
+	}
+
 	select {
 	case err := <-pg.txAuthorErrChan:
 		pg.calculateErrorText = err.Error()
@@ -1110,11 +1373,11 @@ func (pg *sendPage) Handle() {
 
 	if pg.leftAmountEditor.CustomButton.Button.Clicked() {
 		pg.leftAmountEditor.Editor.Focus()
-		pg.setMaxAmount()
+		pg.maxAmountClicked()
 	}
 	if pg.rightAmountEditor.CustomButton.Button.Clicked() {
 		pg.rightAmountEditor.Editor.Focus()
-		pg.setMaxAmount()
+		pg.maxAmountClicked()
 	}
 }
 
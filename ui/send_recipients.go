@@ -0,0 +1,326 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gioui.org/layout"
+	"gioui.org/widget"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+// recipientRow is a destination added to the send page after the first one.
+// Its amount is always entered in DCR.
+type recipientRow struct {
+	addressEditor decredmaterial.Editor
+	amountEditor  decredmaterial.Editor
+	removeButton  decredmaterial.IconButton
+
+	// sendMax pays the row everything left after the other outputs and the fee.
+	sendMax bool
+}
+
+func newRecipientRow(common *pageCommon) *recipientRow {
+	r := &recipientRow{}
+
+	r.addressEditor = common.theme.Editor(new(widget.Editor), "Address")
+	r.addressEditor.Editor.SingleLine = true
+
+	r.amountEditor = common.theme.Editor(new(widget.Editor), "Amount (DCR)")
+	r.amountEditor.Editor.SingleLine = true
+	r.amountEditor.IsCustomButton = true
+	r.amountEditor.CustomButton.Background = common.theme.Color.Gray
+	r.amountEditor.CustomButton.Inset = layout.UniformInset(values.MarginPadding2)
+	r.amountEditor.CustomButton.Text = "Max"
+	r.amountEditor.CustomButton.CornerRadius = values.MarginPadding0
+
+	r.removeButton = common.theme.PlainIconButton(new(widget.Clickable), common.icons.contentClear)
+	r.removeButton.Color = common.theme.Color.Gray3
+	r.removeButton.Size = values.MarginPadding20
+	r.removeButton.Inset = layout.UniformInset(values.MarginPadding0)
+
+	return r
+}
+
+func (r *recipientRow) setSendMax(sendMax bool) {
+	r.sendMax = sendMax
+	if sendMax {
+		r.amountEditor.Editor.SetText("")
+		r.amountEditor.Hint = "Max amount"
+		return
+	}
+	r.amountEditor.Hint = "Amount (DCR)"
+}
+
+func (r *recipientRow) setRecipient(recipient wallet.Recipient) {
+	r.addressEditor.Editor.SetText(recipient.Address)
+	r.setSendMax(recipient.SendMax)
+	if !recipient.SendMax {
+		r.amountEditor.Editor.SetText(strconv.FormatFloat(dcrutil.Amount(recipient.Amount).ToCoin(), 'f', -1, 64))
+	}
+}
+
+// recipient returns the destination entered in the row without checking the
+// address.
+func (r *recipientRow) recipient() (wallet.Recipient, error) {
+	recipient := wallet.Recipient{
+		Address: r.addressEditor.Editor.Text(),
+		SendMax: r.sendMax,
+	}
+	if r.sendMax {
+		return recipient, nil
+	}
+
+	dcr, err := strconv.ParseFloat(r.amountEditor.Editor.Text(), 64)
+	if err != nil {
+		return recipient, fmt.Errorf("invalid amount")
+	}
+	amount, err := dcrutil.NewAmount(dcr)
+	if err != nil {
+		return recipient, err
+	}
+	recipient.Amount = int64(amount)
+	return recipient, nil
+}
+
+// validate checks the row and shows any error on its editors.
+func (r *recipientRow) validate(wal *wallet.Wallet) bool {
+	r.addressEditor.SetError("")
+	r.amountEditor.SetError("")
+
+	address := r.addressEditor.Editor.Text()
+	if isValid, _ := wal.IsAddressValid(address); !isValid {
+		if address != "" {
+			r.addressEditor.SetError("Invalid address")
+		}
+		return false
+	}
+
+	recipient, err := r.recipient()
+	if err == nil && !recipient.SendMax && recipient.Amount <= 0 {
+		err = fmt.Errorf("invalid amount")
+	}
+	if err != nil {
+		if r.amountEditor.Editor.Text() != "" {
+			r.amountEditor.SetError("Invalid amount")
+		}
+		return false
+	}
+	return true
+}
+
+func (r *recipientRow) layout(gtx layout.Context) layout.Dimensions {
+	return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+			layout.Flexed(0.6, func(gtx C) D {
+				return layout.Inset{Right: values.MarginPadding10}.Layout(gtx, r.addressEditor.Layout)
+			}),
+			layout.Flexed(0.4, r.amountEditor.Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, r.removeButton.Layout)
+			}),
+		)
+	})
+}
+
+// recipientsLayout lays out the destinations added after the first one and
+// the controls to add more.
+func (pg *sendPage) recipientsLayout(gtx layout.Context) layout.Dimensions {
+	rows := make([]layout.FlexChild, 0, len(pg.recipients)+1)
+	for _, r := range pg.recipients {
+		rows = append(rows, layout.Rigid(r.layout))
+	}
+	rows = append(rows, layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, pg.recipientsCSVEditor.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, pg.importRecipientsButton.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, pg.addRecipientButton.Layout)
+				}),
+			)
+		})
+	}))
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
+
+func (pg *sendPage) addRecipient() *recipientRow {
+	r := newRecipientRow(pg.common)
+	pg.recipients = append(pg.recipients, r)
+	return r
+}
+
+// importRecipients fills the destinations from the recipients CSV file. The
+// first recipient goes into the first destination, which is entered in DCR.
+func (pg *sendPage) importRecipients() {
+	pg.recipientsCSVEditor.SetError("")
+	f, err := os.Open(pg.recipientsCSVEditor.Editor.Text())
+	if err != nil {
+		pg.recipientsCSVEditor.SetError(err.Error())
+		return
+	}
+	defer f.Close()
+
+	recipients, err := wallet.ParseRecipientsCSV(f)
+	if err != nil {
+		pg.recipientsCSVEditor.SetError(err.Error())
+		return
+	}
+
+	pg.leftExchangeValue, pg.rightExchangeValue = "DCR", pg.fiatCurrency
+	pg.destinationAddressEditor.Editor.SetText(recipients[0].Address)
+	pg.leftAmountEditor.Editor.SetText("")
+	pg.rightAmountEditor.Editor.SetText("")
+	pg.sendMax = recipients[0].SendMax
+	if !pg.sendMax {
+		pg.leftAmountEditor.Editor.SetText(strconv.FormatFloat(dcrutil.Amount(recipients[0].Amount).ToCoin(), 'f', -1, 64))
+	}
+
+	pg.recipients = nil
+	for _, recipient := range recipients[1:] {
+		pg.addRecipient().setRecipient(recipient)
+	}
+	pg.recipientsCSVEditor.Editor.SetText("")
+	pg.common.notify(fmt.Sprintf("%d recipients imported", len(recipients)), true)
+	pg.calculateValues(false)
+}
+
+// setSendMax makes the destination at index receive the max amount, or the
+// first destination if index is negative. Only one destination can receive
+// the max amount.
+func (pg *sendPage) setSendMax(index int) {
+	pg.sendMax = index < 0
+	if pg.sendMax {
+		pg.leftAmountEditor.Editor.SetText("")
+		pg.rightAmountEditor.Editor.SetText("")
+	}
+	for i, r := range pg.recipients {
+		r.setSendMax(i == index)
+	}
+	pg.calculateValues(false)
+}
+
+func (pg *sendPage) hasSendMax() bool {
+	if pg.sendMax {
+		return true
+	}
+	for _, r := range pg.recipients {
+		if r.sendMax {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRecipients checks the destinations added after the first one.
+func (pg *sendPage) validateRecipients() bool {
+	recipients := make([]wallet.Recipient, 0, len(pg.recipients))
+	for _, r := range pg.recipients {
+		if !r.validate(pg.wallet) {
+			return false
+		}
+		recipient, _ := r.recipient()
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return true
+	}
+	return wallet.ValidateRecipients(recipients) == nil
+}
+
+// clearSendDestinations removes the destinations added to the tx author.
+func (pg *sendPage) clearSendDestinations() {
+	for ; pg.destinationCount > 0; pg.destinationCount-- {
+		pg.txAuthor.RemoveSendDestination(0)
+	}
+}
+
+// addRecipientDestinations adds the destinations after the first one to the
+// tx author and to the amount sent.
+func (pg *sendPage) addRecipientDestinations() {
+	if pg.sendToOption != "Address" {
+		return
+	}
+	for _, r := range pg.recipients {
+		recipient, err := r.recipient()
+		if err != nil {
+			continue
+		}
+		if err := pg.txAuthor.AddSendDestination(recipient.Address, recipient.Amount, recipient.SendMax); err != nil {
+			pg.feeEstimationError(err.Error(), "destination")
+			continue
+		}
+		pg.destinationCount++
+		pg.amountAtoms += recipient.Amount
+	}
+}
+
+// confirmOutputs lists every output of the transaction for the confirm modal.
+func (pg *sendPage) confirmOutputs() []sendOutput {
+	amount := func(atoms int64, sendMax bool) string {
+		if sendMax {
+			atoms = pg.sendMaxAtoms
+		}
+		return dcrutil.Amount(atoms).String()
+	}
+
+	outputs := []sendOutput{{
+		address: pg.destinationAddressEditor.Editor.Text(),
+		amount:  amount(pg.firstAmountAtoms, pg.sendMax),
+	}}
+	if pg.sendToOption != "Address" {
+		return outputs
+	}
+	for _, r := range pg.recipients {
+		recipient, _ := r.recipient()
+		outputs = append(outputs, sendOutput{
+			address: recipient.Address,
+			amount:  amount(recipient.Amount, recipient.SendMax),
+		})
+	}
+	return outputs
+}
+
+// handleRecipients handles the events of the destinations added after the
+// first one.
+func (pg *sendPage) handleRecipients() {
+	for pg.addRecipientButton.Button.Clicked() {
+		pg.addRecipient()
+	}
+
+	for pg.importRecipientsButton.Button.Clicked() {
+		pg.importRecipients()
+	}
+
+	for i := 0; i < len(pg.recipients); i++ {
+		r := pg.recipients[i]
+		if r.removeButton.Button.Clicked() {
+			pg.recipients = append(pg.recipients[:i], pg.recipients[i+1:]...)
+			pg.calculateValues(false)
+			i--
+			continue
+		}
+
+		if r.amountEditor.CustomButton.Button.Clicked() {
+			pg.setSendMax(i)
+		}
+
+		for range r.addressEditor.Editor.Events() {
+			pg.calculateValues(false)
+		}
+
+		for _, evt := range r.amountEditor.Editor.Events() {
+			if _, ok := evt.(widget.ChangeEvent); ok {
+				r.sendMax = false
+				pg.calculateValues(false)
+			}
+		}
+	}
+}
@@ -60,10 +60,19 @@ func (win *Window) updateStates(update interface{}) {
 	case *wallet.VSP:
 		win.vspInfo = e
 		return
+	case *wallet.ExchangeRate:
+		return
 	case *wallet.Proposals:
 		win.states.loading = false
 		win.proposals = e
 		return
+	case *wallet.ProposalVoteNotice:
+		if e.Finished {
+			win.notifyOnSuccess("Voting finished on " + e.Name)
+		} else {
+			win.notifyOnSuccess("Voting started on " + e.Name)
+		}
+		return
 	case wallet.Restored:
 		win.states.creating = false
 		op.InvalidateOp{}.Add(win.ops)
@@ -0,0 +1,380 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const ModalTicketBuyer = "ticket_buyer_modal"
+
+// ticketBuyerLogLines is the number of auto-buyer log entries shown for each
+// wallet on the tickets page.
+const ticketBuyerLogLines = 5
+
+// ticketBuyerModal saves the auto-buyer settings of a wallet and starts it.
+type ticketBuyerModal struct {
+	*pageCommon
+	randomID        string
+	modal           decredmaterial.Modal
+	accountSelector *accountSelector
+	vspEditor       decredmaterial.Editor
+	balanceEditor   decredmaterial.Editor
+	passwordEditor  decredmaterial.Editor
+	btnStart        decredmaterial.Button
+	btnCancel       decredmaterial.Button
+
+	account    *dcrlibwallet.Account
+	isStarting bool
+}
+
+// newTicketBuyerModal opens the settings of the wallet of account, or of the
+// first wallet that can buy tickets if account is nil. vspHost is suggested
+// for wallets without settings.
+func newTicketBuyerModal(common *pageCommon, account *dcrlibwallet.Account, vspHost string) *ticketBuyerModal {
+	tm := &ticketBuyerModal{
+		pageCommon: common,
+		randomID:   fmt.Sprintf("%s-%d", ModalTicketBuyer, generateRandomNumber()),
+		modal:      *common.theme.ModalFloatTitle(),
+		btnStart:   common.theme.Button(new(widget.Clickable), "Start"),
+		btnCancel:  common.theme.Button(new(widget.Clickable), "Cancel"),
+		account:    account,
+	}
+	tm.btnCancel.Background = common.theme.Color.Surface
+	tm.btnCancel.Color = common.theme.Color.Primary
+
+	tm.vspEditor = common.theme.Editor(new(widget.Editor), "VSP")
+	tm.vspEditor.Editor.SingleLine = true
+	tm.vspEditor.Editor.SetText(vspHost)
+	tm.balanceEditor = common.theme.Editor(new(widget.Editor), "Balance to maintain (DCR)")
+	tm.balanceEditor.Editor.SingleLine = true
+	tm.balanceEditor.Editor.SetText("0")
+	tm.passwordEditor = common.theme.EditorPassword(new(widget.Editor), "Spending password")
+	tm.passwordEditor.Editor.SingleLine, tm.passwordEditor.Editor.Submit = true, true
+
+	tm.accountSelector = newAccountSelector(common).
+		title("Purchasing account").
+		accountSelected(tm.loadConfig).
+		accountValidator(func(account *dcrlibwallet.Account) bool {
+			wal := common.multiWallet.WalletWithID(account.WalletID)
+			return account.Number != MaxInt32 && !wal.IsWatchingOnlyWallet()
+		})
+	return tm
+}
+
+func (tm *ticketBuyerModal) ModalID() string {
+	return tm.randomID
+}
+
+func (tm *ticketBuyerModal) OnResume() {
+	if tm.account != nil {
+		tm.accountSelector.setupSelectedAccount(tm.account)
+		tm.loadConfig(tm.account)
+		return
+	}
+	if err := tm.accountSelector.selectFirstWalletValidAccount(); err != nil {
+		tm.notify(err.Error(), false)
+	}
+}
+
+func (tm *ticketBuyerModal) OnDismiss() {}
+
+func (tm *ticketBuyerModal) Show() {
+	tm.showModal(tm)
+}
+
+func (tm *ticketBuyerModal) Dismiss() {
+	tm.dismissModal(tm)
+}
+
+// loadConfig fills the editors with the saved settings of the account's
+// wallet if they are for that account.
+func (tm *ticketBuyerModal) loadConfig(account *dcrlibwallet.Account) {
+	cfg, err := tm.wallet.TicketBuyerConfig(account.WalletID)
+	if err != nil || cfg == nil || cfg.Account != account.Number {
+		return
+	}
+	tm.vspEditor.Editor.SetText(cfg.VSPHost)
+	tm.balanceEditor.Editor.SetText(strconv.FormatFloat(dcrutil.Amount(cfg.BalanceToMaintain).ToCoin(), 'f', -1, 64))
+}
+
+// config returns the settings entered, showing any error on the editors.
+func (tm *ticketBuyerModal) config() (wallet.TicketBuyerConfig, bool) {
+	tm.balanceEditor.SetError("")
+	cfg := wallet.TicketBuyerConfig{VSPHost: tm.vspEditor.Editor.Text()}
+	if account := tm.accountSelector.selectedAccount; account != nil {
+		cfg.Account = account.Number
+	}
+
+	dcr, err := strconv.ParseFloat(tm.balanceEditor.Editor.Text(), 64)
+	if err != nil || dcr < 0 {
+		tm.balanceEditor.SetError("Invalid amount")
+		return cfg, false
+	}
+	amount, err := dcrutil.NewAmount(dcr)
+	if err != nil {
+		tm.balanceEditor.SetError(err.Error())
+		return cfg, false
+	}
+	cfg.BalanceToMaintain = int64(amount)
+	return cfg, true
+}
+
+func (tm *ticketBuyerModal) start() {
+	account := tm.accountSelector.selectedAccount
+	cfg, ok := tm.config()
+	if !ok || account == nil {
+		return
+	}
+	if err := tm.wallet.SetTicketBuyerConfig(account.WalletID, cfg); err != nil {
+		tm.notify(err.Error(), false)
+		return
+	}
+
+	tm.isStarting = true
+	tm.passwordEditor.SetError("")
+	id := tm.wallet.StartTicketBuyer(account.WalletID, []byte(tm.passwordEditor.Editor.Text()))
+	tm.onResponse(id, func(resp wallet.Response) {
+		tm.isStarting = false
+		if resp.Err != nil {
+			if resp.Err.Error() == dcrlibwallet.ErrInvalidPassphrase {
+				tm.passwordEditor.SetError(translateErr(resp.Err))
+				return
+			}
+			tm.notify(resp.Err.Error(), false)
+			return
+		}
+		tm.notify("Ticket auto-buyer started", true)
+		tm.Dismiss()
+	})
+}
+
+func (tm *ticketBuyerModal) canStart() bool {
+	return !tm.isStarting && tm.accountSelector.selectedAccount != nil &&
+		editorsNotEmpty(tm.vspEditor.Editor, tm.balanceEditor.Editor, tm.passwordEditor.Editor)
+}
+
+func (tm *ticketBuyerModal) Handle() {
+	if tm.btnCancel.Button.Clicked() && !tm.isStarting {
+		tm.Dismiss()
+	}
+
+	if tm.canStart() {
+		tm.btnStart.Background = tm.theme.Color.Primary
+	} else {
+		tm.btnStart.Background = tm.theme.Color.Gray1
+	}
+
+	for tm.btnStart.Button.Clicked() {
+		if tm.canStart() {
+			tm.start()
+		}
+	}
+
+	for _, evt := range tm.passwordEditor.Editor.Events() {
+		if _, ok := evt.(widget.SubmitEvent); ok && tm.canStart() {
+			tm.start()
+		}
+	}
+}
+
+func (tm *ticketBuyerModal) Layout(gtx layout.Context) D {
+	w := []layout.Widget{
+		func(gtx C) D {
+			t := tm.theme.H6("Ticket auto-buyer")
+			t.Font.Weight = text.Bold
+			return t.Layout(gtx)
+		},
+		func(gtx C) D {
+			txt := tm.theme.Body2("Tickets are bought on every new block while the spendable balance " +
+				"of the account is above the balance to maintain. The auto-buyer stops when the app is closed.")
+			txt.Color = tm.theme.Color.Gray
+			return txt.Layout(gtx)
+		},
+		tm.accountSelector.Layout,
+		tm.vspEditor.Layout,
+		tm.balanceEditor.Layout,
+		tm.passwordEditor.Layout,
+		func(gtx C) D {
+			return layout.E.Layout(gtx, func(gtx C) D {
+				return layout.Flex{}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Right: values.MarginPadding4}.Layout(gtx, tm.btnCancel.Layout)
+					}),
+					layout.Rigid(tm.btnStart.Layout),
+				)
+			})
+		},
+	}
+	return tm.modal.Layout(gtx, w, 850)
+}
+
+// ticketBuyerWallet is a wallet with auto-buyer settings on the tickets page.
+type ticketBuyerWallet struct {
+	id       int
+	name     string
+	cfg      *wallet.TicketBuyerConfig
+	account  string
+	running  bool
+	startRun decredmaterial.Button
+}
+
+// ticketBuyerWallets returns the wallets with auto-buyer settings, reusing the
+// buttons of wallets already listed.
+func (pg *ticketPage) ticketBuyerWallets() []*ticketBuyerWallet {
+	buyers := make([]*ticketBuyerWallet, 0, len(pg.ticketBuyers))
+	for _, w := range pg.common.sortedWalletList() {
+		cfg, err := pg.wal.TicketBuyerConfig(w.ID)
+		if err != nil || cfg == nil {
+			continue
+		}
+
+		var tb *ticketBuyerWallet
+		for _, existing := range pg.ticketBuyers {
+			if existing.id == w.ID {
+				tb = existing
+			}
+		}
+		if tb == nil {
+			tb = &ticketBuyerWallet{id: w.ID, startRun: pg.th.Button(new(widget.Clickable), "Start")}
+			tb.startRun.TextSize = values.TextSize12
+		}
+		tb.name, tb.cfg = w.Name, cfg
+		tb.account = fmt.Sprintf("account %d", cfg.Account)
+		if account, err := w.GetAccount(cfg.Account); err == nil {
+			tb.account = account.Name
+		}
+		tb.running = pg.wal.TicketBuyerRunning(w.ID)
+		if tb.running {
+			tb.startRun.Text = "Stop"
+			tb.startRun.Background = pg.th.Color.Danger
+		} else {
+			tb.startRun.Text = "Start"
+			tb.startRun.Background = pg.th.Color.Primary
+		}
+		buyers = append(buyers, tb)
+	}
+	return buyers
+}
+
+// handleTicketBuyer starts and stops auto-buyers from the switch on the
+// ticket price card and the buttons of the auto-buyer card.
+func (pg *ticketPage) handleTicketBuyer() {
+	c := pg.common
+	pg.ticketBuyers = pg.ticketBuyerWallets()
+
+	anyRunning := false
+	for _, tb := range pg.ticketBuyers {
+		anyRunning = anyRunning || tb.running
+	}
+
+	if pg.autoPurchaseEnabled.Changed() {
+		if pg.autoPurchaseEnabled.Value {
+			newTicketBuyerModal(c, nil, c.wallet.GetRememberVSP()).Show()
+		} else {
+			for _, tb := range pg.ticketBuyers {
+				pg.wal.StopTicketBuyer(tb.id)
+			}
+			anyRunning = false
+		}
+	}
+	pg.autoPurchaseEnabled.Value = anyRunning
+
+	for _, tb := range pg.ticketBuyers {
+		for tb.startRun.Button.Clicked() {
+			if tb.running {
+				pg.wal.StopTicketBuyer(tb.id)
+				continue
+			}
+			account, err := c.multiWallet.WalletWithID(tb.id).GetAccount(tb.cfg.Account)
+			if err != nil {
+				c.notify(err.Error(), false)
+				continue
+			}
+			newTicketBuyerModal(c, account, tb.cfg.VSPHost).Show()
+		}
+	}
+}
+
+func (pg *ticketPage) ticketBuyerSection(gtx layout.Context, c *pageCommon) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		tit := c.theme.Label(values.TextSize14, "Auto-buyer")
+		tit.Color = c.theme.Color.Gray2
+		rows := []layout.FlexChild{layout.Rigid(tit.Layout)}
+
+		if len(pg.ticketBuyers) == 0 {
+			txt := c.theme.Body2("Turn on the switch above to buy tickets automatically")
+			txt.Color = c.theme.Color.Gray
+			rows = append(rows, layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, txt.Layout)
+			}))
+		}
+
+		for _, tb := range pg.ticketBuyers {
+			tb := tb
+			rows = append(rows, layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+					return pg.ticketBuyerLayout(gtx, c, tb)
+				})
+			}))
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+	})
+}
+
+func (pg *ticketPage) ticketBuyerLayout(gtx layout.Context, c *pageCommon, tb *ticketBuyerWallet) layout.Dimensions {
+	status := c.theme.Caption("Stopped")
+	status.Color = c.theme.Color.Gray
+	if tb.running {
+		status.Text = "Running"
+		status.Color = c.theme.Color.Success
+	}
+	settings := c.theme.Caption(fmt.Sprintf("From %s through %s, keeping %s", tb.account, tb.cfg.VSPHost,
+		dcrutil.Amount(tb.cfg.BalanceToMaintain)))
+	settings.Color = c.theme.Color.Gray
+
+	rows := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(func(gtx C) D {
+							return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+								layout.Rigid(c.theme.Body1(tb.name).Layout),
+								layout.Rigid(func(gtx C) D {
+									return layout.Inset{Left: values.MarginPadding8}.Layout(gtx, status.Layout)
+								}),
+							)
+						}),
+						layout.Rigid(settings.Layout),
+					)
+				}),
+				layout.Rigid(tb.startRun.Layout),
+			)
+		}),
+	}
+
+	logs := pg.wal.TicketBuyerLog(tb.id)
+	if len(logs) > ticketBuyerLogLines {
+		logs = logs[:ticketBuyerLogLines]
+	}
+	for _, entry := range logs {
+		line := c.theme.Caption(entry.Time.Format("Jan 2 15:04") + "  " + entry.String())
+		if entry.Err != "" {
+			line.Color = c.theme.Color.Danger
+		}
+		rows = append(rows, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, line.Layout)
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
@@ -35,6 +35,8 @@ type ticketPageList struct {
 	wallets []*dcrlibwallet.Wallet
 
 	backButton decredmaterial.IconButton
+
+	vspButton decredmaterial.Button
 }
 
 func TicketPageList(c *pageCommon) Page {
@@ -47,8 +49,11 @@ func TicketPageList(c *pageCommon) Page {
 		isGridView:     true,
 
 		wallets: c.multiWallet.AllWallets(),
+
+		vspButton: c.theme.Button(new(widget.Clickable), "VSP status"),
 	}
 	pg.backButton, _ = c.SubPageHeaderButtons()
+	pg.vspButton.TextSize = values.TextSize12
 
 	pg.orderDropDown = createOrderDropDown(c)
 	pg.ticketTypeDropDown = c.theme.DropDown([]decredmaterial.DropDownItem{
@@ -177,7 +182,12 @@ func (pg *ticketPageList) dropDowns(gtx layout.Context) layout.Dimensions {
 			return pg.walletDropDown.Layout(gtx)
 		}),
 		layout.Rigid(func(gtx C) D {
-			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{
+						Left: values.MarginPadding5,
+					}.Layout(gtx, pg.vspButton.Layout)
+				}),
 				layout.Rigid(func(gtx C) D {
 					return layout.Inset{
 						Left: values.MarginPadding5,
@@ -323,6 +333,10 @@ func (pg *ticketPageList) Handle() {
 		pg.isGridView = !pg.isGridView
 	}
 
+	if pg.vspButton.Button.Clicked() {
+		newVSPTicketStatusModal(pg.common, pg.wallets[pg.walletDropDown.SelectedIndex()].ID).Show()
+	}
+
 	sortSelection := pg.orderDropDown.SelectedIndex()
 	if pg.filterSorter != sortSelection {
 		pg.filterSorter = sortSelection
@@ -52,6 +52,8 @@ type ticketPage struct {
 	autoPurchaseEnabled     *widget.Bool
 	toTickets               decredmaterial.TextAndIconButton
 	toTicketsActivity       decredmaterial.TextAndIconButton
+	toAgendas               decredmaterial.TextAndIconButton
+	toTreasury              decredmaterial.TextAndIconButton
 	purchaseErrChan         chan error
 
 	vspInfo          **wallet.VSP
@@ -64,9 +66,17 @@ type ticketPage struct {
 	inputVSP         decredmaterial.Editor
 	spendingPassword decredmaterial.Editor
 	addVSP           decredmaterial.Button
+	manageVSPs       decredmaterial.TextAndIconButton
 	vspErrChan       chan error
 
 	isPurchaseLoading bool
+
+	ticketBuyers []*ticketBuyerWallet
+
+	stakingReport     *wallet.StakingReport
+	loadingReport     bool
+	exportReport      decredmaterial.Button
+	isExportingReport bool
 }
 
 func TicketPage(c *pageCommon) Page {
@@ -87,6 +97,8 @@ func TicketPage(c *pageCommon) Page {
 		autoPurchaseEnabled:   new(widget.Bool),
 		toTickets:             c.theme.TextAndIconButton(new(widget.Clickable), "See All", c.icons.navigationArrowForward),
 		toTicketsActivity:     c.theme.TextAndIconButton(new(widget.Clickable), "See All", c.icons.navigationArrowForward),
+		toAgendas:             c.theme.TextAndIconButton(new(widget.Clickable), "Vote", c.icons.navigationArrowForward),
+		toTreasury:            c.theme.TextAndIconButton(new(widget.Clickable), "Vote", c.icons.navigationArrowForward),
 		purchaseOptions:       c.theme.Modal(),
 		ticketAmount:          c.theme.Editor(new(widget.Editor), ""),
 		purchaseErrChan:       make(chan error),
@@ -95,11 +107,14 @@ func TicketPage(c *pageCommon) Page {
 		rememberVSP:           c.theme.CheckBox(new(widget.Bool), "Remember VSP"),
 		inputVSP:              c.theme.Editor(new(widget.Editor), "Add a new VSP..."),
 		addVSP:                c.theme.Button(new(widget.Clickable), "Save"),
+		manageVSPs:            c.theme.TextAndIconButton(new(widget.Clickable), "Manage", c.icons.navigationArrowForward),
 		spendingPassword:      c.theme.EditorPassword(new(widget.Editor), "Spending password"),
 		vspInfo:               c.vspInfo,
 		vspErrChan:            make(chan error),
+		exportReport:          c.theme.Button(new(widget.Clickable), "Export CSV"),
 	}
 	pg.ticketAmount.Editor.SetText("1")
+	pg.exportReport.TextSize = values.TextSize12
 
 	pg.purchaseTicket.TextSize = values.TextSize12
 	pg.purchaseTicket.Background = c.theme.Color.Primary
@@ -115,6 +130,15 @@ func TicketPage(c *pageCommon) Page {
 	pg.toTicketsActivity.Color = c.theme.Color.Primary
 	pg.toTicketsActivity.BackgroundColor = c.theme.Color.Surface
 
+	pg.toAgendas.Color = c.theme.Color.Primary
+	pg.toAgendas.BackgroundColor = c.theme.Color.Surface
+
+	pg.toTreasury.Color = c.theme.Color.Primary
+	pg.toTreasury.BackgroundColor = c.theme.Color.Surface
+
+	pg.manageVSPs.Color = c.theme.Color.Primary
+	pg.manageVSPs.BackgroundColor = c.theme.Color.Surface
+
 	pg.purchaseAccountSelector = newAccountSelector(c).
 		title("Purchasing account").
 		accountSelected(func(selectedAccount *dcrlibwallet.Account) {
@@ -141,6 +165,7 @@ func TicketPage(c *pageCommon) Page {
 
 func (pg *ticketPage) OnResume() {
 	pg.purchaseAccountSelector.selectFirstWalletValidAccount()
+	pg.fetchStakingReport()
 }
 
 func (pg *ticketPage) Layout(gtx layout.Context) layout.Dimensions {
@@ -150,6 +175,9 @@ func (pg *ticketPage) Layout(gtx layout.Context) layout.Dimensions {
 			func(ctx layout.Context) layout.Dimensions {
 				return pg.ticketPriceSection(gtx, c)
 			},
+			func(ctx layout.Context) layout.Dimensions {
+				return pg.ticketBuyerSection(gtx, c)
+			},
 			func(ctx layout.Context) layout.Dimensions {
 				return pg.ticketsLiveSection(gtx, c)
 			},
@@ -159,6 +187,15 @@ func (pg *ticketPage) Layout(gtx layout.Context) layout.Dimensions {
 			func(ctx layout.Context) layout.Dimensions {
 				return pg.stackingRecordSection(gtx, c)
 			},
+			func(ctx layout.Context) layout.Dimensions {
+				return pg.stakingReportSection(gtx, c)
+			},
+			func(ctx layout.Context) layout.Dimensions {
+				return pg.agendasSection(gtx, c)
+			},
+			func(ctx layout.Context) layout.Dimensions {
+				return pg.treasurySection(gtx, c)
+			},
 		}
 
 		return pg.ticketPageContainer.Layout(gtx, len(sections), func(gtx C, i int) D {
@@ -297,6 +334,22 @@ func (pg *ticketPage) ticketsLiveSection(gtx layout.Context, c *pageCommon) layo
 	})
 }
 
+func (pg *ticketPage) agendasSection(gtx layout.Context, c *pageCommon) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		tit := c.theme.Label(values.TextSize14, "Consensus changes")
+		tit.Color = c.theme.Color.Gray2
+		return pg.titleRow(gtx, tit.Layout, pg.toAgendas.Layout)
+	})
+}
+
+func (pg *ticketPage) treasurySection(gtx layout.Context, c *pageCommon) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		tit := c.theme.Label(values.TextSize14, "Treasury spends")
+		tit.Color = c.theme.Color.Gray2
+		return pg.titleRow(gtx, tit.Layout, pg.toTreasury.Layout)
+	})
+}
+
 func (pg *ticketPage) ticketsActivitySection(gtx layout.Context, c *pageCommon) layout.Dimensions {
 	tickets := (*pg.tickets).RecentActivity
 	if len(tickets) == 0 {
@@ -403,7 +456,11 @@ func (pg *ticketPage) stackingRecordSection(gtx layout.Context, c *pageCommon) l
 											return ic.Layout(gtx)
 										}),
 										layout.Rigid(func(gtx C) D {
-											return c.layoutBalance(gtx, "16.5112316", false)
+											var rewards int64
+											if pg.stakingReport != nil {
+												rewards = pg.stakingReport.Total.Rewards
+											}
+											return c.layoutBalance(gtx, dcrutil.Amount(rewards).String(), false)
 										}),
 									)
 								}),
@@ -603,7 +660,7 @@ func (pg *ticketPage) vspHostSelectorLayout(gtx C, c *pageCommon) layout.Dimensi
 func (pg *ticketPage) vspHostModalLayout(gtx C, c *pageCommon) layout.Dimensions {
 	return pg.purchaseOptions.Layout(gtx, []layout.Widget{
 		func(gtx C) D {
-			return pg.th.Label(values.TextSize20, "Voting service provider").Layout(gtx)
+			return endToEndRow(gtx, pg.th.Label(values.TextSize20, "Voting service provider").Layout, pg.manageVSPs.Layout)
 		},
 		func(gtx C) D {
 			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
@@ -683,11 +740,11 @@ func (pg *ticketPage) editorsNotEmpty(btn *decredmaterial.Button, editors ...*wi
 }
 
 func (pg *ticketPage) calculateAndValidCost(c *pageCommon) bool {
-	tprice, _ := c.wallet.TicketPrice()
+	tprice, _, priceErr := c.wallet.TicketPrice()
 	tnumber, err := strconv.ParseInt(pg.ticketAmount.Editor.Text(), 10, 64)
 	pg.submitPurchase.Text = "Purchase tickets"
 	pg.reviewPurchase.Background = pg.th.Color.Hint
-	if err != nil || pg.selectedVSP.Info == nil {
+	if err != nil || priceErr != nil || pg.selectedVSP.Info == nil {
 		return false
 	}
 	pg.submitPurchase.Text = fmt.Sprintf("Purchase %d tickets", tnumber)
@@ -719,6 +776,16 @@ func (pg *ticketPage) doPurchaseTicket(c *pageCommon, password []byte, ticketAmo
 	c.wallet.PurchaseTicket(selectedAccount.WalletID, selectedAccount.Number, ticketAmount, password, pg.vspd, pg.purchaseErrChan)
 }
 
+// fetchVSPs reloads the VSP list, reporting failures on this page.
+func (pg *ticketPage) fetchVSPs(c *pageCommon) {
+	id := c.wallet.GetAllVSP()
+	c.onResponse(id, func(resp wallet.Response) {
+		if resp.Err != nil {
+			c.notify(resp.Err.Error(), false)
+		}
+	})
+}
+
 func (pg *ticketPage) createNewVSPD(c *pageCommon) {
 	selectedAccount := pg.purchaseAccountSelector.selectedAccount
 	vspd, err := c.wallet.NewVSPD(pg.selectedVSP.Host, selectedAccount.WalletID, selectedAccount.Number)
@@ -732,15 +799,21 @@ func (pg *ticketPage) Handle() {
 	c := pg.common
 	// TODO: frefresh when ticket price update from remote
 	if len(c.info.Wallets) > 0 && pg.ticketPrice == "" {
-		_, priceText := c.wallet.TicketPrice()
+		_, priceText, err := c.wallet.TicketPrice()
+		if err != nil {
+			log.Error(err)
+		}
 		pg.ticketPrice = priceText
-		c.wallet.GetAllVSP()
+		pg.fetchVSPs(c)
 	}
 
 	if len((*pg.vspInfo).List) != len(pg.selectVSP) {
 		pg.selectVSP = createClickGestures(len((*pg.vspInfo).List))
 	}
 
+	pg.handleTicketBuyer()
+	pg.handleStakingReport()
+
 	for _, evt := range pg.ticketAmount.Editor.Events() {
 		switch evt.(type) {
 		case widget.ChangeEvent:
@@ -759,12 +832,6 @@ func (pg *ticketPage) Handle() {
 				}
 			}
 		}
-
-		if pg.autoPurchaseEnabled.Value {
-			// TODO: calculate ticket number and vsp selected
-			pg.showPurchaseConfirm = true
-			return
-		}
 		pg.showPurchaseOptions = true
 	}
 
@@ -791,7 +858,7 @@ func (pg *ticketPage) Handle() {
 	}
 
 	if pg.showVSP.Clicked() {
-		c.wallet.GetAllVSP()
+		pg.fetchVSPs(c)
 		pg.showVSPHosts = true
 	}
 
@@ -816,6 +883,20 @@ func (pg *ticketPage) Handle() {
 		c.changePage(PageTicketsActivity)
 	}
 
+	if pg.toAgendas.Button.Clicked() {
+		c.changePage(PageAgendas)
+	}
+
+	if pg.toTreasury.Button.Clicked() {
+		c.changePage(PageTreasury)
+	}
+
+	if pg.manageVSPs.Button.Clicked() {
+		pg.showVSPHosts = false
+		pg.showPurchaseOptions = false
+		c.changePage(PageVSPs)
+	}
+
 	select {
 	case err := <-pg.vspErrChan:
 		c.notify(err.Error(), false)
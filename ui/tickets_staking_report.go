@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+// stakingReportMonths is the number of months charted on the tickets page.
+const stakingReportMonths = 12
+
+// fetchStakingReport loads the staking report of all wallets combined.
+func (pg *ticketPage) fetchStakingReport() {
+	if pg.loadingReport {
+		return
+	}
+	pg.loadingReport = true
+	id := pg.wal.StakingReport()
+	pg.common.onResponse(id, func(resp wallet.Response) {
+		pg.loadingReport = false
+		if resp.Err != nil {
+			pg.common.notify(resp.Err.Error(), false)
+			return
+		}
+		report := wallet.CombineStakingReports(resp.Resp.(*wallet.StakingReports).Reports)
+		pg.stakingReport = &report
+	})
+}
+
+func (pg *ticketPage) handleStakingReport() {
+	c := pg.common
+	for pg.exportReport.Button.Clicked() {
+		if pg.isExportingReport {
+			continue
+		}
+		pg.isExportingReport = true
+		id := pg.wal.ExportStakingReport()
+		c.onResponse(id, func(resp wallet.Response) {
+			pg.isExportingReport = false
+			if resp.Err != nil {
+				c.notify(resp.Err.Error(), false)
+				return
+			}
+			c.notify("Staking report exported to "+resp.Resp.(*wallet.StakingReportExported).Path, true)
+		})
+	}
+}
+
+func (pg *ticketPage) stakingReportSection(gtx layout.Context, c *pageCommon) layout.Dimensions {
+	return pg.pageSections(gtx, func(gtx C) D {
+		tit := c.theme.Label(values.TextSize14, "Staking Report")
+		tit.Color = c.theme.Color.Gray2
+		rows := []layout.FlexChild{
+			layout.Rigid(func(gtx C) D {
+				return pg.titleRow(gtx, tit.Layout, pg.exportReport.Layout)
+			}),
+		}
+
+		report := pg.stakingReport
+		if report == nil || len(report.Months) == 0 {
+			txt := c.theme.Body2("No tickets bought yet")
+			if pg.loadingReport {
+				txt.Text = "Loading..."
+			}
+			txt.Color = c.theme.Color.Gray
+			rows = append(rows, layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, txt.Layout)
+			}))
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+		}
+
+		months := report.Months
+		if len(months) > stakingReportMonths {
+			months = months[len(months)-stakingReportMonths:]
+		}
+		rewards := make([]decredmaterial.Bar, len(months))
+		bought := make([]decredmaterial.Bar, len(months))
+		for i, m := range months {
+			label := m.Month.Format("Jan")
+			rewards[i] = decredmaterial.Bar{Label: label, Value: dcrutil.Amount(m.Rewards).ToCoin()}
+			bought[i] = decredmaterial.Bar{Label: label, Value: float64(m.TicketsBought)}
+		}
+		rewardsChart := c.theme.BarChart(rewards)
+		rewardsChart.Color = c.theme.Color.Success
+		boughtChart := c.theme.BarChart(bought)
+
+		chart := func(title string, bc decredmaterial.BarChart) layout.FlexChild {
+			return layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+					caption := c.theme.Caption(title)
+					caption.Color = c.theme.Color.Gray2
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(caption.Layout),
+						layout.Rigid(func(gtx C) D {
+							return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, bc.Layout)
+						}),
+					)
+				})
+			})
+		}
+		rows = append(rows,
+			chart("Rewards (DCR)", rewardsChart),
+			chart("Tickets bought", boughtChart),
+			layout.Rigid(func(gtx C) D {
+				return layout.Inset{Top: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+					return pg.stakingReportStats(gtx, c, report.Total)
+				})
+			}),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+	})
+}
+
+func (pg *ticketPage) stakingReportStats(gtx layout.Context, c *pageCommon, total wallet.StakingMonth) layout.Dimensions {
+	stats := []struct {
+		value string
+		title string
+	}{
+		{fmt.Sprintf("%.1f", total.AvgDaysToVote()), "Avg. days to vote"},
+		{fmt.Sprintf("%.2f%%", total.AnnualROI()*100), "Annual ROI"},
+		{fmt.Sprintf("%.2f%%", total.MissedRate()*100), "Missed"},
+		{fmt.Sprintf("%.2f%%", total.ExpiredRate()*100), "Expired"},
+		{dcrutil.Amount(total.VSPFees).String(), "VSP fees"},
+		{dcrutil.Amount(total.TxFees).String(), "Transaction fees"},
+	}
+
+	return decredmaterial.GridWrap{
+		Axis:      layout.Horizontal,
+		Alignment: layout.End,
+	}.Layout(gtx, len(stats), func(gtx layout.Context, i int) layout.Dimensions {
+		gtx.Constraints.Min.X = gtx.Px(unit.Dp(150))
+		return layout.Inset{Bottom: values.MarginPadding16}.Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					label := pg.th.Label(values.TextSize16, stats[i].value)
+					label.Color = pg.th.Color.DeepBlue
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					txt := pg.th.Label(values.TextSize12, stats[i].title)
+					txt.Color = pg.th.Color.Gray2
+					return txt.Layout(gtx)
+				}),
+			)
+		})
+	})
+}
@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"fmt"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const PageTreasury = "Treasury"
+
+var treasuryPolicyChoices = []string{wallet.TreasuryAbstain, wallet.TreasuryYes, wallet.TreasuryNo}
+
+// treasuryItem holds the widgets of a treasury key or tspend.
+type treasuryItem struct {
+	id     string
+	policy string
+	choice *widget.Enum
+	update decredmaterial.Button
+}
+
+type treasuryPage struct {
+	th             *decredmaterial.Theme
+	common         *pageCommon
+	backButton     decredmaterial.IconButton
+	list           layout.List
+	walletDropDown *decredmaterial.DropDown
+	wallets        []*dcrlibwallet.Wallet
+
+	walletID int
+	policies *wallet.TreasuryPolicies
+	keys     []treasuryItem
+	tspends  []treasuryItem
+
+	inputTSpend  decredmaterial.Editor
+	tspendChoice *widget.Enum
+	addTSpend    decredmaterial.Button
+}
+
+func TreasuryPage(c *pageCommon) Page {
+	pg := &treasuryPage{
+		th:           c.theme,
+		common:       c,
+		list:         layout.List{Axis: layout.Vertical},
+		wallets:      c.multiWallet.AllWallets(),
+		inputTSpend:  c.theme.Editor(new(widget.Editor), "Tspend hash"),
+		tspendChoice: &widget.Enum{Value: wallet.TreasuryYes},
+		addTSpend:    c.theme.Button(new(widget.Clickable), "Set policy"),
+	}
+	pg.inputTSpend.Editor.SingleLine = true
+	pg.addTSpend.TextSize = values.TextSize12
+	pg.backButton, _ = c.SubPageHeaderButtons()
+	return pg
+}
+
+func (pg *treasuryPage) OnResume() {
+	pg.wallets = pg.common.multiWallet.AllWallets()
+	pg.common.createOrUpdateWalletDropDown(&pg.walletDropDown, pg.wallets)
+	pg.loadPolicies()
+}
+
+func (pg *treasuryPage) newItem(id, policy string) treasuryItem {
+	item := treasuryItem{
+		id:     id,
+		policy: policy,
+		choice: &widget.Enum{Value: policy},
+		update: pg.th.Button(new(widget.Clickable), "Update"),
+	}
+	item.update.TextSize = values.TextSize12
+	return item
+}
+
+func (pg *treasuryPage) loadPolicies() {
+	pg.walletID = pg.wallets[pg.walletDropDown.SelectedIndex()].ID
+	policies, err := pg.common.wallet.TreasuryPolicies(pg.walletID)
+	if err != nil {
+		pg.common.notify(err.Error(), false)
+		return
+	}
+
+	pg.policies = policies
+	pg.keys = make([]treasuryItem, len(policies.Keys))
+	for i, key := range policies.Keys {
+		pg.keys[i] = pg.newItem(key.PiKey, key.Policy)
+	}
+	pg.tspends = make([]treasuryItem, len(policies.TSpends))
+	for i, tspend := range policies.TSpends {
+		pg.tspends[i] = pg.newItem(tspend.Hash, tspend.Policy)
+	}
+}
+
+func (pg *treasuryPage) setPolicy(target, policy string) {
+	c := pg.common
+	walletID := pg.walletID
+	newPasswordModal(c).
+		title(fmt.Sprintf("Vote %s on treasury spends", policy)).
+		negativeButton("Cancel", func() {}).
+		positiveButton("Confirm", func(password string, pm *passwordModal) bool {
+			id := c.wallet.SetTreasuryPolicy(walletID, target, policy, []byte(password))
+			c.onResponse(id, func(resp wallet.Response) {
+				pm.setLoading(false)
+				if resp.Err != nil {
+					if resp.Err.Error() == dcrlibwallet.ErrInvalidPassphrase {
+						pm.setError(translateErr(resp.Err))
+					} else {
+						pm.setError(resp.Err.Error())
+					}
+					return
+				}
+				pm.Dismiss()
+
+				set := resp.Resp.(*wallet.TreasuryPolicySet)
+				if len(set.Failed) > 0 {
+					c.notify(fmt.Sprintf("Treasury policy saved, %d VSP tickets updated, %d failed", set.Updated, len(set.Failed)), false)
+				} else {
+					c.notify(fmt.Sprintf("Treasury policy saved, %d VSP tickets updated", set.Updated), true)
+				}
+				if walletID == pg.walletID {
+					pg.inputTSpend.Editor.SetText("")
+					pg.loadPolicies()
+				}
+			})
+			return false
+		}).Show()
+}
+
+func (pg *treasuryPage) Handle() {
+	if pg.policies == nil {
+		return
+	}
+	if pg.wallets[pg.walletDropDown.SelectedIndex()].ID != pg.walletID {
+		pg.loadPolicies()
+		return
+	}
+
+	for _, items := range [][]treasuryItem{pg.keys, pg.tspends} {
+		for _, item := range items {
+			if item.update.Button.Clicked() && item.choice.Value != item.policy {
+				pg.setPolicy(item.id, item.choice.Value)
+			}
+		}
+	}
+
+	if pg.inputTSpend.Editor.Text() != "" {
+		pg.addTSpend.Background = pg.th.Color.Primary
+	} else {
+		pg.addTSpend.Background = pg.th.Color.Hint
+	}
+	if pg.addTSpend.Button.Clicked() && pg.inputTSpend.Editor.Text() != "" {
+		pg.setPolicy(pg.inputTSpend.Editor.Text(), pg.tspendChoice.Value)
+	}
+}
+
+func (pg *treasuryPage) OnClose() {}
+
+func (pg *treasuryPage) Layout(gtx layout.Context) layout.Dimensions {
+	c := pg.common
+	c.createOrUpdateWalletDropDown(&pg.walletDropDown, pg.wallets)
+
+	body := func(gtx C) D {
+		page := SubPage{
+			title:      "Treasury spends",
+			backButton: pg.backButton,
+			back: func() {
+				c.changePage(PageTickets)
+			},
+			body: func(gtx C) D {
+				sections := []layout.Widget{
+					pg.walletDropDown.Layout,
+					func(gtx C) D {
+						return pg.section(gtx, "Treasury keys", func(gtx C) D {
+							return pg.itemsLayout(gtx, pg.keys)
+						})
+					},
+					func(gtx C) D {
+						return pg.section(gtx, "Treasury spends", pg.tspendsLayout)
+					},
+				}
+				return pg.list.Layout(gtx, len(sections), func(gtx C, i int) D {
+					return sections[i](gtx)
+				})
+			},
+		}
+		return c.SubPageLayout(gtx, page)
+	}
+	return c.UniformPadding(gtx, body)
+}
+
+func (pg *treasuryPage) section(gtx layout.Context, title string, body layout.Widget) layout.Dimensions {
+	return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+		return pg.th.Card().Layout(gtx, func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.UniformInset(values.MarginPadding16).Layout(gtx, func(gtx C) D {
+				tit := pg.th.Label(values.TextSize14, title)
+				tit.Color = pg.th.Color.Gray2
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(tit.Layout),
+					layout.Rigid(body),
+				)
+			})
+		})
+	})
+}
+
+func (pg *treasuryPage) tspendsLayout(gtx layout.Context) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			txt := pg.th.Body2("Pending tspends are not listed yet. Enter the hash of a tspend, e.g. from a block explorer, to vote on it.")
+			txt.Color = pg.th.Color.Gray
+			return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, txt.Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return pg.itemsLayout(gtx, pg.tspends)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, pg.inputTSpend.Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return endToEndRow(gtx, func(gtx C) D {
+				return pg.choicesLayout(gtx, pg.tspendChoice)
+			}, pg.addTSpend.Layout)
+		}),
+	)
+}
+
+func (pg *treasuryPage) choicesLayout(gtx layout.Context, choice *widget.Enum) layout.Dimensions {
+	buttons := make([]layout.FlexChild, len(treasuryPolicyChoices))
+	for i, policy := range treasuryPolicyChoices {
+		buttons[i] = layout.Rigid(pg.th.RadioButton(choice, policy, policy).Layout)
+	}
+	return layout.Flex{}.Layout(gtx, buttons...)
+}
+
+// itemsLayout lays out treasury keys or tspends with their policy choices.
+func (pg *treasuryPage) itemsLayout(gtx layout.Context, items []treasuryItem) layout.Dimensions {
+	rows := make([]layout.FlexChild, len(items))
+	for i := range items {
+		item := items[i]
+		rows[i] = layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						id := pg.th.Body1(item.id)
+						id.Font.Weight = text.Bold
+						return id.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return endToEndRow(gtx, func(gtx C) D {
+							return pg.choicesLayout(gtx, item.choice)
+						}, func(gtx C) D {
+							if item.choice.Value == item.policy {
+								return layout.Dimensions{}
+							}
+							return item.update.Layout(gtx)
+						})
+					}),
+				)
+			})
+		})
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
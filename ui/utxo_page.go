@@ -19,6 +19,14 @@ import (
 
 const PageUTXO = "unspentTransactionOutput"
 
+// utxoRowWidgets are the widgets of an unspent output listed on the page.
+type utxoRowWidgets struct {
+	checkbox    decredmaterial.CheckBoxStyle
+	copyButton  decredmaterial.IconButton
+	freeze      decredmaterial.Button
+	labelEditor decredmaterial.Editor
+}
+
 type utxoPage struct {
 	theme                  *decredmaterial.Theme
 	common                 *pageCommon
@@ -26,13 +34,18 @@ type utxoPage struct {
 	txAuthor               *dcrlibwallet.TxAuthor
 	backButton             decredmaterial.IconButton
 	useUTXOButton          decredmaterial.Button
-	unspentOutputs         **wallet.UnspentOutputs
+	unspentOutputs         []*wallet.UnspentOutput
 	unspentOutputsSelected *map[int]map[int32]map[string]*wallet.UnspentOutput
-	checkboxes             []decredmaterial.CheckBoxStyle
-	copyButtons            []decredmaterial.IconButton
+	rows                   []utxoRowWidgets
 	selecAllChexBox        decredmaterial.CheckBoxStyle
 	separator              decredmaterial.Line
 
+	sortAmount        *widget.Clickable
+	sortAge           *widget.Clickable
+	sortConfirmations *widget.Clickable
+	sortBy            wallet.UTXOSort
+	sortAscending     bool
+
 	txnFee            string
 	txnAmount         string
 	txnAmountAfterFee string
@@ -43,9 +56,8 @@ type utxoPage struct {
 
 func UTXOPage(common *pageCommon) Page {
 	pg := &utxoPage{
-		theme:          common.theme,
-		common:         common,
-		unspentOutputs: common.unspentOutputs,
+		theme:  common.theme,
+		common: common,
 		utxoListContainer: layout.List{
 			Axis: layout.Vertical,
 		},
@@ -53,6 +65,9 @@ func UTXOPage(common *pageCommon) Page {
 		unspentOutputsSelected: &common.selectedUTXO,
 		selecAllChexBox:        common.theme.CheckBox(new(widget.Bool), ""),
 		separator:              common.theme.Separator(),
+		sortAmount:             new(widget.Clickable),
+		sortAge:                new(widget.Clickable),
+		sortConfirmations:      new(widget.Clickable),
 	}
 
 	pg.backButton = common.theme.PlainIconButton(new(widget.Clickable), common.icons.navigationArrowBack)
@@ -64,31 +79,77 @@ func UTXOPage(common *pageCommon) Page {
 }
 
 func (pg *utxoPage) OnResume() {
+	common := pg.common
+	if acct := common.utxoAccount; acct != nil {
+		pg.selectedWalletID, pg.selectedAccountID = acct.WalletID, acct.Number
+	} else if len(common.info.Wallets) == 0 {
+		return
+	} else {
+		pg.selectedWalletID = common.info.Wallets[*common.selectedWallet].ID
+		pg.selectedAccountID = common.info.Wallets[*common.selectedWallet].Accounts[*common.selectedAccount].Number
+	}
+
+	selected := *pg.unspentOutputsSelected
+	if selected[pg.selectedWalletID] == nil {
+		selected[pg.selectedWalletID] = make(map[int32]map[string]*wallet.UnspentOutput)
+	}
+	if selected[pg.selectedWalletID][pg.selectedAccountID] == nil {
+		selected[pg.selectedWalletID][pg.selectedAccountID] = make(map[string]*wallet.UnspentOutput)
+	}
+
+	id := common.wallet.AllUnspentOutputs(pg.selectedWalletID, pg.selectedAccountID)
+	common.onResponse(id, func(resp wallet.Response) {
+		if resp.Err != nil {
+			common.notify(resp.Err.Error(), false)
+			return
+		}
+		pg.unspentOutputs = resp.Resp.(*wallet.UnspentOutputs).List
+		pg.loadRows()
+	})
+}
 
+// selected returns the outputs chosen to be spent from the listed account.
+func (pg *utxoPage) selected() map[string]*wallet.UnspentOutput {
+	return (*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID]
 }
 
-func (pg *utxoPage) Handle() {
-	common := pg.common
-	pg.selectedWalletID = common.info.Wallets[*common.selectedWallet].ID
-	pg.selectedAccountID = common.info.Wallets[*common.selectedWallet].Accounts[*common.selectedAccount].Number
-
-	if len(pg.checkboxes) != len((*pg.unspentOutputs).List) {
-		pg.checkboxes = make([]decredmaterial.CheckBoxStyle, len((*pg.unspentOutputs).List))
-		pg.copyButtons = make([]decredmaterial.IconButton, len((*pg.unspentOutputs).List))
-
-		for i := 0; i < len((*pg.unspentOutputs).List); i++ {
-			utxo := (*pg.unspentOutputs).List[i]
-			pg.checkboxes[i] = common.theme.CheckBox(new(widget.Bool), "")
-			if _, ok := (*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID][utxo.UTXO.OutputKey]; ok {
-				pg.checkboxes[i].CheckBox.Value = true
-			}
-			icoBtn := common.theme.IconButton(new(widget.Clickable), mustIcon(widget.NewIcon(icons.ContentContentCopy)))
-			icoBtn.Inset, icoBtn.Size = layout.UniformInset(values.MarginPadding5), values.MarginPadding20
-			icoBtn.Background = common.theme.Color.LightGray
-			pg.copyButtons[i] = icoBtn
+// loadRows sorts the unspent outputs and creates the widgets of each row.
+// Frozen outputs are dropped from the selection.
+func (pg *utxoPage) loadRows() {
+	wallet.SortUnspentOutputs(pg.unspentOutputs, pg.sortBy, pg.sortAscending)
+
+	pg.rows = make([]utxoRowWidgets, len(pg.unspentOutputs))
+	for i, utxo := range pg.unspentOutputs {
+		row := utxoRowWidgets{
+			checkbox: pg.theme.CheckBox(new(widget.Bool), ""),
+			freeze:   pg.theme.Button(new(widget.Clickable), "Freeze"),
 		}
-		pg.calculateAmountAndFeeUTXO()
+		if utxo.Frozen {
+			row.freeze.Text = "Unfreeze"
+			delete(pg.selected(), utxo.UTXO.OutputKey)
+		} else if _, ok := pg.selected()[utxo.UTXO.OutputKey]; ok {
+			row.checkbox.CheckBox.Value = true
+		}
+		row.freeze.TextSize = values.TextSize14
+		row.freeze.Background = pg.theme.Color.Surface
+		row.freeze.Color = pg.theme.Color.Primary
+		row.freeze.Inset = layout.UniformInset(values.MarginPadding5)
+
+		row.copyButton = pg.theme.IconButton(new(widget.Clickable), mustIcon(widget.NewIcon(icons.ContentContentCopy)))
+		row.copyButton.Inset, row.copyButton.Size = layout.UniformInset(values.MarginPadding5), values.MarginPadding20
+		row.copyButton.Background = pg.theme.Color.LightGray
+
+		row.labelEditor = pg.theme.Editor(new(widget.Editor), "Label")
+		row.labelEditor.Editor.SingleLine, row.labelEditor.Editor.Submit = true, true
+		row.labelEditor.IsRequired = false
+		row.labelEditor.Editor.SetText(utxo.Label)
+		pg.rows[i] = row
 	}
+	pg.calculateAmountAndFeeUTXO()
+}
+
+func (pg *utxoPage) Handle() {
+	common := pg.common
 
 	if pg.backButton.Button.Clicked() {
 		pg.clearPageData()
@@ -99,38 +160,99 @@ func (pg *utxoPage) Handle() {
 		common.changePage(PageSend)
 	}
 
+	if len(pg.rows) != len(pg.unspentOutputs) {
+		return
+	}
+
 	if pg.selecAllChexBox.CheckBox.Changed() {
-		for i, utxo := range (*pg.unspentOutputs).List {
-			if pg.selecAllChexBox.CheckBox.Value {
-				pg.checkboxes[i].CheckBox.Value = true
-				(*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID][utxo.UTXO.OutputKey] = utxo
+		for i, utxo := range pg.unspentOutputs {
+			if pg.selecAllChexBox.CheckBox.Value && !utxo.Frozen {
+				pg.rows[i].checkbox.CheckBox.Value = true
+				pg.selected()[utxo.UTXO.OutputKey] = utxo
 			} else {
-				delete((*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID], utxo.UTXO.OutputKey)
-				pg.checkboxes[i].CheckBox.Value = false
+				delete(pg.selected(), utxo.UTXO.OutputKey)
+				pg.rows[i].checkbox.CheckBox.Value = false
 			}
 		}
 		pg.calculateAmountAndFeeUTXO()
 	}
-}
 
-func (pg *utxoPage) handlerCheckboxes(cb *decredmaterial.CheckBoxStyle, utxo *wallet.UnspentOutput) {
-	if cb.CheckBox.Changed() {
-		if cb.CheckBox.Value {
-			(*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID][utxo.UTXO.OutputKey] = utxo
-		} else {
-			delete((*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID], utxo.UTXO.OutputKey)
+	sorts := []struct {
+		clickable *widget.Clickable
+		by        wallet.UTXOSort
+	}{
+		{pg.sortAmount, wallet.UTXOSortAmount},
+		{pg.sortAge, wallet.UTXOSortAge},
+		{pg.sortConfirmations, wallet.UTXOSortConfirmations},
+	}
+	for _, s := range sorts {
+		for s.clickable.Clicked() {
+			if pg.sortBy == s.by {
+				pg.sortAscending = !pg.sortAscending
+			} else {
+				pg.sortBy, pg.sortAscending = s.by, false
+			}
+			pg.loadRows()
+			return
+		}
+	}
+
+	for i, utxo := range pg.unspentOutputs {
+		row := &pg.rows[i]
+		if row.checkbox.CheckBox.Changed() {
+			switch {
+			case utxo.Frozen:
+				row.checkbox.CheckBox.Value = false
+				common.notify(wallet.ErrUTXOFrozen.Error(), false)
+			case row.checkbox.CheckBox.Value:
+				pg.selected()[utxo.UTXO.OutputKey] = utxo
+			default:
+				delete(pg.selected(), utxo.UTXO.OutputKey)
+			}
+			pg.calculateAmountAndFeeUTXO()
+		}
+
+		for row.freeze.Button.Clicked() {
+			if err := common.wallet.FreezeUTXO(pg.selectedWalletID, utxo.UTXO.OutputKey, !utxo.Frozen); err != nil {
+				common.notify(err.Error(), false)
+				continue
+			}
+			utxo.Frozen = !utxo.Frozen
+			row.freeze.Text = "Freeze"
+			if utxo.Frozen {
+				row.freeze.Text = "Unfreeze"
+				row.checkbox.CheckBox.Value = false
+				delete(pg.selected(), utxo.UTXO.OutputKey)
+				pg.calculateAmountAndFeeUTXO()
+			}
+		}
+
+		for _, evt := range row.labelEditor.Editor.Events() {
+			if _, ok := evt.(widget.SubmitEvent); !ok {
+				continue
+			}
+			label := row.labelEditor.Editor.Text()
+			if err := common.wallet.SetUTXOLabel(pg.selectedWalletID, utxo.UTXO.OutputKey, label); err != nil {
+				common.notify(err.Error(), false)
+				continue
+			}
+			utxo.Label = label
+			common.notify("Label saved", true)
 		}
-		pg.calculateAmountAndFeeUTXO()
 	}
 }
 
 func (pg *utxoPage) calculateAmountAndFeeUTXO() {
 	var utxoKeys []string
 	var totalAmount int64
-	for utxoKey, utxo := range (*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID] {
+	for utxoKey, utxo := range pg.selected() {
 		utxoKeys = append(utxoKeys, utxoKey)
 		totalAmount += utxo.UTXO.Amount
 	}
+	pg.txnAmount, pg.txnFee, pg.txnAmountAfterFee = "", "", ""
+	if len(utxoKeys) == 0 {
+		return
+	}
 	err := pg.txAuthor.UseInputs(utxoKeys)
 	if err != nil {
 		log.Error(err)
@@ -147,7 +269,8 @@ func (pg *utxoPage) calculateAmountAndFeeUTXO() {
 }
 
 func (pg *utxoPage) clearPageData() {
-	pg.checkboxes = nil
+	pg.rows = nil
+	pg.unspentOutputs = nil
 	pg.txnFee = ""
 }
 
@@ -174,8 +297,7 @@ func (pg *utxoPage) Layout(gtx layout.Context) layout.Dimensions {
 						return layout.Inset{Bottom: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
 							return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
 								layout.Flexed(0.25, func(gtx C) D {
-									utxos := (*pg.unspentOutputsSelected)[pg.selectedWalletID][pg.selectedAccountID]
-									return textData(gtx, c, "Selected:  ", fmt.Sprintf("%d", len(utxos)))
+									return textData(gtx, c, "Selected:  ", fmt.Sprintf("%d", len(pg.selected())))
 								}),
 								layout.Flexed(0.25, func(gtx C) D {
 									return textData(gtx, c, "Amount:  ", pg.txnAmount)
@@ -194,13 +316,11 @@ func (pg *utxoPage) Layout(gtx layout.Context) layout.Dimensions {
 						return pg.utxoRowHeader(gtx, c)
 					}),
 					layout.Flexed(1, func(gtx C) D {
-						if len(pg.checkboxes) == 0 {
+						if len(pg.rows) != len(pg.unspentOutputs) || len(pg.rows) == 0 {
 							return layout.Dimensions{}
 						}
-						return pg.utxoListContainer.Layout(gtx, len((*pg.unspentOutputs).List), func(gtx C, index int) D {
-							utxo := (*pg.unspentOutputs).List[index]
-							pg.handlerCheckboxes(&pg.checkboxes[index], utxo)
-							return pg.utxoRow(gtx, utxo, c, index)
+						return pg.utxoListContainer.Layout(gtx, len(pg.unspentOutputs), func(gtx C, index int) D {
+							return pg.utxoRow(gtx, pg.unspentOutputs[index], c, index)
 						})
 					}),
 					layout.Rigid(func(gtx C) D {
@@ -223,6 +343,21 @@ func textData(gtx layout.Context, c *pageCommon, txt, value string) layout.Dimen
 	)
 }
 
+// sortHeader is the title of a column the list can be sorted by, with an
+// arrow when the list is sorted by it.
+func (pg *utxoPage) sortHeader(gtx layout.Context, c *pageCommon, title string, by wallet.UTXOSort, clickable *widget.Clickable) layout.Dimensions {
+	txt := c.theme.Label(values.MarginPadding15, title)
+	txt.MaxLines = 1
+	txt.Color = c.theme.Color.Primary
+	if pg.sortBy == by {
+		txt.Text += " ↓"
+		if pg.sortAscending {
+			txt.Text = title + " ↑"
+		}
+	}
+	return decredmaterial.Clickable(gtx, clickable, txt.Layout)
+}
+
 func (pg *utxoPage) utxoRowHeader(gtx layout.Context, c *pageCommon) layout.Dimensions {
 	txt := c.theme.Label(values.MarginPadding15, "")
 	txt.MaxLines = 1
@@ -231,8 +366,7 @@ func (pg *utxoPage) utxoRowHeader(gtx layout.Context, c *pageCommon) layout.Dime
 			layout.Rigid(pg.selecAllChexBox.Layout),
 			layout.Rigid(func(gtx C) D {
 				gtx.Constraints.Min.X = gtx.Px(values.MarginPadding150)
-				txt.Text = "Amount"
-				return txt.Layout(gtx)
+				return pg.sortHeader(gtx, c, "Amount", wallet.UTXOSortAmount, pg.sortAmount)
 			}),
 			layout.Rigid(func(gtx C) D {
 				gtx.Constraints.Min.X = gtx.Px(values.MarginPadding200)
@@ -241,55 +375,71 @@ func (pg *utxoPage) utxoRowHeader(gtx layout.Context, c *pageCommon) layout.Dime
 			}),
 			layout.Rigid(func(gtx C) D {
 				gtx.Constraints.Min.X = gtx.Px(values.MarginPadding100)
-				txt.Text = "Date (UTC)"
-				txt.Alignment = text.End
-				return txt.Layout(gtx)
+				return layout.E.Layout(gtx, func(gtx C) D {
+					return pg.sortHeader(gtx, c, "Date (UTC)", wallet.UTXOSortAge, pg.sortAge)
+				})
 			}),
 			layout.Rigid(func(gtx C) D {
-				gtx.Constraints.Min.X = gtx.Px(values.MarginPadding100)
-				txt.Text = "Confirmations"
-				return txt.Layout(gtx)
+				gtx.Constraints.Min.X = gtx.Px(values.MarginPadding120)
+				return layout.E.Layout(gtx, func(gtx C) D {
+					return pg.sortHeader(gtx, c, "Confirmations", wallet.UTXOSortConfirmations, pg.sortConfirmations)
+				})
 			}),
 		)
 	})
 }
 
 func (pg *utxoPage) utxoRow(gtx layout.Context, data *wallet.UnspentOutput, c *pageCommon, index int) layout.Dimensions {
-	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
-		layout.Rigid(pg.checkboxes[index].Layout),
-		layout.Rigid(func(gtx C) D {
-			txt := c.theme.Body2(data.Amount)
-			txt.MaxLines = 1
-			txt.Alignment = text.Start
-			gtx.Constraints.Min.X = gtx.Px(values.MarginPadding150)
-			return txt.Layout(gtx)
-		}),
-		layout.Rigid(func(gtx C) D {
-			txt := c.theme.Body2(data.UTXO.Addresses)
-			txt.MaxLines = 1
-			gtx.Constraints.Max.X = gtx.Px(values.MarginPadding200)
-			gtx.Constraints.Min.X = gtx.Px(values.MarginPadding200)
-			return txt.Layout(gtx)
-		}),
-		layout.Rigid(func(gtx C) D {
-			txt := c.theme.Body2(data.DateTime)
-			txt.MaxLines = 1
-			txt.Alignment = text.End
-			gtx.Constraints.Min.X = gtx.Px(values.MarginPadding100)
-			return txt.Layout(gtx)
-		}),
+	row := &pg.rows[index]
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 		layout.Rigid(func(gtx C) D {
-			txt := c.theme.Body2(fmt.Sprintf("%d", data.UTXO.Confirmations))
-			txt.MaxLines = 1
-			txt.Alignment = text.End
-			gtx.Constraints.Min.X = gtx.Px(values.MarginPadding100)
-			return txt.Layout(gtx)
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(row.checkbox.Layout),
+				layout.Rigid(func(gtx C) D {
+					txt := c.theme.Body2(data.Amount)
+					txt.MaxLines = 1
+					txt.Alignment = text.Start
+					if data.Frozen {
+						txt.Color = c.theme.Color.Gray
+					}
+					gtx.Constraints.Min.X = gtx.Px(values.MarginPadding150)
+					return txt.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					txt := c.theme.Body2(data.UTXO.Addresses)
+					txt.MaxLines = 1
+					gtx.Constraints.Max.X = gtx.Px(values.MarginPadding200)
+					gtx.Constraints.Min.X = gtx.Px(values.MarginPadding200)
+					return txt.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					txt := c.theme.Body2(data.DateTime)
+					txt.MaxLines = 1
+					txt.Alignment = text.End
+					gtx.Constraints.Min.X = gtx.Px(values.MarginPadding100)
+					return txt.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					txt := c.theme.Body2(fmt.Sprintf("%d", data.UTXO.Confirmations))
+					txt.MaxLines = 1
+					txt.Alignment = text.End
+					gtx.Constraints.Min.X = gtx.Px(values.MarginPadding120)
+					return txt.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					if row.copyButton.Button.Clicked() {
+						clipboard.WriteOp{Text: data.UTXO.Addresses}.Add(gtx.Ops)
+					}
+					return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, row.copyButton.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, row.freeze.Layout)
+				}),
+			)
 		}),
 		layout.Rigid(func(gtx C) D {
-			if pg.copyButtons[index].Button.Clicked() {
-				clipboard.WriteOp{Text: data.UTXO.Addresses}.Add(gtx.Ops)
-			}
-			return layout.Inset{Left: values.MarginPadding10}.Layout(gtx, pg.copyButtons[index].Layout)
+			gtx.Constraints.Max.X = gtx.Px(values.MarginPadding350)
+			return layout.Inset{Left: values.MarginPadding30, Bottom: values.MarginPadding10}.Layout(gtx, row.labelEditor.Layout)
 		}),
 	)
 }
@@ -116,4 +116,34 @@ const EN = `
 "french" = "French";
 "usdBittrex" = "USD (Bittrex)";
 "none" = "None";
+"export" = "Export";
+"transactionsExported" = "%d transactions exported to %s";
+"stale" = "(stale)";
+"currencySource" = "%s (%s)";
+// exchange rate source names, keyed by wallet.ExchangeRateSource.Name
+"bittrex" = "Bittrex";
+"coingecko" = "CoinGecko";
+"dcrdata" = "dcrdata";
+"valueAtTheTime" = "Value at the time";
+"importRateHistory" = "Import rate history";
+"csvFilePath" = "CSV file path";
+"ratesImported" = "%d daily %s rates imported";
+"rebroadcast" = "Rebroadcast";
+"txsRebroadcast" = "Unconfirmed transactions rebroadcast";
+"note" = "Note";
+"tags" = "Tags";
+"noteSaved" = "Note saved";
+"addressNote" = "Note for payments to this address (optional)";
+"searchTransactions" = "Search hash, address, note or #tag";
+"filters" = "Filters";
+"allAccounts" = "All accounts";
+"minAmount" = "Min amount (DCR)";
+"maxAmount" = "Max amount (DCR)";
+"fromDate" = "From (YYYY-MM-DD)";
+"toDate" = "To (YYYY-MM-DD)";
+"address" = "Address";
+"txHashPrefix" = "Transaction hash";
+"loadMore" = "Load more";
+"invalidAmount" = "Invalid amount";
+"invalidDate" = "Invalid date";
 `
@@ -222,4 +222,30 @@ const (
 	StrFrench                      = "french"
 	StrUsdBittrex                  = "usdBittrex"
 	StrNone                        = "none"
+	StrExport                      = "export"
+	StrTransactionsExported        = "transactionsExported"
+	StrStale                       = "stale"
+	StrCurrencySource              = "currencySource"
+	StrValueAtTheTime              = "valueAtTheTime"
+	StrImportRateHistory           = "importRateHistory"
+	StrCSVFilePath                 = "csvFilePath"
+	StrRatesImported               = "ratesImported"
+	StrRebroadcast                 = "rebroadcast"
+	StrTxsRebroadcast              = "txsRebroadcast"
+	StrNote                        = "note"
+	StrTags                        = "tags"
+	StrNoteSaved                   = "noteSaved"
+	StrAddressNote                 = "addressNote"
+	StrSearchTransactions          = "searchTransactions"
+	StrFilters                     = "filters"
+	StrAllAccounts                 = "allAccounts"
+	StrMinAmount                   = "minAmount"
+	StrMaxAmount                   = "maxAmount"
+	StrFromDate                    = "fromDate"
+	StrToDate                      = "toDate"
+	StrAddress                     = "address"
+	StrTxHashPrefix                = "txHashPrefix"
+	StrLoadMore                    = "loadMore"
+	StrInvalidAmount               = "invalidAmount"
+	StrInvalidDate                 = "invalidDate"
 )
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const PageVSPs = "VSPs"
+
+// vspProbeInterval is how often the saved VSPs are probed while the page is
+// shown.
+const vspProbeInterval = time.Minute
+
+// vspRow holds the buttons of a saved VSP.
+type vspRow struct {
+	setDefault decredmaterial.Button
+	remove     decredmaterial.Button
+	trust      decredmaterial.Button
+}
+
+type vspPage struct {
+	th         *decredmaterial.Theme
+	common     *pageCommon
+	backButton decredmaterial.IconButton
+	list       layout.List
+
+	inputVSP decredmaterial.Editor
+	addVSP   decredmaterial.Button
+	errChan  chan error
+
+	records   *wallet.VSPRecords
+	rows      map[string]*vspRow
+	probing   bool
+	lastProbe time.Time
+}
+
+func VSPPage(c *pageCommon) Page {
+	pg := &vspPage{
+		th:       c.theme,
+		common:   c,
+		list:     layout.List{Axis: layout.Vertical},
+		inputVSP: c.theme.Editor(new(widget.Editor), "Add VSP..."),
+		addVSP:   c.theme.Button(new(widget.Clickable), "Add"),
+		errChan:  make(chan error),
+		rows:     make(map[string]*vspRow),
+	}
+	pg.inputVSP.Editor.SingleLine = true
+	pg.backButton, _ = c.SubPageHeaderButtons()
+	return pg
+}
+
+func (pg *vspPage) OnResume() {
+	pg.loadRecords(pg.common.wallet.SavedVSPs())
+	pg.probe()
+}
+
+func (pg *vspPage) loadRecords(records *wallet.VSPRecords) {
+	pg.records = records
+	for _, record := range records.List {
+		if _, ok := pg.rows[record.Host]; ok {
+			continue
+		}
+		row := &vspRow{
+			setDefault: pg.th.Button(new(widget.Clickable), "Set default"),
+			remove:     pg.th.Button(new(widget.Clickable), "Remove"),
+			trust:      pg.th.Button(new(widget.Clickable), "Trust new key"),
+		}
+		row.setDefault.TextSize, row.remove.TextSize, row.trust.TextSize = values.TextSize12, values.TextSize12, values.TextSize12
+		row.remove.Background = pg.th.Color.Danger
+		pg.rows[record.Host] = row
+	}
+}
+
+// probe measures the latency and availability of the saved VSPs.
+func (pg *vspPage) probe() {
+	if pg.probing {
+		return
+	}
+	pg.probing = true
+	pg.lastProbe = time.Now()
+	id := pg.common.wallet.ProbeVSPs()
+	pg.common.onResponse(id, func(resp wallet.Response) {
+		pg.probing = false
+		if resp.Err != nil {
+			pg.common.notify(resp.Err.Error(), false)
+			return
+		}
+		pg.loadRecords(resp.Resp.(*wallet.VSPRecords))
+	})
+}
+
+func (pg *vspPage) Handle() {
+	c := pg.common
+	if time.Since(pg.lastProbe) > vspProbeInterval {
+		pg.probe()
+	}
+
+	if pg.inputVSP.Editor.Text() != "" {
+		pg.addVSP.Background = pg.th.Color.Primary
+	} else {
+		pg.addVSP.Background = pg.th.Color.Hint
+	}
+	if pg.addVSP.Button.Clicked() && pg.inputVSP.Editor.Text() != "" {
+		id := c.wallet.AddVSP(pg.inputVSP.Editor.Text(), pg.errChan)
+		c.onResponse(id, func(resp wallet.Response) {
+			if resp.Err != nil {
+				c.notify(resp.Err.Error(), false)
+				return
+			}
+			pg.inputVSP.Editor.SetText("")
+			pg.loadRecords(c.wallet.SavedVSPs())
+		})
+	}
+
+	if pg.records != nil {
+		for _, record := range pg.records.List {
+			row := pg.rows[record.Host]
+			if row.setDefault.Button.Clicked() {
+				c.wallet.RememberVSP(record.Host)
+				pg.loadRecords(c.wallet.SavedVSPs())
+			}
+			if row.remove.Button.Clicked() {
+				if err := c.wallet.RemoveVSP(record.Host); err != nil {
+					c.notify(err.Error(), false)
+				}
+				delete(pg.rows, record.Host)
+				pg.loadRecords(c.wallet.SavedVSPs())
+				break
+			}
+			if row.trust.Button.Clicked() {
+				c.wallet.TrustVSPPubKey(record.Host)
+				pg.loadRecords(c.wallet.SavedVSPs())
+			}
+		}
+	}
+
+	select {
+	case err := <-pg.errChan:
+		c.notify(err.Error(), false)
+	default:
+	}
+}
+
+func (pg *vspPage) OnClose() {}
+
+func (pg *vspPage) Layout(gtx layout.Context) layout.Dimensions {
+	c := pg.common
+	body := func(gtx C) D {
+		page := SubPage{
+			title:      "Voting service providers",
+			backButton: pg.backButton,
+			back: func() {
+				c.changePage(PageTickets)
+			},
+			body: func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+							layout.Flexed(1, pg.inputVSP.Layout),
+							layout.Rigid(func(gtx C) D {
+								return layout.Inset{Left: values.MarginPadding8}.Layout(gtx, pg.addVSP.Layout)
+							}),
+						)
+					}),
+					layout.Rigid(func(gtx C) D {
+						if pg.records == nil || len(pg.records.List) == 0 {
+							txt := c.theme.Body1("No VSPs saved yet")
+							txt.Color = c.theme.Color.Gray2
+							return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, txt.Layout)
+						}
+						records := pg.records.List
+						return pg.list.Layout(gtx, len(records), func(gtx C, i int) D {
+							return layout.Inset{Top: values.MarginPadding15}.Layout(gtx, func(gtx C) D {
+								return c.theme.Card().Layout(gtx, func(gtx C) D {
+									gtx.Constraints.Min.X = gtx.Constraints.Max.X
+									return layout.UniformInset(values.MarginPadding16).Layout(gtx, func(gtx C) D {
+										return pg.vspLayout(gtx, records[i])
+									})
+								})
+							})
+						})
+					}),
+				)
+			},
+		}
+		return c.SubPageLayout(gtx, page)
+	}
+	return c.UniformPadding(gtx, body)
+}
+
+func (pg *vspPage) vspLayout(gtx layout.Context, record wallet.VSPRecord) layout.Dimensions {
+	row := pg.rows[record.Host]
+	detail := func(txt string) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			label := pg.th.Body2(txt)
+			label.Color = pg.th.Color.Gray
+			return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, label.Layout)
+		})
+	}
+
+	status := "Not checked yet"
+	switch {
+	case record.Available:
+		status = fmt.Sprintf("Online, %s", record.Latency.Round(time.Millisecond))
+	case record.Err != "":
+		status = "Unreachable: " + record.Err
+	case record.Info != nil && record.Info.VspClosed:
+		status = "Closed to new tickets"
+	}
+
+	rows := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D {
+			title := pg.th.Label(values.TextSize16, record.Host)
+			title.Font.Weight = text.Bold
+			if record.Host != pg.records.Default {
+				return endToEndRow(gtx, title.Layout, row.setDefault.Layout)
+			}
+			tag := pg.th.Label(values.TextSize14, "Default")
+			tag.Color = pg.th.Color.Success
+			return endToEndRow(gtx, title.Layout, tag.Layout)
+		}),
+		detail(status),
+	}
+	if record.Info != nil {
+		info := record.Info
+		rows = append(rows,
+			detail(fmt.Sprintf("Fee: %v%%, voting %d tickets, %s", info.FeePercentage, info.Voting, info.Network)),
+			detail("Public key: "+hex.EncodeToString(info.PubKey)),
+			detail("Updated "+record.FetchedAt.Format("Jan 2 15:04")),
+		)
+	}
+	if record.PubKeyChanged {
+		rows = append(rows, layout.Rigid(func(gtx C) D {
+			warning := pg.th.Body2("The public key of this VSP changed since it was added. It was " +
+				hex.EncodeToString(record.PreviousPubKey))
+			warning.Color = pg.th.Color.Danger
+			return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, warning.Layout)
+		}))
+	}
+	rows = append(rows, layout.Rigid(func(gtx C) D {
+		return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+			return layout.E.Layout(gtx, func(gtx C) D {
+				return layout.Flex{}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						if !record.PubKeyChanged {
+							return layout.Dimensions{}
+						}
+						return layout.Inset{Right: values.MarginPadding8}.Layout(gtx, row.trust.Layout)
+					}),
+					layout.Rigid(row.remove.Layout),
+				)
+			})
+		})
+	}))
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
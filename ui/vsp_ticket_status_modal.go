@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/widget"
+
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/godcr/ui/decredmaterial"
+	"github.com/planetdecred/godcr/ui/values"
+	"github.com/planetdecred/godcr/wallet"
+)
+
+const ModalVSPTicketStatus = "vsp_ticket_status_modal"
+
+// vspTicketStatusModal shows the VSP fee state of the unspent tickets of a
+// wallet and pays stuck fees again.
+type vspTicketStatusModal struct {
+	*pageCommon
+	randomID       string
+	modal          decredmaterial.Modal
+	passwordEditor decredmaterial.Editor
+	btnCheck       decredmaterial.Button
+	btnClose       decredmaterial.Button
+	retryButtons   map[string]decredmaterial.Button
+
+	walletID   int
+	statuses   *wallet.VSPTicketStatuses
+	isChecking bool
+	// retrying is the ticket whose fee is being paid.
+	retrying string
+}
+
+func newVSPTicketStatusModal(common *pageCommon, walletID int) *vspTicketStatusModal {
+	sm := &vspTicketStatusModal{
+		pageCommon:   common,
+		randomID:     fmt.Sprintf("%s-%d", ModalVSPTicketStatus, generateRandomNumber()),
+		modal:        *common.theme.ModalFloatTitle(),
+		btnCheck:     common.theme.Button(new(widget.Clickable), "Check"),
+		btnClose:     common.theme.Button(new(widget.Clickable), "Close"),
+		retryButtons: make(map[string]decredmaterial.Button),
+		walletID:     walletID,
+	}
+
+	sm.btnCheck.TextSize, sm.btnClose.TextSize = values.TextSize16, values.TextSize16
+	sm.btnCheck.Font.Weight, sm.btnClose.Font.Weight = text.Bold, text.Bold
+	sm.btnClose.Background = common.theme.Color.Surface
+	sm.btnClose.Color = common.theme.Color.Primary
+
+	sm.passwordEditor = common.theme.EditorPassword(new(widget.Editor), "Spending password")
+	sm.passwordEditor.Editor.SingleLine, sm.passwordEditor.Editor.Submit = true, true
+	return sm
+}
+
+func (sm *vspTicketStatusModal) ModalID() string {
+	return sm.randomID
+}
+
+func (sm *vspTicketStatusModal) OnResume() {}
+
+func (sm *vspTicketStatusModal) OnDismiss() {}
+
+func (sm *vspTicketStatusModal) Show() {
+	sm.showModal(sm)
+}
+
+func (sm *vspTicketStatusModal) Dismiss() {
+	sm.dismissModal(sm)
+}
+
+func (sm *vspTicketStatusModal) busy() bool {
+	return sm.isChecking || sm.retrying != ""
+}
+
+func (sm *vspTicketStatusModal) canCheck() bool {
+	return sm.passwordEditor.Editor.Text() != "" && !sm.busy()
+}
+
+func (sm *vspTicketStatusModal) passwordError(err error) bool {
+	if err.Error() == dcrlibwallet.ErrInvalidPassphrase {
+		sm.passwordEditor.SetError(translateErr(err))
+		return true
+	}
+	return false
+}
+
+func (sm *vspTicketStatusModal) check() {
+	sm.isChecking = true
+	sm.passwordEditor.SetError("")
+	id := sm.wallet.VSPTicketStatus(sm.walletID, []byte(sm.passwordEditor.Editor.Text()))
+	sm.onResponse(id, func(resp wallet.Response) {
+		sm.isChecking = false
+		if resp.Err != nil {
+			if !sm.passwordError(resp.Err) {
+				sm.notify(resp.Err.Error(), false)
+			}
+			return
+		}
+
+		sm.statuses = resp.Resp.(*wallet.VSPTicketStatuses)
+		for _, status := range sm.statuses.Statuses {
+			if _, ok := sm.retryButtons[status.TicketHash]; !ok {
+				btn := sm.theme.Button(new(widget.Clickable), "Retry fee payment")
+				btn.TextSize = values.TextSize12
+				sm.retryButtons[status.TicketHash] = btn
+			}
+		}
+	})
+}
+
+func (sm *vspTicketStatusModal) retry(ticketHash string) {
+	sm.retrying = ticketHash
+	sm.passwordEditor.SetError("")
+	id := sm.wallet.RetryVSPFee(sm.walletID, ticketHash, []byte(sm.passwordEditor.Editor.Text()))
+	sm.onResponse(id, func(resp wallet.Response) {
+		sm.retrying = ""
+		if resp.Err != nil {
+			if !sm.passwordError(resp.Err) {
+				sm.notify(resp.Err.Error(), false)
+			}
+			return
+		}
+		sm.notify("VSP fee paid", true)
+		sm.check()
+	})
+}
+
+func (sm *vspTicketStatusModal) Handle() {
+	if sm.btnClose.Button.Clicked() && !sm.busy() {
+		sm.Dismiss()
+	}
+
+	if sm.canCheck() {
+		sm.btnCheck.Background = sm.theme.Color.Primary
+	} else {
+		sm.btnCheck.Background = sm.theme.Color.Gray1
+	}
+
+	for sm.btnCheck.Button.Clicked() {
+		if sm.canCheck() {
+			sm.check()
+		}
+	}
+
+	for _, evt := range sm.passwordEditor.Editor.Events() {
+		if _, ok := evt.(widget.SubmitEvent); ok && sm.canCheck() {
+			sm.check()
+		}
+	}
+
+	for hash, btn := range sm.retryButtons {
+		for btn.Button.Clicked() {
+			if sm.canCheck() {
+				sm.retry(hash)
+			}
+		}
+	}
+}
+
+func (sm *vspTicketStatusModal) ticketStatusLayout(gtx layout.Context, status wallet.VSPTicketStatus) layout.Dimensions {
+	detail := func(txt string) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			label := sm.theme.Body2(txt)
+			label.Color = sm.theme.Color.Gray
+			return layout.Inset{Top: values.MarginPadding4}.Layout(gtx, label.Layout)
+		})
+	}
+
+	rows := []layout.FlexChild{
+		layout.Rigid(sm.theme.Body1(status.TicketHash).Layout),
+	}
+	switch {
+	case status.Host == "" && status.Err != "":
+		rows = append(rows, detail("Error: "+status.Err))
+	case status.Host == "":
+		rows = append(rows, detail("Not found at any known VSP"))
+	default:
+		confirmed := "unconfirmed"
+		if status.TicketConfirmed {
+			confirmed = "confirmed"
+		}
+		rows = append(rows,
+			detail(fmt.Sprintf("%s, ticket %s", status.Host, confirmed)),
+			detail("Fee: "+status.FeeTxStatus))
+		if status.FeeTxHash != "" {
+			rows = append(rows, detail("Fee transaction: "+status.FeeTxHash))
+		}
+		if len(status.VoteChoices) > 0 {
+			choices := make([]string, 0, len(status.VoteChoices))
+			for agenda, choice := range status.VoteChoices {
+				choices = append(choices, agenda+": "+choice)
+			}
+			sort.Strings(choices)
+			rows = append(rows, detail("Votes: "+strings.Join(choices, ", ")))
+		}
+	}
+	if status.FeeStuck() {
+		rows = append(rows, layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: values.MarginPadding8}.Layout(gtx, func(gtx C) D {
+				if sm.retrying == status.TicketHash {
+					return sm.theme.Body2("Paying fee...").Layout(gtx)
+				}
+				return sm.retryButtons[status.TicketHash].Layout(gtx)
+			})
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
+
+func (sm *vspTicketStatusModal) Layout(gtx layout.Context) D {
+	w := []layout.Widget{
+		func(gtx C) D {
+			t := sm.theme.H6("VSP ticket status")
+			t.Font.Weight = text.Bold
+			return t.Layout(gtx)
+		},
+		func(gtx C) D {
+			txt := sm.theme.Body2("Requests to the VSP are signed with your tickets' keys.")
+			txt.Color = sm.theme.Color.Gray
+			return txt.Layout(gtx)
+		},
+		sm.passwordEditor.Layout,
+	}
+
+	if sm.statuses != nil {
+		if len(sm.statuses.Statuses) == 0 {
+			w = append(w, sm.theme.Body1("No unspent tickets").Layout)
+		}
+		for i := range sm.statuses.Statuses {
+			status := sm.statuses.Statuses[i]
+			w = append(w, func(gtx C) D {
+				return sm.ticketStatusLayout(gtx, status)
+			})
+		}
+	}
+
+	w = append(w, func(gtx C) D {
+		return layout.E.Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				layout.Rigid(sm.btnClose.Layout),
+				layout.Rigid(func(gtx C) D {
+					if sm.isChecking {
+						return sm.theme.Body1("Checking...").Layout(gtx)
+					}
+					return sm.btnCheck.Layout(gtx)
+				}),
+			)
+		})
+	})
+
+	return sm.modal.Layout(gtx, w, 850)
+}
@@ -46,6 +46,11 @@ type Window struct {
 	modalMutex sync.Mutex
 	modals     []Modal
 
+	// responseHandlers holds the callbacks of pages waiting on the result
+	// of a wallet command, keyed by the request ID the command returned.
+	responseMutex    sync.Mutex
+	responseHandlers map[wallet.RequestID]func(wallet.Response)
+
 	currentPage   Page
 	pageBackStack []Page
 
@@ -105,6 +110,7 @@ func CreateWindow(wal *wallet.Wallet, decredIcons map[string]image.Image, collec
 
 	win.wallet = wal
 	win.states.loading = false
+	win.responseHandlers = make(map[wallet.RequestID]func(wallet.Response))
 
 	win.keyEvents = make(chan *key.Event)
 
@@ -146,6 +152,7 @@ func (win *Window) NewLoad(decredIcons map[string]image.Image) *load.Load {
 
 	l.SelectedWallet = &win.selected
 	l.RefreshWindow = win.refreshWindow
+	l.OnResponse = win.onResponse
 	l.ShowModal = win.showModal
 	l.DismissModal = win.dismissModal
 	l.PopWindowPage = win.popPage
@@ -196,6 +203,27 @@ func (win *Window) refreshWindow() {
 	win.invalidate <- struct{}{}
 }
 
+// onResponse registers handler to receive the response to the wallet command
+// that returned id. The handler is called once, from the window loop, before
+// the response updates the shared window state. Errors sent to a handler are
+// left for it to report.
+func (win *Window) onResponse(id wallet.RequestID, handler func(wallet.Response)) {
+	win.responseMutex.Lock()
+	win.responseHandlers[id] = handler
+	win.responseMutex.Unlock()
+}
+
+// takeResponseHandler removes and returns the handler registered for id.
+func (win *Window) takeResponseHandler(id wallet.RequestID) (func(wallet.Response), bool) {
+	win.responseMutex.Lock()
+	defer win.responseMutex.Unlock()
+	handler, ok := win.responseHandlers[id]
+	if ok {
+		delete(win.responseHandlers, id)
+	}
+	return handler, ok
+}
+
 func (win *Window) showModal(modal Modal) {
 	modal.OnResume() // setup display data
 	win.modalMutex.Lock()
@@ -261,9 +289,18 @@ func (win *Window) Loop(w *app.Window, shutdown chan int) {
 		case <-win.invalidate:
 			w.Invalidate()
 		case e := <-win.wallet.Send:
+			if handler, ok := win.takeResponseHandler(e.ID); ok {
+				handler(e)
+				if e.Err != nil {
+					log.Errorf("Wallet Error (%s #%d): %v", e.Op, e.ID, e.Err)
+					op.InvalidateOp{}.Add(win.ops)
+					break
+				}
+			}
+
 			if e.Err != nil {
 				err := e.Err.Error()
-				log.Error("Wallet Error: " + err)
+				log.Errorf("Wallet Error (%s #%d): %s", e.Op, e.ID, err)
 				if err == dcrlibwallet.ErrWalletDatabaseInUse {
 					close(shutdown)
 					win.unloaded(w)
@@ -0,0 +1,265 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+// AddressBookConfigKey is the multiwallet user config key the address book is
+// stored under.
+const AddressBookConfigKey = "address_book"
+
+// addressNetworks are the networks an address book entry can belong to.
+var addressNetworks = []string{"mainnet", "testnet3"}
+
+var (
+	// ErrInvalidContact is returned when a contact has no label or an
+	// address that is not valid on any known network.
+	ErrInvalidContact = errors.New("contact needs a label and a valid address")
+
+	// ErrContactNotFound is returned when no contact has the given address.
+	ErrContactNotFound = errors.New("address not in address book")
+)
+
+// Contact is an address book entry.
+type Contact struct {
+	Label   string `json:"label"`
+	Address string `json:"address"`
+	Network string `json:"network"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// AddressCheck describes an address about to be sent to.
+type AddressCheck struct {
+	// Network is the network the address is valid on, or empty if it is
+	// not a valid address.
+	Network string
+	// WrongNetwork is set if the address is not valid on the network the
+	// wallet runs on.
+	WrongNetwork bool
+	// OwnWallet is the name of the loaded wallet the address belongs to.
+	OwnWallet string
+	// Contact is the address book entry of the address, if any.
+	Contact *Contact
+}
+
+// AddressNetwork returns the network address is valid on.
+func AddressNetwork(address string) (string, bool) {
+	for _, net := range addressNetworks {
+		params, err := utils.ChainParams(net)
+		if err != nil {
+			continue
+		}
+		if _, err := dcrutil.DecodeAddress(address, params); err == nil {
+			return net, true
+		}
+	}
+	return "", false
+}
+
+// Contacts returns the address book sorted by label.
+func (wal *Wallet) Contacts() []Contact {
+	var contacts []Contact
+	if err := wal.multi.ReadUserConfigValue(AddressBookConfigKey, &contacts); err != nil {
+		return nil
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return strings.ToLower(contacts[i].Label) < strings.ToLower(contacts[j].Label)
+	})
+	return contacts
+}
+
+func (wal *Wallet) saveContacts(contacts []Contact) {
+	wal.SaveConfigValueForKey(AddressBookConfigKey, contacts)
+}
+
+// validContact trims the fields of c and fills in the network of its address.
+func validContact(c Contact) (Contact, error) {
+	c.Label = strings.TrimSpace(c.Label)
+	c.Address = strings.TrimSpace(c.Address)
+	c.Notes = strings.TrimSpace(c.Notes)
+	net, ok := AddressNetwork(c.Address)
+	if c.Label == "" || !ok {
+		return c, ErrInvalidContact
+	}
+	c.Network = net
+	return c, nil
+}
+
+// SaveContact adds c to the address book, replacing the entry with the same
+// address.
+func (wal *Wallet) SaveContact(c Contact) error {
+	c, err := validContact(c)
+	if err != nil {
+		return err
+	}
+
+	wal.addressBookMtx.Lock()
+	defer wal.addressBookMtx.Unlock()
+
+	contacts := wal.Contacts()
+	for i := range contacts {
+		if contacts[i].Address == c.Address {
+			contacts[i] = c
+			wal.saveContacts(contacts)
+			return nil
+		}
+	}
+	wal.saveContacts(append(contacts, c))
+	return nil
+}
+
+// DeleteContact removes the entry with address from the address book.
+func (wal *Wallet) DeleteContact(address string) error {
+	wal.addressBookMtx.Lock()
+	defer wal.addressBookMtx.Unlock()
+
+	contacts := wal.Contacts()
+	for i := range contacts {
+		if contacts[i].Address == address {
+			wal.saveContacts(append(contacts[:i], contacts[i+1:]...))
+			return nil
+		}
+	}
+	return ErrContactNotFound
+}
+
+// ContactWithAddress returns the address book entry of address.
+func (wal *Wallet) ContactWithAddress(address string) (*Contact, bool) {
+	for _, c := range wal.Contacts() {
+		if c.Address == address {
+			return &c, true
+		}
+	}
+	return nil, false
+}
+
+// SuggestContacts returns the contacts on the wallet network whose label or
+// address starts with prefix, ignoring case, for autocompleting a
+// destination address.
+func (wal *Wallet) SuggestContacts(prefix string) []Contact {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return nil
+	}
+
+	var matches []Contact
+	for _, c := range wal.Contacts() {
+		if c.Network != wal.Net {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(c.Label), prefix) ||
+			strings.HasPrefix(strings.ToLower(c.Address), prefix) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// CheckAddress reports the network of address, whether it belongs to one of
+// the loaded wallets and its address book entry.
+func (wal *Wallet) CheckAddress(address string) AddressCheck {
+	var check AddressCheck
+	check.Network, _ = AddressNetwork(address)
+	if check.Network == "" {
+		return check
+	}
+
+	check.WrongNetwork = check.Network != wal.Net
+	if !check.WrongNetwork {
+		_, check.OwnWallet = wal.HaveAddress(address)
+	}
+	check.Contact, _ = wal.ContactWithAddress(address)
+	return check
+}
+
+// ExportContacts writes the address book to w as a JSON array.
+func (wal *Wallet) ExportContacts(w io.Writer) error {
+	contacts := wal.Contacts()
+	if contacts == nil {
+		contacts = []Contact{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(contacts)
+}
+
+// ImportContacts adds the contacts of the JSON array read from r to the
+// address book, replacing entries with the same address. Nothing is imported
+// if any contact is invalid. It returns the number of contacts imported.
+func (wal *Wallet) ImportContacts(r io.Reader) (int, error) {
+	var imported []Contact
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return 0, fmt.Errorf("invalid address book: %v", err)
+	}
+	for i := range imported {
+		c, err := validContact(imported[i])
+		if err != nil {
+			return 0, fmt.Errorf("contact %d: %v", i+1, err)
+		}
+		imported[i] = c
+	}
+
+	wal.addressBookMtx.Lock()
+	defer wal.addressBookMtx.Unlock()
+
+	contacts := wal.Contacts()
+	index := make(map[string]int, len(contacts))
+	for i, c := range contacts {
+		index[c.Address] = i
+	}
+	for _, c := range imported {
+		if i, ok := index[c.Address]; ok {
+			contacts[i] = c
+			continue
+		}
+		index[c.Address] = len(contacts)
+		contacts = append(contacts, c)
+	}
+	wal.saveContacts(contacts)
+	return len(imported), nil
+}
+
+// ExportContactsFile writes the address book to a JSON file in the exports
+// folder of the app data directory and returns its path.
+func (wal *Wallet) ExportContactsFile() (string, error) {
+	dir := filepath.Join(wal.root, exportDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("address-book-%s.json", time.Now().Format("20060102-150405")))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = wal.ExportContacts(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// ImportContactsFile imports the address book JSON file at path.
+func (wal *Wallet) ImportContactsFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return wal.ImportContacts(file)
+}
@@ -0,0 +1,93 @@
+package wallet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Address book", func() {
+	const (
+		mainnetAddr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+		testnetAddr = "TsfDLrRkk9ciUuwfp2b8PawwnukYD7yAjGd"
+	)
+
+	AfterEach(func() {
+		wal.RemoveUserConfigValueForKey(AddressBookConfigKey)
+	})
+
+	It("saves, replaces and deletes contacts", func() {
+		Expect(wal.SaveContact(Contact{Label: "Bob", Address: testnetAddr})).To(Succeed())
+		Expect(wal.SaveContact(Contact{Label: " alice ", Address: mainnetAddr, Notes: "exchange"})).To(Succeed())
+		Expect(wal.SaveContact(Contact{Label: "", Address: testnetAddr})).To(Equal(ErrInvalidContact))
+		Expect(wal.SaveContact(Contact{Label: "Carol", Address: "not an address"})).To(Equal(ErrInvalidContact))
+
+		contacts := wal.Contacts()
+		Expect(contacts).To(HaveLen(2))
+		Expect(contacts[0]).To(Equal(Contact{Label: "alice", Address: mainnetAddr, Network: "mainnet", Notes: "exchange"}))
+		Expect(contacts[1].Network).To(Equal("testnet3"))
+
+		Expect(wal.SaveContact(Contact{Label: "Robert", Address: testnetAddr})).To(Succeed())
+		contact, ok := wal.ContactWithAddress(testnetAddr)
+		Expect(ok).To(BeTrue())
+		Expect(contact.Label).To(Equal("Robert"))
+		Expect(wal.Contacts()).To(HaveLen(2))
+
+		Expect(wal.DeleteContact(mainnetAddr)).To(Succeed())
+		Expect(wal.DeleteContact(mainnetAddr)).To(Equal(ErrContactNotFound))
+		Expect(wal.Contacts()).To(HaveLen(1))
+	})
+
+	It("suggests contacts on the wallet network", func() {
+		Expect(wal.SaveContact(Contact{Label: "Bob", Address: testnetAddr})).To(Succeed())
+		Expect(wal.SaveContact(Contact{Label: "Bobby", Address: mainnetAddr})).To(Succeed())
+
+		Expect(wal.SuggestContacts("bo")).To(HaveLen(1))
+		Expect(wal.SuggestContacts("Tsf")).To(HaveLen(1))
+		Expect(wal.SuggestContacts("x")).To(BeEmpty())
+		Expect(wal.SuggestContacts("")).To(BeEmpty())
+	})
+
+	It("warns about addresses of other networks", func() {
+		check := wal.CheckAddress(mainnetAddr)
+		Expect(check.Network).To(Equal("mainnet"))
+		Expect(check.WrongNetwork).To(BeTrue())
+
+		Expect(wal.SaveContact(Contact{Label: "Bob", Address: testnetAddr})).To(Succeed())
+		check = wal.CheckAddress(testnetAddr)
+		Expect(check.WrongNetwork).To(BeFalse())
+		Expect(check.OwnWallet).To(BeEmpty())
+		Expect(check.Contact.Label).To(Equal("Bob"))
+
+		Expect(wal.CheckAddress("invalid").Network).To(BeEmpty())
+	})
+
+	It("exports and imports JSON", func() {
+		Expect(wal.SaveContact(Contact{Label: "Bob", Address: testnetAddr})).To(Succeed())
+
+		var buf bytes.Buffer
+		Expect(wal.ExportContacts(&buf)).To(Succeed())
+		var exported []Contact
+		Expect(json.Unmarshal(buf.Bytes(), &exported)).To(Succeed())
+		Expect(exported).To(Equal(wal.Contacts()))
+
+		n, err := wal.ImportContacts(strings.NewReader(`[
+			{"label": "Bob's new label", "address": "` + testnetAddr + `"},
+			{"label": "Alice", "address": "` + mainnetAddr + `", "network": "testnet3"}
+		]`))
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(2))
+		contacts := wal.Contacts()
+		Expect(contacts).To(HaveLen(2))
+		Expect(contacts[0].Network).To(Equal("mainnet"))
+		Expect(contacts[1].Label).To(Equal("Bob's new label"))
+
+		_, err = wal.ImportContacts(strings.NewReader(`[{"label": "Eve", "address": "bad"}]`))
+		Expect(err).ToNot(BeNil())
+		Expect(wal.Contacts()).To(HaveLen(2))
+	})
+})
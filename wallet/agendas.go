@@ -0,0 +1,285 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+// voteChoicesConfigKey maps the agendas a wallet chose to vote on to its
+// choices.
+const voteChoicesConfigKey = "vote_choices"
+
+// Agenda states, from the deployment's voting window.
+const (
+	AgendaUpcoming = "upcoming"
+	AgendaVoting   = "voting"
+	AgendaFinished = "finished"
+)
+
+// AgendaChoice is a choice tickets can vote for on an agenda.
+type AgendaChoice struct {
+	ID          string
+	Description string
+	IsAbstain   bool
+	IsNo        bool
+}
+
+// Agenda is a consensus rule change voted on by tickets.
+type Agenda struct {
+	ID          string
+	Description string
+	// Version is the stake version the agenda is voted on in.
+	Version    uint32
+	Choices    []AgendaChoice
+	StartTime  time.Time
+	ExpireTime time.Time
+	// Choice is the choice of the wallet.
+	Choice string
+}
+
+// Status returns whether voting on the agenda is upcoming, started or over.
+func (a *Agenda) Status(now time.Time) string {
+	switch {
+	case now.Before(a.StartTime):
+		return AgendaUpcoming
+	case now.Before(a.ExpireTime):
+		return AgendaVoting
+	default:
+		return AgendaFinished
+	}
+}
+
+// Agendas holds the agendas of the network with a wallet's choices.
+type Agendas struct {
+	WalletID int
+	Agendas  []Agenda
+}
+
+// VoteChoiceSet is sent when Wallet.SetVoteChoice has saved a vote choice and
+// pushed it to the VSPs.
+type VoteChoiceSet struct {
+	WalletID int
+	AgendaID string
+	ChoiceID string
+	// Updated is the number of tickets whose VSP now votes with the choice.
+	Updated int
+	// Failed holds the error of each ticket whose VSP was not updated.
+	Failed map[string]string
+}
+
+// ActiveChoices returns the entries of choices, keyed by agenda ID, whose
+// agenda is voting or upcoming at now. Choices on finished agendas and on
+// agendas not listed are left out.
+func (a *Agendas) ActiveChoices(choices map[string]string, now time.Time) map[string]string {
+	active := make(map[string]string)
+	for _, agenda := range a.Agendas {
+		choice, ok := choices[agenda.ID]
+		if ok && agenda.Status(now) != AgendaFinished {
+			active[agenda.ID] = choice
+		}
+	}
+	return active
+}
+
+func readVoteChoices(w *dcrlibwallet.Wallet) map[string]string {
+	choices := make(map[string]string)
+	w.ReadUserConfigValue(voteChoicesConfigKey, &choices)
+	return choices
+}
+
+// Agendas returns the agendas of the current stake version and any agenda
+// that is still to be voted on, with the choices of the wallet. Agendas the
+// wallet has no choice for are abstained from.
+func (wal *Wallet) Agendas(walletID int) (*Agendas, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	params, err := utils.ChainParams(w.NetType())
+	if err != nil {
+		return nil, err
+	}
+
+	var latest uint32
+	for version := range params.Deployments {
+		if version > latest {
+			latest = version
+		}
+	}
+
+	now := time.Now()
+	choices := readVoteChoices(w)
+	agendas := &Agendas{WalletID: walletID}
+	for version, deployments := range params.Deployments {
+		for _, deployment := range deployments {
+			agenda := Agenda{
+				ID:          deployment.Vote.Id,
+				Description: deployment.Vote.Description,
+				Version:     version,
+				StartTime:   time.Unix(int64(deployment.StartTime), 0),
+				ExpireTime:  time.Unix(int64(deployment.ExpireTime), 0),
+				Choice:      "abstain",
+			}
+			if version != latest && agenda.Status(now) == AgendaFinished {
+				continue
+			}
+			for _, choice := range deployment.Vote.Choices {
+				agenda.Choices = append(agenda.Choices, AgendaChoice{
+					ID:          choice.Id,
+					Description: choice.Description,
+					IsAbstain:   choice.IsAbstain,
+					IsNo:        choice.IsNo,
+				})
+				if choice.IsAbstain {
+					agenda.Choice = choice.Id
+				}
+			}
+			if choice, ok := choices[agenda.ID]; ok {
+				agenda.Choice = choice
+			}
+			agendas.Agendas = append(agendas.Agendas, agenda)
+		}
+	}
+
+	sort.Slice(agendas.Agendas, func(i, j int) bool {
+		a, b := agendas.Agendas[i], agendas.Agendas[j]
+		if a.Version != b.Version {
+			return a.Version > b.Version
+		}
+		return a.ID < b.ID
+	})
+	return agendas, nil
+}
+
+// SetVoteChoiceCtx saves the wallet's choice on an agenda and asks the VSP of
+// each ticket that has not voted yet to vote with all of the wallet's
+// choices and treasury policies. Tickets whose VSP could not be updated are
+// listed in the result. dcrlibwallet does not expose the wallet's own vote
+// choices, so tickets voted on by the wallet itself are not affected.
+// It blocks until all VSPs are updated or ctx is canceled.
+func (wal *Wallet) SetVoteChoiceCtx(ctx context.Context, walletID int, agendaID, choiceID string, passphrase []byte) (*VoteChoiceSet, error) {
+	agendas, err := wal.Agendas(walletID)
+	if err != nil {
+		return nil, err
+	}
+	valid := false
+	for _, agenda := range agendas.Agendas {
+		if agenda.ID != agendaID {
+			continue
+		}
+		for _, choice := range agenda.Choices {
+			valid = valid || choice.ID == choiceID
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid choice %s on agenda %s", choiceID, agendaID)
+	}
+
+	updated, failed, err := wal.syncVSPVoteChoices(ctx, walletID, passphrase, func(w *dcrlibwallet.Wallet) {
+		choices := readVoteChoices(w)
+		choices[agendaID] = choiceID
+		w.SaveUserConfigValue(voteChoicesConfigKey, choices)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VoteChoiceSet{
+		WalletID: walletID,
+		AgendaID: agendaID,
+		ChoiceID: choiceID,
+		Updated:  updated,
+		Failed:   failed,
+	}, nil
+}
+
+// syncVSPVoteChoices finds the VSPs of the wallet's unspent tickets, which
+// checks the passphrase, and calls save before pushing the wallet's vote
+// choices on agendas that are voting or upcoming and its treasury policies
+// to them. It returns the number of tickets updated and the error of each
+// ticket that was not.
+func (wal *Wallet) syncVSPVoteChoices(ctx context.Context, walletID int, passphrase []byte,
+	save func(w *dcrlibwallet.Wallet)) (int, map[string]string, error) {
+	statuses, err := wal.VSPTicketStatusCtx(ctx, walletID, passphrase)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	agendas, err := wal.Agendas(walletID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	w := wal.multi.WalletWithID(walletID)
+	save(w)
+	policies := readTreasuryPolicies(w)
+	choices := VSPVoteChoices{
+		VoteChoices:    agendas.ActiveChoices(readVoteChoices(w), time.Now()),
+		TSpendPolicy:   policies.TSpends,
+		TreasuryPolicy: policies.Keys,
+	}
+
+	updated := 0
+	failed := make(map[string]string)
+	clients := make(map[string]*VSPClient)
+	for _, status := range statuses.Statuses {
+		if status.Host == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, nil, err
+		}
+
+		err := wal.pushVoteChoices(ctx, w, clients, status, choices, passphrase)
+		if err != nil {
+			failed[status.TicketHash] = err.Error()
+			continue
+		}
+		updated++
+	}
+	return updated, failed, nil
+}
+
+func (wal *Wallet) pushVoteChoices(ctx context.Context, w *dcrlibwallet.Wallet, clients map[string]*VSPClient,
+	status VSPTicketStatus, choices VSPVoteChoices, passphrase []byte) error {
+	client, ok := clients[status.Host]
+	if !ok {
+		var err error
+		client, err = wal.vspClient(ctx, status.Host)
+		if err != nil {
+			return err
+		}
+		clients[status.Host] = client
+	}
+
+	hash, err := chainhash.NewHashFromStr(status.TicketHash)
+	if err != nil {
+		return err
+	}
+	sign, err := ticketSigner(w, hash[:], passphrase)
+	if err != nil {
+		return err
+	}
+	return client.SetVoteChoices(ctx, status.TicketHash, choices, sign)
+}
+
+// SetVoteChoice saves the wallet's choice on an agenda and pushes it to the
+// VSPs of its tickets.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) SetVoteChoice(walletID int, agendaID, choiceID string, passphrase []byte) RequestID {
+	req := wal.newRequest(OpSetVoteChoice)
+	go func() {
+		set, err := wal.SetVoteChoiceCtx(context.Background(), walletID, agendaID, choiceID, passphrase)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(set)
+	}()
+	return req.ID
+}
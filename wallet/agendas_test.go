@@ -0,0 +1,75 @@
+package wallet_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Agendas", func() {
+	It("lists the agendas of the network", func() {
+		agendas, err := wal.Agendas(1)
+		Expect(err).To(BeNil())
+		Expect(agendas.Agendas).NotTo(BeEmpty())
+
+		latest := agendas.Agendas[0].Version
+		for _, agenda := range agendas.Agendas {
+			Expect(agenda.Version).To(BeNumerically("<=", latest))
+			Expect(agenda.Choices).NotTo(BeEmpty())
+			Expect(agenda.Choice).To(Equal("abstain"))
+		}
+
+		_, err = wal.Agendas(99)
+		Expect(err).To(Equal(ErrIDNotExist))
+	})
+
+	It("saves the vote choices of a wallet", func() {
+		ctx := context.Background()
+		agendas, err := wal.Agendas(1)
+		Expect(err).To(BeNil())
+		agenda := agendas.Agendas[0]
+		choice := agenda.Choices[len(agenda.Choices)-1].ID
+
+		_, err = wal.SetVoteChoiceCtx(ctx, 1, agenda.ID, "maybe", []byte("password"))
+		Expect(err).NotTo(BeNil())
+		_, err = wal.SetVoteChoiceCtx(ctx, 1, agenda.ID, choice, []byte("wrong"))
+		Expect(err).To(MatchError(dcrlibwallet.ErrInvalidPassphrase))
+
+		set, err := wal.SetVoteChoiceCtx(ctx, 1, agenda.ID, choice, []byte("password"))
+		Expect(err).To(BeNil())
+		Expect(set.Updated).To(BeZero())
+		Expect(set.Failed).To(BeEmpty())
+
+		agendas, err = wal.Agendas(1)
+		Expect(err).To(BeNil())
+		Expect(agendas.Agendas[0].Choice).To(Equal(choice))
+
+		_, err = wal.SetVoteChoiceCtx(ctx, 1, agenda.ID, "abstain", []byte("password"))
+		Expect(err).To(BeNil())
+	})
+
+	It("keeps only the choices of agendas being or about to be voted on", func() {
+		now := time.Now()
+		agendas := &Agendas{Agendas: []Agenda{
+			{ID: "upcoming", StartTime: now.Add(time.Hour), ExpireTime: now.Add(2 * time.Hour)},
+			{ID: "voting", StartTime: now.Add(-time.Hour), ExpireTime: now.Add(time.Hour)},
+			{ID: "finished", StartTime: now.Add(-2 * time.Hour), ExpireTime: now.Add(-time.Hour)},
+			{ID: "unset", StartTime: now.Add(-time.Hour), ExpireTime: now.Add(time.Hour)},
+		}}
+		choices := map[string]string{
+			"upcoming": "yes",
+			"voting":   "no",
+			"finished": "yes",
+			"unknown":  "yes",
+		}
+
+		Expect(agendas.ActiveChoices(choices, now)).To(Equal(map[string]string{
+			"upcoming": "yes",
+			"voting":   "no",
+		}))
+	})
+})
@@ -0,0 +1,258 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"decred.org/dcrwallet/wallet/txrules"
+	"decred.org/dcrwallet/wallet/txsizes"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+const (
+	utxoLabelsConfigKey  = "utxo_labels"
+	frozenUTXOsConfigKey = "frozen_utxos"
+)
+
+var (
+	// ErrUTXOFrozen is returned when a frozen output is chosen to be spent.
+	ErrUTXOFrozen = errors.New("frozen outputs cannot be spent, unfreeze them first")
+
+	// ErrAccountHasFrozenUTXOs is returned when tickets are bought or a VSP
+	// fee is paid from an account with frozen outputs. dcrlibwallet picks
+	// the outputs of those transactions itself and cannot be told to skip
+	// the frozen ones.
+	ErrAccountHasFrozenUTXOs = errors.New("the account has frozen outputs, which tickets and VSP fees could spend; unfreeze them or move them to another account first")
+)
+
+// UTXOSort is the order of a list of unspent outputs.
+type UTXOSort int
+
+const (
+	UTXOSortAmount UTXOSort = iota
+	// UTXOSortAge orders outputs by the time they were received.
+	UTXOSortAge
+	UTXOSortConfirmations
+)
+
+func readUTXOLabels(w *dcrlibwallet.Wallet) map[string]string {
+	labels := make(map[string]string)
+	w.ReadUserConfigValue(utxoLabelsConfigKey, &labels)
+	return labels
+}
+
+func readFrozenUTXOs(w *dcrlibwallet.Wallet) map[string]bool {
+	frozen := make(map[string]bool)
+	w.ReadUserConfigValue(frozenUTXOsConfigKey, &frozen)
+	return frozen
+}
+
+// UTXOLabels returns the labels of the outputs of a wallet by output key.
+func (wal *Wallet) UTXOLabels(walletID int) map[string]string {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return map[string]string{}
+	}
+	return readUTXOLabels(w)
+}
+
+// SetUTXOLabel labels an output of a wallet. An empty label removes it.
+func (wal *Wallet) SetUTXOLabel(walletID int, outputKey, label string) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	labels := readUTXOLabels(w)
+	if label == "" {
+		delete(labels, outputKey)
+	} else {
+		labels[outputKey] = label
+	}
+	w.SaveUserConfigValue(utxoLabelsConfigKey, labels)
+	return nil
+}
+
+// FrozenUTXOs returns the keys of the frozen outputs of a wallet.
+func (wal *Wallet) FrozenUTXOs(walletID int) map[string]bool {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return map[string]bool{}
+	}
+	return readFrozenUTXOs(w)
+}
+
+// FreezeUTXO freezes or unfreezes an output of a wallet. Frozen outputs are
+// never selected to fund a transaction, and tickets are not bought from
+// accounts that hold any.
+func (wal *Wallet) FreezeUTXO(walletID int, outputKey string, freeze bool) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	frozen := readFrozenUTXOs(w)
+	if freeze {
+		frozen[outputKey] = true
+	} else {
+		delete(frozen, outputKey)
+	}
+	w.SaveUserConfigValue(frozenUTXOsConfigKey, frozen)
+	return nil
+}
+
+// SortUnspentOutputs sorts list by the given order, largest, newest or most
+// confirmed first unless ascending is set.
+func SortUnspentOutputs(list []*UnspentOutput, by UTXOSort, ascending bool) {
+	less := func(a, b *UnspentOutput) bool {
+		switch by {
+		case UTXOSortAge:
+			return a.UTXO.ReceiveTime < b.UTXO.ReceiveTime
+		case UTXOSortConfirmations:
+			return a.UTXO.Confirmations < b.UTXO.Confirmations
+		default:
+			return a.UTXO.Amount < b.UTXO.Amount
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if ascending {
+			return less(list[i], list[j])
+		}
+		return less(list[j], list[i])
+	})
+}
+
+// estimatedTxFee is the fee of a transaction spending inputs P2PKH outputs
+// to recipients P2PKH outputs and change at the default relay fee.
+func estimatedTxFee(inputs, recipients int) int64 {
+	scriptSizes := make([]int, inputs)
+	for i := range scriptSizes {
+		scriptSizes[i] = txsizes.RedeemP2PKHSigScriptSize
+	}
+	outputs := make([]*wire.TxOut, recipients)
+	for i := range outputs {
+		outputs[i] = &wire.TxOut{PkScript: make([]byte, txsizes.P2PKHPkScriptSize)}
+	}
+	size := txsizes.EstimateSerializeSize(scriptSizes, outputs, txsizes.P2PKHPkScriptSize)
+	return int64(txrules.FeeForSerializeSize(txrules.DefaultRelayFeePerKb, size))
+}
+
+// SelectInputs picks the outputs of list that are not frozen to send amount
+// to recipients outputs, largest first. All of them are picked to send the
+// max amount.
+func SelectInputs(list []*UnspentOutput, amount int64, recipients int, sendMax bool) ([]string, error) {
+	spendable := make([]*UnspentOutput, 0, len(list))
+	for _, utxo := range list {
+		if !utxo.Frozen {
+			spendable = append(spendable, utxo)
+		}
+	}
+	SortUnspentOutputs(spendable, UTXOSortAmount, false)
+
+	var keys []string
+	var total int64
+	for _, utxo := range spendable {
+		if !sendMax && len(keys) > 0 && total >= amount+estimatedTxFee(len(keys), recipients) {
+			break
+		}
+		keys = append(keys, utxo.UTXO.OutputKey)
+		total += utxo.UTXO.Amount
+	}
+	if len(keys) == 0 || (!sendMax && total < amount+estimatedTxFee(len(keys), recipients)) {
+		return nil, errors.New(dcrlibwallet.ErrInsufficientBalance)
+	}
+	return keys, nil
+}
+
+// CoinControlInputs returns the outputs of an account to spend on a
+// transaction sending amount to recipients outputs. The selected outputs
+// are spent as they are, none of them may be frozen. Without a selection,
+// outputs are picked around the frozen ones; nil is returned when none is
+// frozen so the wallet selects the outputs itself.
+func (wal *Wallet) CoinControlInputs(walletID int, account int32, selected []string, amount int64, recipients int, sendMax bool) ([]string, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	frozen := readFrozenUTXOs(w)
+	if len(selected) > 0 {
+		for _, key := range selected {
+			if frozen[key] {
+				return nil, ErrUTXOFrozen
+			}
+		}
+		return selected, nil
+	}
+	if len(frozen) == 0 {
+		return nil, nil
+	}
+
+	utxos, err := w.UnspentOutputs(account)
+	if err != nil {
+		return nil, err
+	}
+	var frozenInAccount bool
+	list := make([]*UnspentOutput, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Confirmations < w.RequiredConfirmations() {
+			continue
+		}
+		frozenInAccount = frozenInAccount || frozen[utxo.OutputKey]
+		list = append(list, &UnspentOutput{
+			UTXO:   *utxo,
+			Amount: dcrutil.Amount(utxo.Amount).String(),
+			Frozen: frozen[utxo.OutputKey],
+		})
+	}
+	if !frozenInAccount {
+		return nil, nil
+	}
+	return SelectInputs(list, amount, recipients, sendMax)
+}
+
+// InputsAmount returns the total amount of the outputs of an account with
+// the given keys, which a transaction spending them has as inputs.
+func (wal *Wallet) InputsAmount(walletID int, account int32, keys []string) (int64, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return 0, ErrIDNotExist
+	}
+	utxos, err := w.UnspentOutputs(account)
+	if err != nil {
+		return 0, err
+	}
+	amounts := make(map[string]int64, len(utxos))
+	for _, utxo := range utxos {
+		amounts[utxo.OutputKey] = utxo.Amount
+	}
+
+	var total int64
+	for _, key := range keys {
+		amount, ok := amounts[key]
+		if !ok {
+			return 0, fmt.Errorf("output %s is not an unspent output of the account", key)
+		}
+		total += amount
+	}
+	return total, nil
+}
+
+// checkNoFrozenUTXOs returns ErrAccountHasFrozenUTXOs if an unspent output of
+// account is frozen.
+func checkNoFrozenUTXOs(w *dcrlibwallet.Wallet, account int32) error {
+	frozen := readFrozenUTXOs(w)
+	if len(frozen) == 0 {
+		return nil
+	}
+	utxos, err := w.UnspentOutputs(account)
+	if err != nil {
+		return err
+	}
+	for _, utxo := range utxos {
+		if frozen[utxo.OutputKey] {
+			return ErrAccountHasFrozenUTXOs
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package wallet_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+func testUTXO(key string, amount, receiveTime int64, confirmations int32) *UnspentOutput {
+	return &UnspentOutput{UTXO: dcrlibwallet.UnspentOutput{
+		OutputKey:     key,
+		Amount:        amount,
+		ReceiveTime:   receiveTime,
+		Confirmations: confirmations,
+	}}
+}
+
+func outputKeys(list []*UnspentOutput) []string {
+	keys := make([]string, len(list))
+	for i, utxo := range list {
+		keys[i] = utxo.UTXO.OutputKey
+	}
+	return keys
+}
+
+var _ = Describe("Coin control", func() {
+	It("saves labels and frozen outputs per wallet", func() {
+		Expect(wal.SetUTXOLabel(1, "abc:0", "savings")).To(Succeed())
+		Expect(wal.FreezeUTXO(1, "abc:0", true)).To(Succeed())
+		Expect(wal.UTXOLabels(1)).To(Equal(map[string]string{"abc:0": "savings"}))
+		Expect(wal.FrozenUTXOs(1)).To(Equal(map[string]bool{"abc:0": true}))
+
+		_, err := wal.CoinControlInputs(1, 0, []string{"abc:0"}, 1000, 1, false)
+		Expect(err).To(Equal(ErrUTXOFrozen))
+
+		Expect(wal.SetUTXOLabel(1, "abc:0", "")).To(Succeed())
+		Expect(wal.FreezeUTXO(1, "abc:0", false)).To(Succeed())
+		Expect(wal.UTXOLabels(1)).To(BeEmpty())
+		Expect(wal.FrozenUTXOs(1)).To(BeEmpty())
+
+		// Without frozen outputs the wallet selects the outputs itself.
+		keys, err := wal.CoinControlInputs(1, 0, nil, 1000, 1, false)
+		Expect(err).To(BeNil())
+		Expect(keys).To(BeNil())
+
+		Expect(wal.SetUTXOLabel(99, "abc:0", "savings")).To(Equal(ErrIDNotExist))
+		Expect(wal.FreezeUTXO(99, "abc:0", true)).To(Equal(ErrIDNotExist))
+
+		utxos, err := wal.AllUnspentOutputsCtx(context.Background(), 1, 0)
+		Expect(err).To(BeNil())
+		Expect(utxos.List).To(BeEmpty())
+
+		amount, err := wal.InputsAmount(1, 0, nil)
+		Expect(err).To(BeNil())
+		Expect(amount).To(BeZero())
+		_, err = wal.InputsAmount(1, 0, []string{"abc:0"})
+		Expect(err).To(MatchError(ContainSubstring("abc:0")))
+		_, err = wal.InputsAmount(99, 0, nil)
+		Expect(err).To(Equal(ErrIDNotExist))
+	})
+
+	It("sorts unspent outputs", func() {
+		list := []*UnspentOutput{
+			testUTXO("a:0", 300, 10, 5),
+			testUTXO("b:0", 100, 30, 1),
+			testUTXO("c:0", 200, 20, 9),
+		}
+		SortUnspentOutputs(list, UTXOSortAmount, false)
+		Expect(outputKeys(list)).To(Equal([]string{"a:0", "c:0", "b:0"}))
+		SortUnspentOutputs(list, UTXOSortAge, false)
+		Expect(outputKeys(list)).To(Equal([]string{"b:0", "c:0", "a:0"}))
+		SortUnspentOutputs(list, UTXOSortConfirmations, true)
+		Expect(outputKeys(list)).To(Equal([]string{"b:0", "a:0", "c:0"}))
+	})
+
+	It("selects inputs around frozen outputs", func() {
+		list := []*UnspentOutput{
+			testUTXO("a:0", 5e8, 0, 10),
+			testUTXO("b:0", 2e8, 0, 10),
+			testUTXO("c:0", 1e8, 0, 10),
+		}
+		list[0].Frozen = true
+
+		keys, err := SelectInputs(list, 1.5e8, 1, false)
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"b:0"}))
+
+		keys, err = SelectInputs(list, 2.5e8, 1, false)
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"b:0", "c:0"}))
+
+		keys, err = SelectInputs(list, 0, 0, true)
+		Expect(err).To(BeNil())
+		Expect(keys).To(ConsistOf("b:0", "c:0"))
+
+		_, err = SelectInputs(list, 3e8, 1, false)
+		Expect(err).To(MatchError(dcrlibwallet.ErrInsufficientBalance))
+	})
+})
@@ -22,156 +22,206 @@ import (
 	"github.com/planetdecred/dcrlibwallet"
 )
 
-// CreateWallet creates a new wallet with the given parameters.
-// It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) CreateWallet(name, passphrase string, errChan chan error) {
+// sendErr reports err on errChan without blocking the caller. Nothing is sent
+// on a nil errChan.
+func sendErr(errChan chan<- error, err error) {
+	if errChan == nil {
+		return
+	}
 	go func() {
-		var resp Response
-		wall, err := wal.multi.CreateNewWallet(name, passphrase, dcrlibwallet.PassphraseTypePass)
-		sendErr := func(err error) {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not create wallet",
-				Err:     err,
-			})
+		errChan <- err
+	}()
+}
+
+// CreateWalletCtx creates a new wallet with the given parameters and returns its seed.
+// It blocks until the wallet is created or ctx is canceled.
+func (wal *Wallet) CreateWalletCtx(ctx context.Context, name, passphrase string) (*CreatedSeed, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wall, err := wal.multi.CreateNewWallet(name, passphrase, dcrlibwallet.PassphraseTypePass)
+	if err != nil {
+		return nil, MultiWalletError{
+			Message: "Could not create wallet",
+			Err:     err,
 		}
-		if err != nil {
-			sendErr(err)
-			return
+	}
+	seeds, err := wall.DecryptSeed([]byte(passphrase))
+	if err != nil {
+		return nil, MultiWalletError{
+			Message: "Could not create wallet",
+			Err:     err,
 		}
-		seeds, err := wall.DecryptSeed([]byte(passphrase))
+	}
+
+	return &CreatedSeed{
+		Seed: seeds,
+	}, nil
+}
+
+// CreateWallet creates a new wallet with the given parameters.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) CreateWallet(name, passphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpCreateWallet)
+	go func() {
+		seed, err := wal.CreateWalletCtx(context.Background(), name, passphrase)
 		if err != nil {
-			sendErr(err)
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
+		wal.Send <- req.response(*seed)
+	}()
+	return req.ID
+}
+
+// RestoreWalletCtx restores a wallet with the given parameters.
+// It blocks until the wallet is restored or ctx is canceled.
+func (wal *Wallet) RestoreWalletCtx(ctx context.Context, seed, passphrase string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		resp.Resp = CreatedSeed{
-			Seed: seeds,
+	_, err := wal.multi.RestoreWallet("wallet", seed, passphrase, dcrlibwallet.PassphraseTypePass)
+	if err != nil {
+		return MultiWalletError{
+			Message: "Could not restore wallet",
+			Err:     err,
 		}
-		wal.Send <- resp
-	}()
+	}
+	return nil
 }
 
 // RestoreWallet restores a wallet with the given parameters.
 // It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) RestoreWallet(seed, passphrase string, errChan chan error) {
+func (wal *Wallet) RestoreWallet(seed, passphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpRestoreWallet)
 	go func() {
-		var resp Response
-		_, err := wal.multi.RestoreWallet("wallet", seed, passphrase, dcrlibwallet.PassphraseTypePass)
+		err := wal.RestoreWalletCtx(context.Background(), seed, passphrase)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not restore wallet",
-				Err:     err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-		resp.Resp = Restored{}
-		wal.Send <- resp
+		wal.Send <- req.response(Restored{})
 	}()
+	return req.ID
+}
+
+// DeleteWalletCtx deletes a wallet.
+// It blocks until the wallet is deleted or ctx is canceled.
+func (wal *Wallet) DeleteWalletCtx(ctx context.Context, walletID int, passphrase []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log.Debugf("Wallet %d: %+v", walletID, wal.multi.WalletWithID(walletID))
+	err := wal.multi.DeleteWallet(walletID, passphrase)
+	if err != nil {
+		return InternalWalletError{
+			Message:  "Could not delete wallet",
+			Affected: []int{walletID},
+			Err:      err,
+		}
+	}
+	return nil
 }
 
 // DeleteWallet deletes a wallet.
 // It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) DeleteWallet(walletID int, passphrase []byte, errChan chan error) {
+func (wal *Wallet) DeleteWallet(walletID int, passphrase []byte, errChan chan error) RequestID {
+	req := wal.newRequest(OpDeleteWallet)
 	log.Debug("Deleting Wallet")
 	go func() {
-		var resp Response
-		log.Debugf("Wallet %d: %+v", walletID, wal.multi.WalletWithID(walletID))
-		err := wal.multi.DeleteWallet(walletID, passphrase)
+		err := wal.DeleteWalletCtx(context.Background(), walletID, passphrase)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not delete wallet",
-				Affected: []int{walletID},
-				Err:      err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-		resp.Resp = DeletedWallet{
+		wal.Send <- req.response(DeletedWallet{
 			ID: walletID,
-		}
-		wal.Send <- resp
+		})
 	}()
+	return req.ID
 }
 
-// AddAccount adds an account to a wallet.
-// It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) AddAccount(walletID int, name string, pass []byte, errChan chan error, onCreate func(*dcrlibwallet.Account)) {
-	go func() {
-		var resp Response
-		wall := wal.multi.WalletWithID(walletID)
-		if wall == nil {
-			go func() {
-				errChan <- ErrIDNotExist
-			}()
-			resp.Err = ErrIDNotExist
-			wal.Send <- Response{
-				Resp: AddedAccount{},
-				Err:  ErrIDNotExist,
-			}
-			return
+// AddAccountCtx adds an account to a wallet and returns the new account.
+// It blocks until the account is created or ctx is canceled.
+func (wal *Wallet) AddAccountCtx(ctx context.Context, walletID int, name string, pass []byte) (*dcrlibwallet.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return nil, ErrIDNotExist
+	}
+
+	id, err := wall.CreateNewAccount(name, pass)
+	if err != nil {
+		return nil, InternalWalletError{
+			Message:  "Could not create account",
+			Affected: []int{walletID},
+			Err:      err,
 		}
+	}
 
-		id, err := wall.CreateNewAccount(name, pass)
-		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not create account",
-				Affected: []int{walletID},
-				Err:      err,
-			})
-			return
+	acct, err := wall.GetAccount(id)
+	if err != nil {
+		return nil, InternalWalletError{
+			Message:  "Could not fetch newly created account",
+			Affected: []int{walletID},
+			Err:      err,
 		}
+	}
+	return acct, nil
+}
 
-		acct, err := wall.GetAccount(id)
+// AddAccount adds an account to a wallet.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) AddAccount(walletID int, name string, pass []byte, errChan chan error, onCreate func(*dcrlibwallet.Account)) RequestID {
+	req := wal.newRequest(OpAddAccount)
+	go func() {
+		acct, err := wal.AddAccountCtx(context.Background(), walletID, name, pass)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not fetch newly created account",
-				Affected: []int{walletID},
-				Err:      err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
 		onCreate(acct)
 
-		resp.Resp = AddedAccount{
-			ID: id,
-		}
-		wal.Send <- resp
+		wal.Send <- req.response(AddedAccount{
+			ID: acct.Number,
+		})
 	}()
+	return req.ID
+}
+
+// CreateTransactionCtx creates a TxAuthor with the given parameters.
+// The created TxAuthor will have to have a destination added before broadcasting.
+func (wal *Wallet) CreateTransactionCtx(ctx context.Context, walletID int, accountID int32) (*dcrlibwallet.TxAuthor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return wal.multi.NewUnsignedTx(walletID, accountID)
 }
 
 // CreateTransaction creates a TxAuthor with the given parameters.
 // The created TxAuthor will have to have a destination added before broadcasting.
 // It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) CreateTransaction(walletID int, accountID int32, errChan chan error) {
+func (wal *Wallet) CreateTransaction(walletID int, accountID int32, errChan chan error) RequestID {
+	req := wal.newRequest(OpCreateTransaction)
 	go func() {
-		var resp Response
-		txAuthor, err := wal.multi.NewUnsignedTx(walletID, accountID)
+		txAuthor, err := wal.CreateTransactionCtx(context.Background(), walletID, accountID)
 		if err != nil {
 			errChan <- err
 			return
 		}
-		resp.Resp = txAuthor
-		wal.Send <- resp
+		wal.Send <- req.response(txAuthor)
 	}()
+	return req.ID
 }
 
 // transactionStatus accepts the bestBlockHeight, transactionBlockHeight returns a transaction status
@@ -184,136 +234,176 @@ func transactionStatus(bestBlockHeight, txnBlockHeight int32) (string, int32) {
 	return "pending", confirmations
 }
 
+// newTransaction wraps a transaction of the wallet named walletName with its
+// status at bestBlockHeight.
+func newTransaction(walletName string, txnRaw dcrlibwallet.Transaction, bestBlockHeight int32) Transaction {
+	status, confirmations := transactionStatus(bestBlockHeight, txnRaw.BlockHeight)
+	return Transaction{
+		Txn:           txnRaw,
+		Status:        status,
+		Balance:       dcrutil.Amount(txnRaw.Amount).String(),
+		WalletName:    walletName,
+		Confirmations: confirmations,
+		DateTime:      dcrlibwallet.ExtractDateOrTime(txnRaw.Timestamp),
+	}
+}
+
+// BroadcastTransactionCtx broadcasts the transaction built with txAuthor to the network.
+// It blocks until the transaction is broadcast or ctx is canceled.
+func (wal *Wallet) BroadcastTransactionCtx(ctx context.Context, txAuthor *dcrlibwallet.TxAuthor, passphrase []byte) (*Broadcast, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	txHash, err := txAuthor.Broadcast(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("error broadcasting transaction: %s", err.Error())
+	}
+
+	hash, err := chainhash.NewHash(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing successful transaction hash: %s", err.Error())
+	}
+
+	return &Broadcast{
+		TxHash: hash.String(),
+	}, nil
+}
+
 // BroadcastTransaction broadcasts the transaction built with txAuthor to the network.
 // It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) BroadcastTransaction(txAuthor *dcrlibwallet.TxAuthor, passphrase []byte, errChan chan error) {
+func (wal *Wallet) BroadcastTransaction(txAuthor *dcrlibwallet.TxAuthor, passphrase []byte, errChan chan error) RequestID {
+	req := wal.newRequest(OpBroadcastTransaction)
 	go func() {
-		var resp Response
-
-		txHash, err := txAuthor.Broadcast(passphrase)
+		broadcast, err := wal.BroadcastTransactionCtx(context.Background(), txAuthor, passphrase)
 		if err != nil {
-			errChan <- fmt.Errorf("error broadcasting transaction: %s", err.Error())
+			errChan <- err
 			return
 		}
+		wal.Send <- req.response(broadcast)
+	}()
+	return req.ID
+}
 
-		hash, err := chainhash.NewHash(txHash)
-		if err != nil {
-			errChan <- fmt.Errorf("error parsing successful transaction hash: %s", err.Error())
-			return
+// GetAllTransactionsCtx collects a per-wallet slice of transactions fitting the parameters.
+// It blocks until all wallets have been read or ctx is canceled.
+func (wal *Wallet) GetAllTransactionsCtx(ctx context.Context, offset, limit, txfilter int32) (*Transactions, error) {
+	wallets, err := wal.wallets()
+	if err != nil {
+		return nil, err
+	}
+
+	var recentTxs []Transaction
+
+	transactions := make(map[int][]Transaction)
+	ticketTxs := make(map[int][]Transaction)
+	bestBestBlock := wal.multi.GetBestBlock()
+	totalTxn := 0
+
+	for _, wall := range wallets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
-		resp.Resp = &Broadcast{
-			TxHash: hash.String(),
+		txs, err := wall.GetTransactionsRaw(offset, limit, txfilter, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, txnRaw := range txs {
+			totalTxn++
+			txn := newTransaction(wall.Name, txnRaw, bestBestBlock.Height)
+			recentTxs = append(recentTxs, txn)
+			if txn.Txn.Type == dcrlibwallet.TxTypeTicketPurchase {
+				ticketTxs[wall.ID] = append(ticketTxs[wall.ID], txn)
+			}
+			transactions[txnRaw.WalletID] = append(transactions[txnRaw.WalletID], txn)
 		}
-		wal.Send <- resp
-	}()
+	}
+
+	sort.SliceStable(recentTxs, func(i, j int) bool {
+		backTime := time.Unix(recentTxs[j].Txn.Timestamp, 0)
+		frontTime := time.Unix(recentTxs[i].Txn.Timestamp, 0)
+		return backTime.Before(frontTime)
+	})
+
+	recentTxsLimit := 5
+	if len(recentTxs) > recentTxsLimit {
+		recentTxs = recentTxs[:recentTxsLimit]
+	}
+
+	return &Transactions{
+		Total:   totalTxn,
+		Txs:     transactions,
+		Recent:  recentTxs,
+		Tickets: ticketTxs,
+	}, nil
 }
 
 // GetAllTransactions collects a per-wallet slice of transactions fitting the parameters.
 // It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) GetAllTransactions(offset, limit, txfilter int32) {
+func (wal *Wallet) GetAllTransactions(offset, limit, txfilter int32) RequestID {
+	req := wal.newRequest(OpGetAllTransactions)
 	go func() {
-		var resp Response
-		wallets, err := wal.wallets()
+		txs, err := wal.GetAllTransactionsCtx(context.Background(), offset, limit, txfilter)
 		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
+			wal.Send <- req.error(err)
 			return
 		}
+		wal.Send <- req.response(txs)
+	}()
+	return req.ID
+}
 
-		var recentTxs []Transaction
-
-		transactions := make(map[int][]Transaction)
-		ticketTxs := make(map[int][]Transaction)
-		bestBestBlock := wal.multi.GetBestBlock()
-		totalTxn := 0
-
-		for _, wall := range wallets {
-			txs, err := wall.GetTransactionsRaw(offset, limit, txfilter, true)
-			if err != nil {
-				resp.Err = err
-				wal.Send <- resp
-				return
-			}
-			for _, txnRaw := range txs {
-				totalTxn++
-				status, confirmations := transactionStatus(bestBestBlock.Height, txnRaw.BlockHeight)
-				txn := Transaction{
-					Txn:           txnRaw,
-					Status:        status,
-					Balance:       dcrutil.Amount(txnRaw.Amount).String(),
-					WalletName:    wall.Name,
-					Confirmations: confirmations,
-					DateTime:      dcrlibwallet.ExtractDateOrTime(txnRaw.Timestamp),
-				}
-				recentTxs = append(recentTxs, txn)
-				if txn.Txn.Type == dcrlibwallet.TxTypeTicketPurchase {
-					ticketTxs[wall.ID] = append(ticketTxs[wall.ID], txn)
-				}
-				transactions[txnRaw.WalletID] = append(transactions[txnRaw.WalletID], txn)
-			}
-		}
+// GetTransactionCtx gets transaction information by wallet ID and transaction hash.
+func (wal *Wallet) GetTransactionCtx(ctx context.Context, walletID int, txnHash string) (*Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-		sort.SliceStable(recentTxs, func(i, j int) bool {
-			backTime := time.Unix(recentTxs[j].Txn.Timestamp, 0)
-			frontTime := time.Unix(recentTxs[i].Txn.Timestamp, 0)
-			return backTime.Before(frontTime)
-		})
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return nil, ErrIDNotExist
+	}
 
-		recentTxsLimit := 5
-		if len(recentTxs) > recentTxsLimit {
-			recentTxs = recentTxs[:recentTxsLimit]
-		}
+	hash, err := chainhash.NewHashFromStr(txnHash)
+	if err != nil {
+		return nil, err
+	}
 
-		resp.Resp = &Transactions{
-			Total:   totalTxn,
-			Txs:     transactions,
-			Recent:  recentTxs,
-			Tickets: ticketTxs,
-		}
-		wal.Send <- resp
-	}()
+	txn, err := wall.GetTransactionRaw(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	bestBestBlock := wal.multi.GetBestBlock()
+	status, confirmations := transactionStatus(bestBestBlock.Height, txn.BlockHeight)
+	acct, err := wall.GetAccount(txn.Inputs[0].AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Txn:           *txn,
+		Status:        status,
+		Balance:       dcrutil.Amount(txn.Amount).String(),
+		WalletName:    wall.Name,
+		Confirmations: confirmations,
+		DateTime:      dcrlibwallet.ExtractDateOrTime(txn.Timestamp),
+		AccountName:   acct.Name,
+	}, nil
 }
 
 // GetTransaction get transaction information by wallet ID and transaction hash
 // It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) GetTransaction(walletID int, txnHash string) {
+func (wal *Wallet) GetTransaction(walletID int, txnHash string) RequestID {
+	req := wal.newRequest(OpGetTransaction)
 	go func() {
-		var resp Response
-		wall := wal.multi.WalletWithID(walletID)
-
-		hash, err := chainhash.NewHashFromStr(txnHash)
-		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
-			return
-		}
-
-		txn, err := wall.GetTransactionRaw(hash[:])
+		txn, err := wal.GetTransactionCtx(context.Background(), walletID, txnHash)
 		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
+			wal.Send <- req.error(err)
 			return
 		}
-		bestBestBlock := wal.multi.GetBestBlock()
-		status, confirmations := transactionStatus(bestBestBlock.Height, txn.BlockHeight)
-		acct, err := wall.GetAccount(txn.Inputs[0].AccountNumber)
-		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
-			return
-		}
-		resp.Resp = &Transaction{
-			Txn:           *txn,
-			Status:        status,
-			Balance:       dcrutil.Amount(txn.Amount).String(),
-			WalletName:    wall.Name,
-			Confirmations: confirmations,
-			DateTime:      dcrlibwallet.ExtractDateOrTime(txn.Timestamp),
-			AccountName:   acct.Name,
-		}
-		wal.Send <- resp
+		wal.Send <- req.response(txn)
 	}()
+	return req.ID
 }
 
 // WalletSyncStatus returns the sync status of a single wallet
@@ -328,181 +418,216 @@ func walletSyncStatus(isWaiting bool, walletBestBlock, bestBlockHeight int32) st
 	return "synced"
 }
 
-// GetMultiWalletInfo gets bulk information about the loaded wallets.
+// GetMultiWalletInfoCtx gets bulk information about the loaded wallets.
 // Information regarding transactions is collected with respect to wal.confirms as the
 // number of required confirmations for said transactions.
-// It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) GetMultiWalletInfo() {
-	go func() {
-		log.Debug("Getting multiwallet info")
-		var resp Response
-		wallets, err := wal.wallets()
-		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
-			return
+// It blocks until all wallets have been read or ctx is canceled.
+func (wal *Wallet) GetMultiWalletInfoCtx(ctx context.Context) (*MultiWalletInfo, error) {
+	log.Debug("Getting multiwallet info")
+	wallets, err := wal.wallets()
+	if err != nil {
+		return nil, err
+	}
+
+	var completeTotal int64
+	infos := make([]InfoShort, len(wallets))
+	i := 0
+	for _, wall := range wallets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
-		var completeTotal int64
-		infos := make([]InfoShort, len(wallets))
-		i := 0
-		for _, wall := range wallets {
-			iter, err := wall.AccountsIterator()
-			if err != nil {
-				resp.Err = err
-				wal.Send <- resp
-				return
-			}
-			var acctBalance, spendableBalance int64
-			accts := make([]Account, 0)
-			for acct := iter.Next(); acct != nil; acct = iter.Next() {
-				var addr string
-				if acct.Number != math.MaxInt32 {
-					var er error
-					addr, er = wall.CurrentAddress(acct.Number)
-					if er != nil {
-						log.Error("Could not get current address for wallet ", wall.ID, "account", acct.Number)
-					}
+		iter, err := wall.AccountsIterator()
+		if err != nil {
+			return nil, err
+		}
+		var acctBalance, spendableBalance int64
+		accts := make([]Account, 0)
+		for acct := iter.Next(); acct != nil; acct = iter.Next() {
+			var addr string
+			if acct.Number != math.MaxInt32 {
+				var er error
+				addr, er = wall.CurrentAddress(acct.Number)
+				if er != nil {
+					log.Error("Could not get current address for wallet ", wall.ID, "account", acct.Number)
 				}
-				accts = append(accts, Account{
-					Number:           acct.Number,
-					Name:             acct.Name,
-					TotalBalance:     dcrutil.Amount(acct.TotalBalance).String(),
-					SpendableBalance: acct.Balance.Spendable,
-					Balance: Balance{
-						Total:                   acct.Balance.Total,
-						Spendable:               acct.Balance.Spendable,
-						ImmatureReward:          acct.Balance.ImmatureReward,
-						ImmatureStakeGeneration: acct.Balance.ImmatureStakeGeneration,
-						LockedByTickets:         acct.Balance.LockedByTickets,
-						VotingAuthority:         acct.Balance.VotingAuthority,
-						UnConfirmed:             acct.Balance.UnConfirmed,
-					},
-					Keys: struct {
-						Internal, External, Imported string
-					}{
-						Internal: strconv.Itoa(int(acct.InternalKeyCount)),
-						External: strconv.Itoa(int(acct.ExternalKeyCount)),
-						Imported: strconv.Itoa(int(acct.ImportedKeyCount)),
-					},
-					HDPath:         wal.hdPrefix() + strconv.Itoa(int(acct.Number)) + "'",
-					CurrentAddress: addr,
-				})
-				acctBalance += acct.TotalBalance
-				spendableBalance += acct.Balance.Spendable
 			}
-			completeTotal += acctBalance
-
-			infos[i] = InfoShort{
-				ID:               wall.ID,
-				Name:             wall.Name,
-				Balance:          dcrutil.Amount(acctBalance).String(),
-				SpendableBalance: spendableBalance,
-				Accounts:         accts,
-				BestBlockHeight:  wall.GetBestBlock(),
-				BlockTimestamp:   wall.GetBestBlockTimeStamp(),
-				DaysBehind:       fmt.Sprintf("%s behind", calculateDaysBehind(wall.GetBestBlockTimeStamp())),
-				Status:           walletSyncStatus(wall.IsWaiting(), wall.GetBestBlock(), wal.OverallBlockHeight),
-				Seed:             wall.EncryptedSeed,
-				IsWatchingOnly:   wall.IsWatchingOnlyWallet(),
-			}
-			i++
-		}
+			accts = append(accts, Account{
+				Number:           acct.Number,
+				Name:             acct.Name,
+				TotalBalance:     dcrutil.Amount(acct.TotalBalance).String(),
+				SpendableBalance: acct.Balance.Spendable,
+				Balance: Balance{
+					Total:                   acct.Balance.Total,
+					Spendable:               acct.Balance.Spendable,
+					ImmatureReward:          acct.Balance.ImmatureReward,
+					ImmatureStakeGeneration: acct.Balance.ImmatureStakeGeneration,
+					LockedByTickets:         acct.Balance.LockedByTickets,
+					VotingAuthority:         acct.Balance.VotingAuthority,
+					UnConfirmed:             acct.Balance.UnConfirmed,
+				},
+				Keys: struct {
+					Internal, External, Imported string
+				}{
+					Internal: strconv.Itoa(int(acct.InternalKeyCount)),
+					External: strconv.Itoa(int(acct.ExternalKeyCount)),
+					Imported: strconv.Itoa(int(acct.ImportedKeyCount)),
+				},
+				HDPath:         wal.hdPrefix() + strconv.Itoa(int(acct.Number)) + "'",
+				CurrentAddress: addr,
+			})
+			acctBalance += acct.TotalBalance
+			spendableBalance += acct.Balance.Spendable
+		}
+		completeTotal += acctBalance
+
+		infos[i] = InfoShort{
+			ID:               wall.ID,
+			Name:             wall.Name,
+			Balance:          dcrutil.Amount(acctBalance).String(),
+			SpendableBalance: spendableBalance,
+			Accounts:         accts,
+			BestBlockHeight:  wall.GetBestBlock(),
+			BlockTimestamp:   wall.GetBestBlockTimeStamp(),
+			DaysBehind:       fmt.Sprintf("%s behind", calculateDaysBehind(wall.GetBestBlockTimeStamp())),
+			Status:           walletSyncStatus(wall.IsWaiting(), wall.GetBestBlock(), wal.OverallBlockHeight),
+			Seed:             wall.EncryptedSeed,
+			IsWatchingOnly:   wall.IsWatchingOnlyWallet(),
+		}
+		i++
+	}
 
-		best := wal.multi.GetBestBlock()
+	best := wal.multi.GetBestBlock()
 
-		if best == nil {
-			if len(wallets) == 0 {
-				wal.Send <- ResponseResp(MultiWalletInfo{})
-				return
-			}
-			resp.Err = InternalWalletError{
-				Message: "Could not get load best block",
-			}
-			wal.Send <- resp
-			return
+	if best == nil {
+		if len(wallets) == 0 {
+			return &MultiWalletInfo{}, nil
 		}
+		return nil, InternalWalletError{
+			Message: "Could not get load best block",
+		}
+	}
+
+	lastSyncTime := int64(time.Since(time.Unix(best.Timestamp, 0)).Seconds())
+	return &MultiWalletInfo{
+		LoadedWallets:   len(wallets),
+		TotalBalance:    dcrutil.Amount(completeTotal).String(),
+		TotalBalanceRaw: GetRawBalance(completeTotal, 0),
+		BestBlockHeight: best.Height,
+		BestBlockTime:   best.Timestamp,
+		LastSyncTime:    SecondsToDays(lastSyncTime),
+		Wallets:         infos,
+		Synced:          wal.multi.IsSynced(),
+		Syncing:         wal.multi.IsSyncing(),
+	}, nil
+}
 
-		lastSyncTime := int64(time.Since(time.Unix(best.Timestamp, 0)).Seconds())
-		resp.Resp = MultiWalletInfo{
-			LoadedWallets:   len(wallets),
-			TotalBalance:    dcrutil.Amount(completeTotal).String(),
-			TotalBalanceRaw: GetRawBalance(completeTotal, 0),
-			BestBlockHeight: best.Height,
-			BestBlockTime:   best.Timestamp,
-			LastSyncTime:    SecondsToDays(lastSyncTime),
-			Wallets:         infos,
-			Synced:          wal.multi.IsSynced(),
-			Syncing:         wal.multi.IsSyncing(),
+// GetMultiWalletInfo gets bulk information about the loaded wallets.
+// Information regarding transactions is collected with respect to wal.confirms as the
+// number of required confirmations for said transactions.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) GetMultiWalletInfo() RequestID {
+	req := wal.newRequest(OpGetMultiWalletInfo)
+	go func() {
+		info, err := wal.GetMultiWalletInfoCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
 		}
-		wal.Send <- resp
+		wal.Send <- req.response(*info)
 	}()
+	return req.ID
 }
 
 func (wal *Wallet) GetMultiWallet() *dcrlibwallet.MultiWallet {
 	return wal.multi
 }
 
-func (wal *Wallet) SignMessage(walletID int, passphrase []byte, address, message string, errChan chan error) {
-	go func() {
-		var resp Response
+// SignMessageCtx signs message with the private key of address.
+func (wal *Wallet) SignMessageCtx(ctx context.Context, walletID int, passphrase []byte, address, message string) (*Signature, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return nil, ErrIDNotExist
+	}
+
+	signedMessageBytes, err := wall.SignMessage(passphrase, address, message)
+	if err != nil {
+		return nil, err
+	}
 
-		wall := wal.multi.WalletWithID(walletID)
-		if wall == nil {
-			resp.Err = ErrIDNotExist
-			wal.Send <- resp
+	return &Signature{
+		Signature: base64.StdEncoding.EncodeToString(signedMessageBytes),
+	}, nil
+}
+
+// SignMessage signs message with the private key of address.
+// It is non-blocking and sends its result to wal.Send. Signing errors are sent to errChan.
+func (wal *Wallet) SignMessage(walletID int, passphrase []byte, address, message string, errChan chan error) RequestID {
+	req := wal.newRequest(OpSignMessage)
+	go func() {
+		sig, err := wal.SignMessageCtx(context.Background(), walletID, passphrase, address, message)
+		if err == ErrIDNotExist {
+			wal.Send <- req.error(err)
 			return
 		}
-
-		signedMessageBytes, err := wall.SignMessage(passphrase, address, message)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			wal.Send <- resp
+			sendErr(errChan, err)
+			wal.Send <- req.response(nil)
 			return
 		}
+		wal.Send <- req.response(sig)
+	}()
+	return req.ID
+}
 
-		resp.Resp = &Signature{
-			Signature: base64.StdEncoding.EncodeToString(signedMessageBytes),
-		}
+// RenameWalletCtx renames the wallet identified by walletID.
+func (wal *Wallet) RenameWalletCtx(ctx context.Context, walletID int, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		wal.Send <- resp
-	}()
+	err := wal.multi.RenameWallet(walletID, name)
+	if err != nil {
+		return MultiWalletError{
+			Message: "Could not rename wallet",
+			Err:     err,
+		}
+	}
+	return nil
 }
 
 // RenameWallet renames the wallet identified by walletID.
-func (wal *Wallet) RenameWallet(walletID int, name string, errChan chan error) {
+func (wal *Wallet) RenameWallet(walletID int, name string, errChan chan error) RequestID {
+	req := wal.newRequest(OpRenameWallet)
 	go func() {
-		var resp Response
-		err := wal.multi.RenameWallet(walletID, name)
+		err := wal.RenameWalletCtx(context.Background(), walletID, name)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not rename wallet",
-				Err:     err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-		resp.Resp = Renamed{
+		wal.Send <- req.response(Renamed{
 			ID: walletID,
-		}
-		wal.Send <- resp
+		})
 	}()
+	return req.ID
 }
 
 // ImportWatchOnlyWallet imports a watch only wallet with the given parameters.
-// It is non-blocking and sends its result or any error to wal.Send.
+// The extended public key is saved to build unsigned transactions from the
+// wallet.
 func (wal *Wallet) ImportWatchOnlyWallet(name, extendedPublicKey string) error {
 	var g errgroup.Group
 	g.Go(func() error {
-		_, err := wal.multi.CreateWatchOnlyWallet(name, extendedPublicKey)
+		w, err := wal.multi.CreateWatchOnlyWallet(name, extendedPublicKey)
 		if err != nil {
 			return fmt.Errorf("error importing watch only wallet: %s", err.Error())
 		}
+		w.SaveUserConfigValue(accountXpubConfigKey, extendedPublicKey)
 		return nil
 	})
 
@@ -513,116 +638,158 @@ func (wal *Wallet) ImportWatchOnlyWallet(name, extendedPublicKey string) error {
 	return nil
 }
 
+// ChangeWalletPassphraseCtx changes the spending passphrase of the wallet identified by walletID.
+func (wal *Wallet) ChangeWalletPassphraseCtx(ctx context.Context, walletID int, oldPrivatePassphrase, newPrivatePassphrase string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := wal.multi.ChangePrivatePassphraseForWallet(walletID, []byte(oldPrivatePassphrase), []byte(newPrivatePassphrase), dcrlibwallet.PassphraseTypePass)
+	if err != nil {
+		return InternalWalletError{
+			Message:  "Could not change password",
+			Affected: []int{walletID},
+			Err:      err,
+		}
+	}
+	return nil
+}
+
 // ChangeWalletPassphrase changes the spending passphrase of the wallet identified by walletID.
-func (wal *Wallet) ChangeWalletPassphrase(walletID int, oldPrivatePassphrase, newPrivatePassphrase string, errChan chan error) {
+func (wal *Wallet) ChangeWalletPassphrase(walletID int, oldPrivatePassphrase, newPrivatePassphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpChangeWalletPassphrase)
 	go func() {
-		var resp Response
-		err := wal.multi.ChangePrivatePassphraseForWallet(walletID, []byte(oldPrivatePassphrase), []byte(newPrivatePassphrase), dcrlibwallet.PassphraseTypePass)
+		err := wal.ChangeWalletPassphraseCtx(context.Background(), walletID, oldPrivatePassphrase, newPrivatePassphrase)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not change password",
-				Affected: []int{walletID},
-				Err:      err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-
-		resp.Resp = &ChangePassword{
+		wal.Send <- req.response(&ChangePassword{
 			ID: walletID,
-		}
+		})
 	}()
+	return req.ID
 }
 
-func (wal *Wallet) OpenWallets(passphrase string, errChan chan error) {
-	go func() {
-		var resp Response
-		err := wal.multi.OpenWallets([]byte(passphrase))
-		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not open wallets",
-				Err:     err,
-			})
-			return
-		}
+// OpenWalletsCtx opens all wallets using the startup passphrase.
+func (wal *Wallet) OpenWalletsCtx(ctx context.Context, passphrase string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		resp.Resp = OpenWallet{}
-		wal.Send <- resp
-	}()
+	err := wal.multi.OpenWallets([]byte(passphrase))
+	if err != nil {
+		return MultiWalletError{
+			Message: "Could not open wallets",
+			Err:     err,
+		}
+	}
+	return nil
 }
 
-func (wal *Wallet) SetStartupPassphrase(passphrase string, errChan chan error) {
+func (wal *Wallet) OpenWallets(passphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpOpenWallets)
 	go func() {
-		var resp Response
-		err := wal.multi.SetStartupPassphrase([]byte(passphrase), dcrlibwallet.PassphraseTypePass)
+		err := wal.OpenWalletsCtx(context.Background(), passphrase)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not set up startup passphrase",
-				Err:     err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-		resp.Resp = &StartupPassphrase{
-			Msg: "Startup password set",
-		}
-		wal.Send <- resp
+		wal.Send <- req.response(OpenWallet{})
 	}()
+	return req.ID
 }
 
-func (wal *Wallet) ChangeStartupPassphrase(oldPrivatePassphrase, newPrivatePassphrase string, errChan chan error) {
-	go func() {
-		var resp Response
-		err := wal.multi.ChangeStartupPassphrase([]byte(oldPrivatePassphrase), []byte(newPrivatePassphrase), dcrlibwallet.PassphraseTypePass)
-		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not change startup passphrase",
-				Err:     err,
-			})
-			return
-		}
+// SetStartupPassphraseCtx protects the wallets with a startup passphrase.
+func (wal *Wallet) SetStartupPassphraseCtx(ctx context.Context, passphrase string) (*StartupPassphrase, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-		resp.Resp = &StartupPassphrase{
-			Msg: "Startup password changed",
+	err := wal.multi.SetStartupPassphrase([]byte(passphrase), dcrlibwallet.PassphraseTypePass)
+	if err != nil {
+		return nil, MultiWalletError{
+			Message: "Could not set up startup passphrase",
+			Err:     err,
 		}
-		wal.Send <- resp
-	}()
+	}
+	return &StartupPassphrase{
+		Msg: "Startup password set",
+	}, nil
 }
 
-func (wal *Wallet) RemoveStartupPassphrase(passphrase string, errChan chan error) {
+func (wal *Wallet) SetStartupPassphrase(passphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpSetStartupPassphrase)
 	go func() {
-		var resp Response
-		err := wal.multi.RemoveStartupPassphrase([]byte(passphrase))
-		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not remove startup passphrase",
-				Err:     err,
-			})
-			return
+		msg, err := wal.SetStartupPassphraseCtx(context.Background(), passphrase)
+		wal.sendStartupPassphrase(req, msg, err, errChan)
+	}()
+	return req.ID
+}
+
+// ChangeStartupPassphraseCtx replaces the startup passphrase.
+func (wal *Wallet) ChangeStartupPassphraseCtx(ctx context.Context, oldPrivatePassphrase, newPrivatePassphrase string) (*StartupPassphrase, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	err := wal.multi.ChangeStartupPassphrase([]byte(oldPrivatePassphrase), []byte(newPrivatePassphrase), dcrlibwallet.PassphraseTypePass)
+	if err != nil {
+		return nil, MultiWalletError{
+			Message: "Could not change startup passphrase",
+			Err:     err,
 		}
-		resp.Resp = &StartupPassphrase{
-			Msg: "Startup password removed",
+	}
+	return &StartupPassphrase{
+		Msg: "Startup password changed",
+	}, nil
+}
+
+func (wal *Wallet) ChangeStartupPassphrase(oldPrivatePassphrase, newPrivatePassphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpChangeStartupPassphrase)
+	go func() {
+		msg, err := wal.ChangeStartupPassphraseCtx(context.Background(), oldPrivatePassphrase, newPrivatePassphrase)
+		wal.sendStartupPassphrase(req, msg, err, errChan)
+	}()
+	return req.ID
+}
+
+// RemoveStartupPassphraseCtx removes the startup passphrase.
+func (wal *Wallet) RemoveStartupPassphraseCtx(ctx context.Context, passphrase string) (*StartupPassphrase, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	err := wal.multi.RemoveStartupPassphrase([]byte(passphrase))
+	if err != nil {
+		return nil, MultiWalletError{
+			Message: "Could not remove startup passphrase",
+			Err:     err,
 		}
-		wal.Send <- resp
+	}
+	return &StartupPassphrase{
+		Msg: "Startup password removed",
+	}, nil
+}
+
+func (wal *Wallet) RemoveStartupPassphrase(passphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpRemoveStartupPassphrase)
+	go func() {
+		msg, err := wal.RemoveStartupPassphraseCtx(context.Background(), passphrase)
+		wal.sendStartupPassphrase(req, msg, err, errChan)
 	}()
+	return req.ID
+}
+
+func (wal *Wallet) sendStartupPassphrase(req request, msg *StartupPassphrase, err error, errChan chan error) {
+	if err != nil {
+		sendErr(errChan, cause(err))
+		wal.Send <- req.error(err)
+		return
+	}
+	wal.Send <- req.response(msg)
 }
 
 // IsStartupSecuritySet checks if start up password is set
@@ -630,57 +797,71 @@ func (wal *Wallet) IsStartupSecuritySet() bool {
 	return wal.multi.IsStartupSecuritySet()
 }
 
-// RenameAccount renames the acct of wallet with id walletID.
-func (wal *Wallet) RenameAccount(walletID int, acct int32, name string, errChan chan<- error) {
-	go func() {
-		var resp Response
-		wall := wal.multi.WalletWithID(walletID)
-		if wall == nil {
-			go func() {
-				errChan <- ErrIDNotExist
-			}()
-			resp.Err = ErrIDNotExist
-			wal.Send <- Response{
-				Resp: UpdatedAccount{},
-				Err:  ErrIDNotExist,
-			}
-			return
+// RenameAccountCtx renames the acct of wallet with id walletID.
+func (wal *Wallet) RenameAccountCtx(ctx context.Context, walletID int, acct int32, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return ErrIDNotExist
+	}
+
+	err := wall.RenameAccount(acct, name)
+	if err != nil {
+		return InternalWalletError{
+			Message:  "Could not rename account",
+			Affected: []int{walletID},
+			Err:      err,
 		}
+	}
+	return nil
+}
 
-		err := wall.RenameAccount(acct, name)
+// RenameAccount renames the acct of wallet with id walletID.
+func (wal *Wallet) RenameAccount(walletID int, acct int32, name string, errChan chan<- error) RequestID {
+	req := wal.newRequest(OpRenameAccount)
+	go func() {
+		err := wal.RenameAccountCtx(context.Background(), walletID, acct, name)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not rename account",
-				Affected: []int{walletID},
-				Err:      err,
-			})
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-		resp.Resp = UpdatedAccount{
+		wal.Send <- req.response(UpdatedAccount{
 			ID: acct,
-		}
-		wal.Send <- resp
+		})
 	}()
+	return req.ID
 }
 
-func (wal *Wallet) GetAllProposals() {
-	var resp Response
+// GetAllProposalsCtx returns all proposals saved by the politeia client.
+func (wal *Wallet) GetAllProposalsCtx(ctx context.Context) (*Proposals, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	proposals, err := wal.multi.Politeia.GetProposalsRaw(dcrlibwallet.ProposalCategoryAll, 0, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Proposals{
+		Proposals: proposals,
+	}, nil
+}
+
+func (wal *Wallet) GetAllProposals() RequestID {
+	req := wal.newRequest(OpGetAllProposals)
 	go func() {
-		proposals, err := wal.multi.Politeia.GetProposalsRaw(dcrlibwallet.ProposalCategoryAll, 0, 0, true)
+		proposals, err := wal.GetAllProposalsCtx(context.Background())
 		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
+			wal.Send <- req.error(err)
 			return
 		}
-		resp.Resp = &Proposals{
-			Proposals: proposals,
-		}
-		wal.Send <- resp
+		wal.Send <- req.response(proposals)
 	}()
+	return req.ID
 }
 
 func (wal *Wallet) FetchProposalDescription(token string) (string, error) {
@@ -816,44 +997,55 @@ func divMod(numerator, denominator int64) (quotient, remainder int64) {
 	return
 }
 
+// AllUnspentOutputsCtx gets all unspent outputs by walletID and acct
+func (wal *Wallet) AllUnspentOutputsCtx(ctx context.Context, walletID int, acct int32) (*UnspentOutputs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return nil, ErrIDNotExist
+	}
+	utxos, err := wall.UnspentOutputs(acct)
+	if err != nil {
+		return nil, InternalWalletError{
+			Message:  "Could not get unspent outputs",
+			Affected: []int{walletID, int(acct)},
+			Err:      err,
+		}
+	}
+
+	labels, frozen := readUTXOLabels(wall), readFrozenUTXOs(wall)
+	var list []*UnspentOutput
+	for _, utxo := range utxos {
+		item := UnspentOutput{
+			UTXO:     *utxo,
+			Amount:   dcrutil.Amount(utxo.Amount).String(),
+			DateTime: dcrlibwallet.ExtractDateOrTime(utxo.ReceiveTime),
+			Label:    labels[utxo.OutputKey],
+			Frozen:   frozen[utxo.OutputKey],
+		}
+		list = append(list, &item)
+	}
+	return &UnspentOutputs{
+		List: list,
+	}, nil
+}
+
 // AllUnspentOutputs get all unspent outputs by walletID and acct
-func (wal *Wallet) AllUnspentOutputs(walletID int, acct int32) {
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) AllUnspentOutputs(walletID int, acct int32) RequestID {
+	req := wal.newRequest(OpAllUnspentOutputs)
 	go func() {
-		var resp Response
-		wall := wal.multi.WalletWithID(walletID)
-		if wall == nil {
-			resp.Err = ErrIDNotExist
-			wal.Send <- Response{
-				Resp: UnspentOutputs{},
-				Err:  ErrIDNotExist,
-			}
-			return
-		}
-		utxos, err := wall.UnspentOutputs(acct)
+		utxos, err := wal.AllUnspentOutputsCtx(context.Background(), walletID, acct)
 		if err != nil {
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not get unspent outputs",
-				Affected: []int{walletID, int(acct)},
-				Err:      err,
-			})
+			wal.Send <- req.error(err)
 			return
 		}
-
-		var list []*UnspentOutput
-		for _, utxo := range utxos {
-			item := UnspentOutput{
-				UTXO:     *utxo,
-				Amount:   dcrutil.Amount(utxo.Amount).String(),
-				DateTime: dcrlibwallet.ExtractDateOrTime(utxo.ReceiveTime),
-			}
-			list = append(list, &item)
-		}
-		resp.Resp = &UnspentOutputs{
-			List: list,
-		}
-		wal.Send <- resp
+		wal.Send <- req.response(utxos)
 	}()
+	return req.ID
 }
 
 // IsAccountMixerConfigSet check the wallet have account mixer config set
@@ -865,89 +1057,100 @@ func (wal *Wallet) IsAccountMixerConfigSet(walletID int) bool {
 	return wall.ReadBoolConfigValueForKey(dcrlibwallet.AccountMixerConfigSet, false)
 }
 
-// SetupAccountMixer setup account mixer with the given parameters.
-// It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) SetupAccountMixer(walletID int, walletPassphrase string, errChan chan error) {
-	go func() {
-		var resp Response
-
-		wall := wal.multi.WalletWithID(walletID)
-		if wall == nil {
-			resp.Err = ErrIDNotExist
-			wal.Send <- Response{
-				Resp: SetupAccountMixer{},
-				Err:  ErrIDNotExist,
-			}
-			return
-		}
+// SetupAccountMixerCtx setup account mixer with the given parameters.
+// The mixed and unmixed accounts are created if they do not exist.
+func (wal *Wallet) SetupAccountMixerCtx(ctx context.Context, walletID int, walletPassphrase string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		var err error
-		var mixedAcctNumber int32
-		var unmixedAcctNumber int32
-		mixedAcct := "mixed"
-		unmixedAcct := "unmixed"
-
-		sendErr := func(err error) {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(InternalWalletError{
-				Message:  "Could not set account mixer",
-				Err:      err,
-				Affected: []int{walletID},
-			})
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return ErrIDNotExist
+	}
+
+	var err error
+	var mixedAcctNumber int32
+	var unmixedAcctNumber int32
+	mixedAcct := "mixed"
+	unmixedAcct := "unmixed"
+
+	mixerErr := func(err error) error {
+		return InternalWalletError{
+			Message:  "Could not set account mixer",
+			Err:      err,
+			Affected: []int{walletID},
 		}
+	}
 
-		if !wall.HasAccount(mixedAcct) {
-			mixedAcctNumber, err = wall.CreateNewAccount(mixedAcct, []byte(walletPassphrase))
-			if err != nil {
-				sendErr(err)
-				return
-			}
-		} else {
-			mixedAcctNumber, err = wall.AccountNumber(mixedAcct)
-			if err != nil {
-				sendErr(err)
-				return
-			}
+	if !wall.HasAccount(mixedAcct) {
+		mixedAcctNumber, err = wall.CreateNewAccount(mixedAcct, []byte(walletPassphrase))
+		if err != nil {
+			return mixerErr(err)
 		}
+	} else {
+		mixedAcctNumber, err = wall.AccountNumber(mixedAcct)
+		if err != nil {
+			return mixerErr(err)
+		}
+	}
 
-		if !wall.HasAccount(unmixedAcct) {
-			unmixedAcctNumber, err = wall.CreateNewAccount(unmixedAcct, []byte(walletPassphrase))
-			if err != nil {
-				sendErr(err)
-				return
-			}
-		} else {
-			unmixedAcctNumber, err = wall.AccountNumber(unmixedAcct)
-			if err != nil {
-				sendErr(err)
-				return
-			}
+	if !wall.HasAccount(unmixedAcct) {
+		unmixedAcctNumber, err = wall.CreateNewAccount(unmixedAcct, []byte(walletPassphrase))
+		if err != nil {
+			return mixerErr(err)
+		}
+	} else {
+		unmixedAcctNumber, err = wall.AccountNumber(unmixedAcct)
+		if err != nil {
+			return mixerErr(err)
 		}
+	}
 
-		err = wall.SetAccountMixerConfig(mixedAcctNumber, unmixedAcctNumber, walletPassphrase)
+	err = wall.SetAccountMixerConfig(mixedAcctNumber, unmixedAcctNumber, walletPassphrase)
+	if err != nil {
+		return mixerErr(err)
+	}
+	return nil
+}
+
+// SetupAccountMixer setup account mixer with the given parameters.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) SetupAccountMixer(walletID int, walletPassphrase string, errChan chan error) RequestID {
+	req := wal.newRequest(OpSetupAccountMixer)
+	go func() {
+		err := wal.SetupAccountMixerCtx(context.Background(), walletID, walletPassphrase)
+		if err == ErrIDNotExist {
+			wal.Send <- req.error(err)
+			return
+		}
 		if err != nil {
-			sendErr(err)
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
 			return
 		}
-
-		resp.Resp = SetupAccountMixer{}
-		wal.Send <- resp
+		wal.Send <- req.response(SetupAccountMixer{})
 	}()
+	return req.ID
 }
 
-// TicketPrice get ticket price
-func (wal *Wallet) TicketPrice() (int64, string) {
-	pr, err := wal.multi.WalletsIterator().Next().TicketPrice()
+// TicketPrice get ticket price. ErrNoWallets is returned if no wallet is
+// loaded to ask for it.
+func (wal *Wallet) TicketPrice() (int64, string, error) {
+	w := wal.multi.WalletsIterator().Next()
+	if w == nil {
+		return 0, "", ErrNoWallets
+	}
+	pr, err := w.TicketPrice()
 	if err != nil {
-		log.Error(err)
-		return 0, ""
+		return 0, "", err
 	}
-	return pr.TicketPrice, dcrutil.Amount(pr.TicketPrice).String()
+	return pr.TicketPrice, dcrutil.Amount(pr.TicketPrice).String(), nil
 }
 
+// NewVSPD returns a dcrlibwallet client that buys tickets of an account
+// through the VSP at host. ErrVSPPubKeyChanged is returned if the VSP signs
+// with a key that has not been trusted.
 func (wal *Wallet) NewVSPD(host string, walletID int, accountID int32) (*dcrlibwallet.VSP, error) {
 	if host == "" {
 		return nil, fmt.Errorf("Host is required")
@@ -956,183 +1159,201 @@ func (wal *Wallet) NewVSPD(host string, walletID int, accountID int32) (*dcrlibw
 	if wall == nil {
 		return nil, ErrIDNotExist
 	}
+	// dcrlibwallet fetches the key of the VSP itself, so the cached key is
+	// refreshed first to catch a change before the VSP is used.
+	if _, err := wal.trustedVSP(context.Background(), host, true); err != nil {
+		return nil, err
+	}
 	vspd, err := wal.multi.NewVSPClient(host, walletID, uint32(accountID))
 	if err != nil {
 		return nil, fmt.Errorf("Something wrong when creating new VSPD: %v", err)
 	}
+	rememberVSPHost(wall, host)
 	return vspd, nil
 }
 
-// PurchaseTicket buy a ticket with given parameters
-func (wal *Wallet) PurchaseTicket(walletID int, accountID int32, tickets uint32, passphrase []byte, vspd *dcrlibwallet.VSP, errChan chan error) {
-	go func() {
-		var resp Response
-		wall := wal.multi.WalletWithID(walletID)
-		if wall == nil {
-			go func() {
-				errChan <- ErrIDNotExist
-			}()
-			return
-		}
+// PurchaseTicketCtx buys tickets from account with the given parameters
+// through vspd, which must have been created for that account. Accounts with
+// frozen outputs are refused. The VSP is queried with ctx before the
+// purchase begins.
+func (wal *Wallet) PurchaseTicketCtx(ctx context.Context, walletID int, account int32, tickets uint32, passphrase []byte, vspd *dcrlibwallet.VSP) error {
+	wall := wal.multi.WalletWithID(walletID)
+	if wall == nil {
+		return ErrIDNotExist
+	}
+	if err := checkNoFrozenUTXOs(wall, account); err != nil {
+		return err
+	}
 
-		_, err := vspd.GetInfo(context.Background())
-		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			return
-		}
+	_, err := vspd.GetInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return vspd.PurchaseTickets(int32(tickets), wal.multi.GetBestBlock().Height+256, passphrase)
+}
 
-		err = vspd.PurchaseTickets(int32(tickets), wal.multi.GetBestBlock().Height+256, passphrase)
+// PurchaseTicket buy a ticket with given parameters
+func (wal *Wallet) PurchaseTicket(walletID int, accountID int32, tickets uint32, passphrase []byte, vspd *dcrlibwallet.VSP, errChan chan error) RequestID {
+	req := wal.newRequest(OpPurchaseTicket)
+	go func() {
+		err := wal.PurchaseTicketCtx(context.Background(), walletID, accountID, tickets, passphrase, vspd)
 		if err != nil {
-			go func() {
-				errChan <- err
-			}()
+			sendErr(errChan, err)
 			return
 		}
 
-		go func() {
-			errChan <- nil
-		}()
-
-		resp.Resp = &TicketPurchase{}
-		wal.Send <- resp
+		sendErr(errChan, nil)
+		wal.Send <- req.response(&TicketPurchase{})
 	}()
+	return req.ID
 }
 
-// GetAllTickets collects a per-wallet slice of tickets fitting the parameters.
-// It is non-blocking and sends its result or any error to wal.Send.
-func (wal *Wallet) GetAllTickets() {
-	go func() {
-		var resp Response
-		wallets, err := wal.wallets()
-		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
-			return
-		}
+// GetAllTicketsCtx collects a per-wallet slice of tickets fitting the parameters.
+// It blocks until all wallets have been read or ctx is canceled.
+func (wal *Wallet) GetAllTicketsCtx(ctx context.Context) (*Tickets, error) {
+	wallets, err := wal.wallets()
+	if err != nil {
+		return nil, err
+	}
 
-		var liveRecentTickets []Ticket
-		var recentActivity []Ticket
-
-		tickets := make(map[int][]Ticket)
-		unconfirmedTickets := make(map[int][]UnconfirmedPurchase)
-
-		stackingRecordCounter := []struct {
-			Status string
-			Count  int
-		}{
-			{"UNMINED", 0},
-			{"IMMATURE", 0},
-			{"LIVE", 0},
-			{"VOTED", 0},
-			{"MISSED", 0},
-			{"EXPIRED", 0},
-			{"REVOKED", 0},
-		}
-
-		liveCounter := []struct {
-			Status string
-			Count  int
-		}{
-			{"UNMINED", 0},
-			{"IMMATURE", 0},
-			{"LIVE", 0},
-		}
-
-		for _, wall := range wallets {
-			ticketsInfo, err := wall.GetTicketsForBlockHeightRange(0, wall.GetBestBlock(), math.MaxInt32)
-			if err != nil {
-				resp.Err = err
-				wal.Send <- resp
-				return
-			}
+	var liveRecentTickets []Ticket
+	var recentActivity []Ticket
+
+	tickets := make(map[int][]Ticket)
+	unconfirmedTickets := make(map[int][]UnconfirmedPurchase)
+
+	stackingRecordCounter := []struct {
+		Status string
+		Count  int
+	}{
+		{"UNMINED", 0},
+		{"IMMATURE", 0},
+		{"LIVE", 0},
+		{"VOTED", 0},
+		{"MISSED", 0},
+		{"EXPIRED", 0},
+		{"REVOKED", 0},
+	}
 
-			for _, tinfo := range ticketsInfo {
-				if tinfo.Status == "UNKNOWN" {
-					continue
-				}
+	liveCounter := []struct {
+		Status string
+		Count  int
+	}{
+		{"UNMINED", 0},
+		{"IMMATURE", 0},
+		{"LIVE", 0},
+	}
 
-				var amount dcrutil.Amount
-				for _, output := range tinfo.Ticket.MyOutputs {
-					amount += output.Amount
-				}
-				info := Ticket{
-					Info:       *tinfo,
-					DateTime:   time.Unix(tinfo.Ticket.Timestamp, 0).Format("Jan 2, 2006 03:04:05 PM"),
-					MonthDay:   time.Unix(tinfo.Ticket.Timestamp, 0).Format("Jan 2"),
-					DaysBehind: calculateDaysBehind(tinfo.Ticket.Timestamp),
-					Amount:     amount.String(),
-					Fee:        tinfo.Ticket.Fee.String(),
-					WalletName: wall.Name,
-				}
-				tickets[wall.ID] = append(tickets[wall.ID], info)
+	for _, wall := range wallets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-				for i := range liveCounter {
-					if liveCounter[i].Status == tinfo.Status {
-						liveCounter[i].Count++
-					}
-				}
+		ticketsInfo, err := wall.GetTicketsForBlockHeightRange(0, wall.GetBestBlock(), math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
 
-				if tinfo.Status == "UNMINED" || tinfo.Status == "IMMATURE" || tinfo.Status == "LIVE" {
-					liveRecentTickets = append(liveRecentTickets, info)
-				}
+		for _, tinfo := range ticketsInfo {
+			if tinfo.Status == "UNKNOWN" {
+				continue
+			}
 
-				recentActivity = append(recentActivity, info)
+			var amount dcrutil.Amount
+			for _, output := range tinfo.Ticket.MyOutputs {
+				amount += output.Amount
+			}
+			info := Ticket{
+				Info:       *tinfo,
+				DateTime:   time.Unix(tinfo.Ticket.Timestamp, 0).Format("Jan 2, 2006 03:04:05 PM"),
+				MonthDay:   time.Unix(tinfo.Ticket.Timestamp, 0).Format("Jan 2"),
+				DaysBehind: calculateDaysBehind(tinfo.Ticket.Timestamp),
+				Amount:     amount.String(),
+				Fee:        tinfo.Ticket.Fee.String(),
+				WalletName: wall.Name,
+			}
+			tickets[wall.ID] = append(tickets[wall.ID], info)
 
-				for i := range stackingRecordCounter {
-					if stackingRecordCounter[i].Status == tinfo.Status {
-						stackingRecordCounter[i].Count++
-					}
+			for i := range liveCounter {
+				if liveCounter[i].Status == tinfo.Status {
+					liveCounter[i].Count++
 				}
 			}
 
-			sort.SliceStable(tickets[wall.ID], func(i, j int) bool {
-				backTime := time.Unix(tickets[wall.ID][j].Info.Ticket.Timestamp, 0)
-				frontTime := time.Unix(tickets[wall.ID][i].Info.Ticket.Timestamp, 0)
-				return backTime.Before(frontTime)
-			})
+			if tinfo.Status == "UNMINED" || tinfo.Status == "IMMATURE" || tinfo.Status == "LIVE" {
+				liveRecentTickets = append(liveRecentTickets, info)
+			}
 
-			unconfirmedTicketPurchases, err := getUnconfirmedPurchases(wall, tickets[wall.ID])
-			if err != nil {
-				resp.Err = err
-				wal.Send <- resp
-				return
+			recentActivity = append(recentActivity, info)
+
+			for i := range stackingRecordCounter {
+				if stackingRecordCounter[i].Status == tinfo.Status {
+					stackingRecordCounter[i].Count++
+				}
 			}
-			unconfirmedTickets[wall.ID] = unconfirmedTicketPurchases
 		}
 
-		sort.SliceStable(liveRecentTickets, func(i, j int) bool {
-			backTime := time.Unix(liveRecentTickets[j].Info.Ticket.Timestamp, 0)
-			frontTime := time.Unix(liveRecentTickets[i].Info.Ticket.Timestamp, 0)
+		sort.SliceStable(tickets[wall.ID], func(i, j int) bool {
+			backTime := time.Unix(tickets[wall.ID][j].Info.Ticket.Timestamp, 0)
+			frontTime := time.Unix(tickets[wall.ID][i].Info.Ticket.Timestamp, 0)
 			return backTime.Before(frontTime)
 		})
 
-		recentLimit := 5
-		if len(liveRecentTickets) > recentLimit {
-			liveRecentTickets = liveRecentTickets[:recentLimit]
+		unconfirmedTicketPurchases, err := getUnconfirmedPurchases(wall, tickets[wall.ID])
+		if err != nil {
+			return nil, err
 		}
+		unconfirmedTickets[wall.ID] = unconfirmedTicketPurchases
+	}
 
-		sort.SliceStable(recentActivity, func(i, j int) bool {
-			backTime := time.Unix(recentActivity[j].Info.Ticket.Timestamp, 0)
-			frontTime := time.Unix(recentActivity[i].Info.Ticket.Timestamp, 0)
-			return backTime.Before(frontTime)
-		})
+	sort.SliceStable(liveRecentTickets, func(i, j int) bool {
+		backTime := time.Unix(liveRecentTickets[j].Info.Ticket.Timestamp, 0)
+		frontTime := time.Unix(liveRecentTickets[i].Info.Ticket.Timestamp, 0)
+		return backTime.Before(frontTime)
+	})
 
-		if len(recentActivity) > recentLimit {
-			recentActivity = recentActivity[:recentLimit]
-		}
+	recentLimit := 5
+	if len(liveRecentTickets) > recentLimit {
+		liveRecentTickets = liveRecentTickets[:recentLimit]
+	}
+
+	sort.SliceStable(recentActivity, func(i, j int) bool {
+		backTime := time.Unix(recentActivity[j].Info.Ticket.Timestamp, 0)
+		frontTime := time.Unix(recentActivity[i].Info.Ticket.Timestamp, 0)
+		return backTime.Before(frontTime)
+	})
+
+	if len(recentActivity) > recentLimit {
+		recentActivity = recentActivity[:recentLimit]
+	}
+
+	return &Tickets{
+		Confirmed:             tickets,
+		Unconfirmed:           unconfirmedTickets,
+		RecentActivity:        recentActivity,
+		StackingRecordCounter: stackingRecordCounter,
+		LiveRecent:            liveRecentTickets,
+		LiveCounter:           liveCounter,
+	}, nil
+}
 
-		resp.Resp = &Tickets{
-			Confirmed:             tickets,
-			Unconfirmed:           unconfirmedTickets,
-			RecentActivity:        recentActivity,
-			StackingRecordCounter: stackingRecordCounter,
-			LiveRecent:            liveRecentTickets,
-			LiveCounter:           liveCounter,
+// GetAllTickets collects a per-wallet slice of tickets fitting the parameters.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) GetAllTickets() RequestID {
+	req := wal.newRequest(OpGetAllTickets)
+	go func() {
+		tickets, err := wal.GetAllTicketsCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
 		}
-		wal.Send <- resp
+		wal.Send <- req.response(tickets)
 	}()
+	return req.ID
 }
 
 func getUnconfirmedPurchases(wall dcrlibwallet.Wallet, tickets []Ticket) (unconfirmed []UnconfirmedPurchase, err error) {
@@ -1220,92 +1441,102 @@ func (wal *Wallet) ReadMixerConfigValueForKey(key string, walletID int) int32 {
 	return 0
 }
 
-func (wal *Wallet) AddVSP(host string, errChan chan error) {
-	// wal.multi.DeleteUserConfigValueForKey(dcrlibwallet.VSPHostConfigKey)
-	go func() {
-		var resp Response
-		var valueOut struct {
-			Remember string
-			List     []string
-		}
-
-		wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &valueOut)
+// AddVSPCtx validates the VSP at host and saves it to the list of known VSPs.
+func (wal *Wallet) AddVSPCtx(ctx context.Context, host string) (*VSPInfo, error) {
+	var valueOut vspHostList
+	wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &valueOut)
 
-		for _, v := range valueOut.List {
-			if v == host {
-				go func() {
-					errChan <- fmt.Errorf("Existing host %s", host)
-				}()
-				return
-			}
+	for _, v := range valueOut.List {
+		if v == host {
+			return nil, fmt.Errorf("Existing host %s", host)
 		}
+	}
 
-		info, err := getVSPInfo(host)
-		if err != nil {
-			go func() {
-				errChan <- err
-			}()
-			resp.Err = err
-			wal.Send <- ResponseError(MultiWalletError{
-				Message: "Could not create vsp",
-				Err:     err,
-			})
-			return
+	record, err := wal.fetchVSP(ctx, host)
+	if err != nil {
+		return nil, MultiWalletError{
+			Message: "Could not create vsp",
+			Err:     err,
 		}
+	}
 
-		if info.Network != wal.Net {
-			go func() {
-				errChan <- fmt.Errorf("Invalid net %s", info.Network)
-			}()
-			return
-		}
+	info := record.Info
+	if info.Network != wal.Net {
+		return nil, fmt.Errorf("Invalid net %s", info.Network)
+	}
 
-		valueOut.List = append(valueOut.List, host)
-		wal.multi.SaveUserConfigValue(dcrlibwallet.VSPHostConfigKey, valueOut)
-		resp.Resp = &VSPInfo{
-			Host: host,
-			Info: info,
-		}
-		wal.Send <- resp
-	}()
+	valueOut.List = append(valueOut.List, host)
+	wal.multi.SaveUserConfigValue(dcrlibwallet.VSPHostConfigKey, valueOut)
+	return &VSPInfo{
+		Host: host,
+		Info: info,
+	}, nil
 }
 
-func (wal *Wallet) GetAllVSP() {
+func (wal *Wallet) AddVSP(host string, errChan chan error) RequestID {
+	req := wal.newRequest(OpAddVSP)
+	// wal.multi.DeleteUserConfigValueForKey(dcrlibwallet.VSPHostConfigKey)
 	go func() {
-		var valueOut struct {
-			Remember string
-			List     []string
+		info, err := wal.AddVSPCtx(context.Background(), host)
+		if err != nil {
+			sendErr(errChan, cause(err))
+			if _, ok := err.(MultiWalletError); ok {
+				wal.Send <- req.error(err)
+			}
+			return
 		}
+		wal.Send <- req.response(info)
+	}()
+	return req.ID
+}
 
-		wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &valueOut)
-		var loadedVSP []VSPInfo
-
-		for _, host := range valueOut.List {
-			v, err := getVSPInfo(host)
-			if err == nil {
-				loadedVSP = append(loadedVSP, VSPInfo{
-					Host: host,
-					Info: v,
-				})
-			}
+// GetAllVSPCtx returns the info of the saved VSPs and the VSPs listed by
+// api.decred.org for the wallet's network. The info of saved VSPs is only
+// fetched again once it is stale. VSPs that cannot be reached are skipped.
+func (wal *Wallet) GetAllVSPCtx(ctx context.Context) (*VSP, error) {
+	var valueOut vspHostList
+	wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &valueOut)
+	var loadedVSP []VSPInfo
+
+	for _, host := range valueOut.List {
+		record, err := wal.cachedVSP(ctx, host)
+		if err == nil {
+			loadedVSP = append(loadedVSP, VSPInfo{
+				Host: host,
+				Info: record.Info,
+			})
 		}
+	}
 
-		l, _ := getInitVSPInfo("https://api.decred.org/?c=vsp")
-		for h, v := range l {
-			if strings.Contains(wal.Net, v.Network) {
-				loadedVSP = append(loadedVSP, VSPInfo{
-					Host: fmt.Sprintf("https://%s", h),
-					Info: v,
-				})
-			}
+	l, _ := getInitVSPInfo(ctx, "https://api.decred.org/?c=vsp")
+	for h, v := range l {
+		if strings.Contains(wal.Net, v.Network) {
+			loadedVSP = append(loadedVSP, VSPInfo{
+				Host: fmt.Sprintf("https://%s", h),
+				Info: v,
+			})
 		}
+	}
 
-		var resp Response
-		resp.Resp = &VSP{
-			List: loadedVSP,
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &VSP{
+		List: loadedVSP,
+	}, nil
+}
+
+func (wal *Wallet) GetAllVSP() RequestID {
+	req := wal.newRequest(OpGetAllVSP)
+	go func() {
+		vsps, err := wal.GetAllVSPCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
 		}
-		wal.Send <- resp
+		wal.Send <- req.response(vsps)
 	}()
+	return req.ID
 }
 
 func (wal *Wallet) RememberVSP(host string) {
@@ -1328,9 +1559,14 @@ func (wal *Wallet) GetRememberVSP() string {
 }
 
 // getVSPInfo returns the information of the specified VSP base URL
-func getVSPInfo(url string) (*dcrlibwallet.VspInfoResponse, error) {
+func getVSPInfo(ctx context.Context, url string) (*dcrlibwallet.VspInfoResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/api/v3/vspinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
 	rq := new(http.Client)
-	resp, err := rq.Get((url + "/api/v3/vspinfo"))
+	resp, err := rq.Do(req)
 
 	if err != nil {
 		return nil, err
@@ -1360,9 +1596,14 @@ func getVSPInfo(url string) (*dcrlibwallet.VspInfoResponse, error) {
 }
 
 // getInitVSPInfo returns the list information of the VSP
-func getInitVSPInfo(url string) (map[string]*dcrlibwallet.VspInfoResponse, error) {
+func getInitVSPInfo(ctx context.Context, url string) (map[string]*dcrlibwallet.VspInfoResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	rq := new(http.Client)
-	resp, err := rq.Get((url))
+	resp, err := rq.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// DcrdataClient publishes transactions through the Insight API of a dcrdata
+// instance. dcrlibwallet only publishes transactions it signs itself, so
+// transactions signed offline are sent to the network through dcrdata.
+type DcrdataClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewDcrdataClient returns a client for the dcrdata instance at baseURL, e.g.
+// "https://explorer.dcrdata.org". A nil client uses a default client.
+func NewDcrdataClient(baseURL string, client *http.Client) *DcrdataClient {
+	return &DcrdataClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: httpClient(client)}
+}
+
+// dcrdataURL returns the URL of the dcrdata explorer of net.
+func dcrdataURL(net string) string {
+	if net == "testnet3" {
+		return "https://testnet.dcrdata.org"
+	}
+	return "https://explorer.dcrdata.org"
+}
+
+// PublishTx sends the hex encoded signed transaction to the network and
+// returns its hash.
+func (c *DcrdataClient) PublishTx(ctx context.Context, txHex string) (string, error) {
+	body, err := json.Marshal(struct {
+		RawTx string `json:"rawtx"`
+	}{txHex})
+	if err != nil {
+		return "", err
+	}
+
+	url := c.baseURL + "/insight/api/tx/send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		// dcrdata explains why dcrd rejected the transaction in the body.
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+		if len(bytes.TrimSpace(msg)) > 0 {
+			return "", fmt.Errorf("%s: %s: %s", url, res.Status, bytes.TrimSpace(msg))
+		}
+		return "", fmt.Errorf("%s: %s", url, res.Status)
+	}
+
+	var reply struct {
+		TxID string `json:"txid"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&reply); err != nil {
+		return "", err
+	}
+	return reply.TxID, nil
+}
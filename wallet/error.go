@@ -10,6 +10,10 @@ var (
 	// ErrIDNotExist is returned when a given ID does not exist
 	ErrIDNotExist = errors.New("ID does not exist")
 
+	// ErrNoWallets is returned by commands that need a loaded wallet when
+	// there is none
+	ErrNoWallets = errors.New("no wallets loaded")
+
 	// ErrBadPass wraps dcrlibwallet.ErrInvalidPassphrase
 	ErrBadPass = errors.New(dcrlibwallet.ErrInvalidPassphrase)
 )
@@ -52,3 +56,13 @@ func (err MultiWalletError) Error() string {
 func (err MultiWalletError) Unwrap() error {
 	return err.Err
 }
+
+// cause returns the error wrapped by a wallet error, or err itself.
+// The async commands report it on their error channels, where callers
+// compare it against dcrlibwallet error messages.
+func cause(err error) error {
+	if wrapped := errors.Unwrap(err); wrapped != nil {
+		return wrapped
+	}
+	return err
+}
@@ -0,0 +1,306 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+// Names of the exchange rate sources known to the wallet.
+const (
+	BittrexSource   = "bittrex"
+	CoinGeckoSource = "coingecko"
+	DcrdataSource   = "dcrdata"
+)
+
+// DefaultRateMaxAge is how long a fetched exchange rate is used before it is
+// fetched again.
+const DefaultRateMaxAge = 5 * time.Minute
+
+var (
+	// ErrNoExchangeRate is returned when currency conversion is turned off.
+	ErrNoExchangeRate = errors.New("currency conversion is disabled")
+
+	// ErrUnsupportedCurrency is returned when a source does not quote DCR
+	// in the requested currency.
+	ErrUnsupportedCurrency = errors.New("currency not supported by exchange rate source")
+)
+
+// ExchangeRate is the price of one DCR in a fiat currency.
+type ExchangeRate struct {
+	Source    string
+	Currency  string
+	Rate      float64
+	FetchedAt time.Time
+	// Stale is set when the rate is older than the max age of the cache
+	// because it could not be fetched again.
+	Stale bool
+}
+
+// ExchangeRateSource fetches the current DCR price from a price feed.
+type ExchangeRateSource interface {
+	// Name is the name used to select the source in the
+	// CurrencyConversionConfigKey preference.
+	Name() string
+	// Currencies lists the fiat currencies the source quotes DCR in.
+	Currencies() []string
+	// FetchRate returns the price of one DCR in currency.
+	FetchRate(ctx context.Context, currency string) (float64, error)
+}
+
+// CurrencyConversionValue returns the CurrencyConversionConfigKey preference
+// value that selects currency from source, e.g. "usd_bittrex".
+func CurrencyConversionValue(source, currency string) string {
+	return strings.ToLower(currency) + "_" + source
+}
+
+// ParseCurrencyConversion splits a CurrencyConversionConfigKey preference
+// value into its source and currency. ok is false if the value does not
+// select a source, as with the "none" preference.
+func ParseCurrencyConversion(value string) (source, currency string, ok bool) {
+	i := strings.Index(value, "_")
+	if i <= 0 || i == len(value)-1 {
+		return "", "", false
+	}
+	return value[i+1:], strings.ToUpper(value[:i]), true
+}
+
+// ExchangeRates caches the rates fetched from a set of exchange rate sources.
+// It is safe for concurrent use.
+type ExchangeRates struct {
+	maxAge  time.Duration
+	sources []ExchangeRateSource
+
+	mtx   sync.Mutex
+	rates map[string]ExchangeRate
+}
+
+// NewExchangeRates returns an ExchangeRates that fetches from sources and
+// refreshes rates older than maxAge.
+func NewExchangeRates(maxAge time.Duration, sources ...ExchangeRateSource) *ExchangeRates {
+	return &ExchangeRates{
+		maxAge:  maxAge,
+		sources: sources,
+		rates:   make(map[string]ExchangeRate),
+	}
+}
+
+// Sources returns the sources rates can be fetched from.
+func (er *ExchangeRates) Sources() []ExchangeRateSource {
+	return er.sources
+}
+
+func (er *ExchangeRates) source(name string) ExchangeRateSource {
+	for _, s := range er.sources {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Rate returns the price of one DCR in currency from the named source.
+// Cached rates are returned until they are older than the max age. If a
+// rate cannot be refreshed the cached rate is returned marked as stale.
+func (er *ExchangeRates) Rate(ctx context.Context, source, currency string) (*ExchangeRate, error) {
+	src := er.source(source)
+	if src == nil {
+		return nil, fmt.Errorf("unknown exchange rate source %q", source)
+	}
+
+	key := CurrencyConversionValue(source, currency)
+	er.mtx.Lock()
+	cached, ok := er.rates[key]
+	er.mtx.Unlock()
+	if ok && time.Since(cached.FetchedAt) < er.maxAge {
+		return &cached, nil
+	}
+
+	rate, err := src.FetchRate(ctx, currency)
+	if err != nil {
+		if !ok || err == ErrUnsupportedCurrency {
+			return nil, err
+		}
+		log.Warnf("Using stale %s rate from %s: %v", currency, source, err)
+		cached.Stale = true
+		return &cached, nil
+	}
+
+	fetched := ExchangeRate{
+		Source:    source,
+		Currency:  currency,
+		Rate:      rate,
+		FetchedAt: time.Now(),
+	}
+	er.mtx.Lock()
+	er.rates[key] = fetched
+	er.mtx.Unlock()
+	return &fetched, nil
+}
+
+// ExchangeRateCtx returns the DCR rate of the currency and source selected in
+// the CurrencyConversionConfigKey preference. ErrNoExchangeRate is returned
+// if currency conversion is turned off.
+func (wal *Wallet) ExchangeRateCtx(ctx context.Context) (*ExchangeRate, error) {
+	value := wal.ReadStringConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey)
+	source, currency, ok := ParseCurrencyConversion(value)
+	if !ok {
+		return nil, ErrNoExchangeRate
+	}
+	return wal.ExchangeRates.Rate(ctx, source, currency)
+}
+
+// ExchangeRate returns the DCR rate of the currency and source selected in
+// the CurrencyConversionConfigKey preference.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) ExchangeRate() RequestID {
+	req := wal.newRequest(OpExchangeRate)
+	go func() {
+		rate, err := wal.ExchangeRateCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(rate)
+	}()
+	return req.ID
+}
+
+// getJSON decodes the JSON body returned by a GET request for url into target.
+func getJSON(ctx context.Context, client *http.Client, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(target)
+}
+
+func httpClient(client *http.Client) *http.Client {
+	if client == nil {
+		return &http.Client{Timeout: 30 * time.Second}
+	}
+	return client
+}
+
+// Bittrex quotes DCR against the USDT stablecoin, which is used as USD.
+type bittrex struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewBittrexSource returns a source for the Bittrex DCR-USDT market at
+// baseURL, e.g. "https://api.bittrex.com". A nil client uses a default client.
+func NewBittrexSource(baseURL string, client *http.Client) ExchangeRateSource {
+	return &bittrex{baseURL: baseURL, client: httpClient(client)}
+}
+
+func (b *bittrex) Name() string { return BittrexSource }
+
+func (b *bittrex) Currencies() []string { return []string{"USD"} }
+
+func (b *bittrex) FetchRate(ctx context.Context, currency string) (float64, error) {
+	if currency != "USD" {
+		return 0, ErrUnsupportedCurrency
+	}
+
+	var ticker struct {
+		LastTradeRate string `json:"lastTradeRate"`
+	}
+	err := getJSON(ctx, b.client, b.baseURL+"/v3/markets/DCR-USDT/ticker", &ticker)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(ticker.LastTradeRate, 64)
+}
+
+type coinGecko struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewCoinGeckoSource returns a source for the CoinGecko simple price API at
+// baseURL, e.g. "https://api.coingecko.com". A nil client uses a default client.
+func NewCoinGeckoSource(baseURL string, client *http.Client) ExchangeRateSource {
+	return &coinGecko{baseURL: baseURL, client: httpClient(client)}
+}
+
+func (c *coinGecko) Name() string { return CoinGeckoSource }
+
+func (c *coinGecko) Currencies() []string {
+	return []string{"USD", "EUR", "GBP", "JPY", "CNY", "BRL"}
+}
+
+func (c *coinGecko) FetchRate(ctx context.Context, currency string) (float64, error) {
+	supported := false
+	for _, cur := range c.Currencies() {
+		supported = supported || cur == currency
+	}
+	if !supported {
+		return 0, ErrUnsupportedCurrency
+	}
+
+	vs := strings.ToLower(currency)
+	var prices map[string]map[string]float64
+	err := getJSON(ctx, c.client, c.baseURL+"/api/v3/simple/price?ids=decred&vs_currencies="+url.QueryEscape(vs), &prices)
+	if err != nil {
+		return 0, err
+	}
+
+	rate, ok := prices["decred"][vs]
+	if !ok {
+		return 0, fmt.Errorf("no %s price for decred in response", currency)
+	}
+	return rate, nil
+}
+
+// dcrdata serves the exchange rate index of a dcrdata instance, which is
+// quoted in a single currency chosen by its operator.
+type dcrdata struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewDcrdataSource returns a source for the exchange rate API of the dcrdata
+// instance at baseURL, e.g. "https://explorer.dcrdata.org". A nil client
+// uses a default client.
+func NewDcrdataSource(baseURL string, client *http.Client) ExchangeRateSource {
+	return &dcrdata{baseURL: baseURL, client: httpClient(client)}
+}
+
+func (d *dcrdata) Name() string { return DcrdataSource }
+
+func (d *dcrdata) Currencies() []string { return []string{"USD"} }
+
+func (d *dcrdata) FetchRate(ctx context.Context, currency string) (float64, error) {
+	var rates struct {
+		Index    string  `json:"btcIndex"`
+		DcrPrice float64 `json:"dcrPrice"`
+	}
+	err := getJSON(ctx, d.client, d.baseURL+"/api/exchangerate", &rates)
+	if err != nil {
+		return 0, err
+	}
+	if rates.Index != currency {
+		return 0, ErrUnsupportedCurrency
+	}
+	return rates.DcrPrice, nil
+}
@@ -0,0 +1,136 @@
+package wallet_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Exchange rates", func() {
+	var (
+		server   *httptest.Server
+		requests int32
+		failing  int32
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&requests, 0)
+		atomic.StoreInt32(&failing, 0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if atomic.LoadInt32(&failing) == 1 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+
+			switch r.URL.Path {
+			case "/v3/markets/DCR-USDT/ticker":
+				fmt.Fprint(w, `{"symbol":"DCR-USDT","lastTradeRate":"130.25"}`)
+			case "/api/v3/simple/price":
+				Expect(r.URL.Query().Get("ids")).To(Equal("decred"))
+				vs := r.URL.Query().Get("vs_currencies")
+				fmt.Fprintf(w, `{"decred":{"%s":110.5}}`, vs)
+			case "/api/exchangerate":
+				fmt.Fprint(w, `{"btcIndex":"USD","dcrPrice":129.75,"btcPrice":50000}`)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("parses currency conversion preferences", func() {
+		source, currency, ok := ParseCurrencyConversion("usd_bittrex")
+		Expect(ok).To(BeTrue())
+		Expect(source).To(Equal(BittrexSource))
+		Expect(currency).To(Equal("USD"))
+		Expect(CurrencyConversionValue(CoinGeckoSource, "EUR")).To(Equal("eur_coingecko"))
+
+		_, _, ok = ParseCurrencyConversion("none")
+		Expect(ok).To(BeFalse())
+		_, _, ok = ParseCurrencyConversion("")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("fetches from each source", func() {
+		ctx := context.Background()
+
+		rate, err := NewBittrexSource(server.URL, nil).FetchRate(ctx, "USD")
+		Expect(err).To(BeNil())
+		Expect(rate).To(Equal(130.25))
+
+		rate, err = NewCoinGeckoSource(server.URL, nil).FetchRate(ctx, "EUR")
+		Expect(err).To(BeNil())
+		Expect(rate).To(Equal(110.5))
+
+		rate, err = NewDcrdataSource(server.URL, nil).FetchRate(ctx, "USD")
+		Expect(err).To(BeNil())
+		Expect(rate).To(Equal(129.75))
+
+		_, err = NewBittrexSource(server.URL, nil).FetchRate(ctx, "EUR")
+		Expect(err).To(Equal(ErrUnsupportedCurrency))
+		_, err = NewDcrdataSource(server.URL, nil).FetchRate(ctx, "EUR")
+		Expect(err).To(Equal(ErrUnsupportedCurrency))
+	})
+
+	It("caches rates and marks them stale when they cannot be refreshed", func() {
+		rates := NewExchangeRates(time.Hour, NewBittrexSource(server.URL, nil))
+		ctx := context.Background()
+
+		rate, err := rates.Rate(ctx, BittrexSource, "USD")
+		Expect(err).To(BeNil())
+		Expect(rate.Rate).To(Equal(130.25))
+		Expect(rate.Stale).To(BeFalse())
+		Expect(rate.FetchedAt).ToNot(BeZero())
+
+		_, err = rates.Rate(ctx, BittrexSource, "USD")
+		Expect(err).To(BeNil())
+		Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(1))
+
+		expired := NewExchangeRates(0, NewBittrexSource(server.URL, nil))
+		_, err = expired.Rate(ctx, BittrexSource, "USD")
+		Expect(err).To(BeNil())
+		atomic.StoreInt32(&failing, 1)
+		rate, err = expired.Rate(ctx, BittrexSource, "USD")
+		Expect(err).To(BeNil())
+		Expect(rate.Stale).To(BeTrue())
+		Expect(rate.Rate).To(Equal(130.25))
+
+		_, err = expired.Rate(ctx, BittrexSource, "EUR")
+		Expect(err).To(Equal(ErrUnsupportedCurrency))
+		_, err = expired.Rate(ctx, "unknown", "USD")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("uses the source selected in the currency conversion preference", func() {
+		defaultRates := wal.ExchangeRates
+		defer func() {
+			wal.ExchangeRates = defaultRates
+			wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "none")
+		}()
+		wal.ExchangeRates = NewExchangeRates(time.Hour,
+			NewBittrexSource(server.URL, nil), NewCoinGeckoSource(server.URL, nil))
+
+		wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "none")
+		_, err := wal.ExchangeRateCtx(context.Background())
+		Expect(err).To(Equal(ErrNoExchangeRate))
+
+		wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "gbp_coingecko")
+		rate, err := wal.ExchangeRateCtx(context.Background())
+		Expect(err).To(BeNil())
+		Expect(rate.Source).To(Equal(CoinGeckoSource))
+		Expect(rate.Currency).To(Equal("GBP"))
+		Expect(rate.Rate).To(Equal(110.5))
+	})
+})
@@ -0,0 +1,502 @@
+package wallet
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+// ExportFormat is the file format of a transaction export.
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+	ExportOFX  ExportFormat = "ofx"
+)
+
+// exportDirName is the directory in the app data folder exports are written to.
+const exportDirName = "exports"
+
+// ofxTimeFormat is the datetime format used in OFX files.
+const ofxTimeFormat = "20060102150405"
+
+// FiatRateFunc returns the DCR price in fiat at time t. ok is false if the
+// rate at t is not known.
+type FiatRateFunc func(t time.Time) (rate float64, ok bool)
+
+// TxExportFilter selects the transactions of a wallet that are exported.
+type TxExportFilter struct {
+	WalletID int
+	// Account limits the export to transactions with an input or output
+	// in the account. A negative account exports all accounts.
+	Account int32
+	// From and To bound the transaction time to [From, To). A zero time
+	// leaves that end of the range open.
+	From, To time.Time
+	// Types lists the dcrlibwallet.TxType* values to export, e.g.
+	// TxTypeRegular, TxTypeTicketPurchase, TxTypeVote or TxTypeRevocation.
+	// All types are exported if it is empty.
+	Types []string
+	// TxFilter is one of the dcrlibwallet.TxFilter* values, e.g.
+	// TxFilterSent, TxFilterReceived or TxFilterStaking. The zero value
+	// TxFilterAll exports all transactions.
+	TxFilter int32
+}
+
+// matchTxFilter reports whether txn is selected by a dcrlibwallet.TxFilter*
+// value the way the wallet selects transactions with it.
+func matchTxFilter(txFilter int32, txn *dcrlibwallet.Transaction) bool {
+	regular := txn.Type == dcrlibwallet.TxTypeRegular
+	switch txFilter {
+	case dcrlibwallet.TxFilterSent:
+		return regular && txn.Direction == dcrlibwallet.TxDirectionSent
+	case dcrlibwallet.TxFilterReceived:
+		return regular && txn.Direction == dcrlibwallet.TxDirectionReceived
+	case dcrlibwallet.TxFilterTransferred:
+		return regular && txn.Direction == dcrlibwallet.TxDirectionTransferred
+	case dcrlibwallet.TxFilterStaking:
+		return txn.Type == dcrlibwallet.TxTypeTicketPurchase || txn.Type == dcrlibwallet.TxTypeVote ||
+			txn.Type == dcrlibwallet.TxTypeRevocation
+	default:
+		return true
+	}
+}
+
+// Match reports whether txn is selected by the filter.
+func (f TxExportFilter) Match(txn *dcrlibwallet.Transaction) bool {
+	if txn.WalletID != f.WalletID {
+		return false
+	}
+
+	t := time.Unix(txn.Timestamp, 0)
+	if !f.From.IsZero() && t.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !t.Before(f.To) {
+		return false
+	}
+
+	if len(f.Types) > 0 {
+		found := false
+		for _, txType := range f.Types {
+			if txn.Type == txType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !matchTxFilter(f.TxFilter, txn) {
+		return false
+	}
+
+	if f.Account < 0 {
+		return true
+	}
+	for _, input := range txn.Inputs {
+		if input.AccountNumber == f.Account {
+			return true
+		}
+	}
+	for _, output := range txn.Outputs {
+		if output.AccountNumber == f.Account {
+			return true
+		}
+	}
+	return false
+}
+
+// TxExport holds the options of a transaction export.
+type TxExport struct {
+	Format ExportFormat
+	Filter TxExportFilter
+	// FiatCurrency is the currency FiatRate is quoted in.
+	FiatCurrency string
+	// FiatRate is used to value each transaction at the time it was made.
+	// Fiat values are left out if it is nil.
+	FiatRate FiatRateFunc
+	// Notes are the notes and tags exported with each transaction.
+	// ExportTransactionsCtx reads them from the wallet if it is nil.
+	Notes *TxNotes
+}
+
+// TxExportRecord is a single exported transaction.
+type TxExportRecord struct {
+	Date          time.Time `json:"date"`
+	Hash          string    `json:"hash"`
+	Wallet        string    `json:"wallet"`
+	Type          string    `json:"type"`
+	Direction     string    `json:"direction"`
+	Amount        float64   `json:"amount"`
+	Fee           float64   `json:"fee"`
+	BlockHeight   int32     `json:"block_height"`
+	Confirmations int32     `json:"confirmations"`
+	Status        string    `json:"status"`
+	FiatValue     *float64  `json:"fiat_value,omitempty"`
+	FiatCurrency  string    `json:"fiat_currency,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+}
+
+// txDirection returns the name of a dcrlibwallet transaction direction.
+func txDirection(direction int32) string {
+	switch direction {
+	case dcrlibwallet.TxDirectionSent:
+		return "sent"
+	case dcrlibwallet.TxDirectionReceived:
+		return "received"
+	case dcrlibwallet.TxDirectionTransferred:
+		return "transferred"
+	default:
+		return "unknown"
+	}
+}
+
+// exportRecords filters txs and converts them to export records, oldest first.
+func exportRecords(txs []Transaction, opts TxExport) []TxExportRecord {
+	records := make([]TxExportRecord, 0, len(txs))
+	for i := range txs {
+		txn := &txs[i]
+		if !opts.Filter.Match(&txn.Txn) {
+			continue
+		}
+
+		amount := dcrutil.Amount(txn.Txn.Amount).ToCoin()
+		if txn.Txn.Direction == dcrlibwallet.TxDirectionSent {
+			amount = -amount
+		}
+
+		record := TxExportRecord{
+			Date:          time.Unix(txn.Txn.Timestamp, 0).UTC(),
+			Hash:          txn.Txn.Hash,
+			Wallet:        txn.WalletName,
+			Type:          txn.Txn.Type,
+			Direction:     txDirection(txn.Txn.Direction),
+			Amount:        amount,
+			Fee:           dcrutil.Amount(txn.Txn.Fee).ToCoin(),
+			BlockHeight:   txn.Txn.BlockHeight,
+			Confirmations: txn.Confirmations,
+			Status:        txn.Status,
+		}
+		if opts.FiatRate != nil {
+			if rate, ok := opts.FiatRate(record.Date); ok {
+				value := amount * rate
+				record.FiatValue = &value
+				record.FiatCurrency = opts.FiatCurrency
+			}
+		}
+		if opts.Notes != nil {
+			note := opts.Notes.For(&txn.Txn)
+			record.Note, record.Tags = note.Note, note.Tags
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Date.Before(records[j].Date)
+	})
+	return records
+}
+
+// WriteTransactions writes the transactions in txs selected by opts.Filter
+// to w in opts.Format. It returns the number of transactions written.
+func WriteTransactions(w io.Writer, txs []Transaction, opts TxExport) (int, error) {
+	records := exportRecords(txs, opts)
+
+	var err error
+	switch opts.Format {
+	case ExportCSV:
+		err = writeCSV(w, records)
+	case ExportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(records)
+	case ExportOFX:
+		err = writeOFX(w, records, opts.Filter)
+	default:
+		return 0, fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+func formatCoin(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 8, 64)
+}
+
+func writeCSV(w io.Writer, records []TxExportRecord) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"date", "hash", "wallet", "type", "direction", "amount", "fee",
+		"block_height", "confirmations", "status", "fiat_value", "fiat_currency", "note", "tags"})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		var fiatValue string
+		if r.FiatValue != nil {
+			fiatValue = strconv.FormatFloat(*r.FiatValue, 'f', 2, 64)
+		}
+		err := cw.Write([]string{
+			r.Date.Format(time.RFC3339),
+			r.Hash,
+			r.Wallet,
+			r.Type,
+			r.Direction,
+			formatCoin(r.Amount),
+			formatCoin(r.Fee),
+			strconv.Itoa(int(r.BlockHeight)),
+			strconv.Itoa(int(r.Confirmations)),
+			r.Status,
+			fiatValue,
+			r.FiatCurrency,
+			r.Note,
+			strings.Join(r.Tags, " "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxTransaction struct {
+	Type     string       `xml:"TRNTYPE"`
+	Posted   string       `xml:"DTPOSTED"`
+	Amount   string       `xml:"TRNAMT"`
+	FITID    string       `xml:"FITID"`
+	Name     string       `xml:"NAME"`
+	Memo     string       `xml:"MEMO,omitempty"`
+	Currency *ofxCurrency `xml:"CURRENCY,omitempty"`
+}
+
+type ofxCurrency struct {
+	Rate   string `xml:"CURRATE"`
+	Symbol string `xml:"CURSYM"`
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Status   ofxStatus `xml:"STATUS"`
+		Server   string    `xml:"DTSERVER"`
+		Language string    `xml:"LANGUAGE"`
+	} `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statement struct {
+		TrnUID    string           `xml:"TRNUID"`
+		Status    ofxStatus        `xml:"STATUS"`
+		Currency  string           `xml:"STMTRS>CURDEF"`
+		BankID    string           `xml:"STMTRS>BANKACCTFROM>BANKID"`
+		AccountID string           `xml:"STMTRS>BANKACCTFROM>ACCTID"`
+		AcctType  string           `xml:"STMTRS>BANKACCTFROM>ACCTTYPE"`
+		Start     string           `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+		End       string           `xml:"STMTRS>BANKTRANLIST>DTEND"`
+		Txs       []ofxTransaction `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+		Balance   string           `xml:"STMTRS>LEDGERBAL>BALAMT"`
+		BalanceAt string           `xml:"STMTRS>LEDGERBAL>DTASOF"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+// writeOFX writes records as an OFX 2 bank statement in DCR. The ledger
+// balance is the net amount of the exported transactions.
+func writeOFX(w io.Writer, records []TxExportRecord, filter TxExportFilter) error {
+	now := time.Now().UTC()
+
+	var doc ofxDocument
+	doc.SignOn.Status = ofxStatus{Severity: "INFO"}
+	doc.SignOn.Server = now.Format(ofxTimeFormat)
+	doc.SignOn.Language = "ENG"
+
+	stmt := &doc.Statement
+	stmt.TrnUID = "0"
+	stmt.Status = ofxStatus{Severity: "INFO"}
+	stmt.Currency = "DCR"
+	stmt.BankID = "godcr"
+	stmt.AccountID = strconv.Itoa(filter.WalletID)
+	if filter.Account >= 0 {
+		stmt.AccountID = fmt.Sprintf("%d-%d", filter.WalletID, filter.Account)
+	}
+	stmt.AcctType = "CHECKING"
+
+	start, end := filter.From, filter.To
+	if len(records) > 0 {
+		if start.IsZero() {
+			start = records[0].Date
+		}
+		if end.IsZero() {
+			end = records[len(records)-1].Date
+		}
+	}
+	if end.IsZero() {
+		end = now
+	}
+	if start.IsZero() {
+		start = end
+	}
+	stmt.Start = start.UTC().Format(ofxTimeFormat)
+	stmt.End = end.UTC().Format(ofxTimeFormat)
+
+	var balance float64
+	for _, r := range records {
+		trnType := "CREDIT"
+		switch r.Direction {
+		case "sent":
+			trnType = "DEBIT"
+		case "transferred":
+			trnType = "XFER"
+		}
+
+		txn := ofxTransaction{
+			Type:   trnType,
+			Posted: r.Date.Format(ofxTimeFormat),
+			Amount: formatCoin(r.Amount),
+			FITID:  r.Hash,
+			Name:   r.Type,
+		}
+		if r.FiatValue != nil && r.Amount != 0 {
+			txn.Currency = &ofxCurrency{
+				Rate:   strconv.FormatFloat(*r.FiatValue/r.Amount, 'f', -1, 64),
+				Symbol: r.FiatCurrency,
+			}
+			txn.Memo = fmt.Sprintf("%.2f %s", *r.FiatValue, r.FiatCurrency)
+		}
+		if note := (TxNote{Note: r.Note, Tags: r.Tags}); !note.IsEmpty() {
+			memo := strings.TrimSpace(note.Note + " " + note.TagsString())
+			if txn.Memo != "" {
+				memo = txn.Memo + "; " + memo
+			}
+			txn.Memo = memo
+		}
+		stmt.Txs = append(stmt.Txs, txn)
+		balance += r.Amount
+	}
+	stmt.Balance = formatCoin(balance)
+	stmt.BalanceAt = stmt.End
+
+	header := xml.Header + `<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// exportFileName returns walletName made safe to use in a file name: path
+// separators are dropped and characters other than letters, digits, '-' and
+// '_' are replaced with '_'.
+func exportFileName(walletName string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, filepath.Base(filepath.Clean("/"+walletName)))
+	if strings.Trim(name, "_") == "" {
+		return "wallet"
+	}
+	return name
+}
+
+// ExportTransactionsCtx writes the transactions selected by opts to a file in
+// the exports folder of the app data directory.
+// It blocks until the file is written or ctx is canceled.
+func (wal *Wallet) ExportTransactionsCtx(ctx context.Context, opts TxExport) (*TransactionsExported, error) {
+	wall := wal.multi.WalletWithID(opts.Filter.WalletID)
+	if wall == nil {
+		return nil, ErrIDNotExist
+	}
+
+	raw, err := wall.GetTransactionsRaw(0, 0, opts.Filter.TxFilter, true)
+	if err != nil {
+		return nil, err
+	}
+	bestBlock := wal.multi.GetBestBlock()
+	txs := make([]Transaction, len(raw))
+	for i, txnRaw := range raw {
+		txs[i] = newTransaction(wall.Name, txnRaw, bestBlock.Height)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Notes == nil {
+		opts.Notes = wal.TxNotes(wall.ID)
+	}
+
+	dir := filepath.Join(wal.root, exportDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-transactions-%s.%s", exportFileName(wall.Name), time.Now().Format("20060102-150405"), opts.Format)
+	path := filepath.Join(dir, name)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := WriteTransactions(file, txs, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, InternalWalletError{
+			Message:  "Could not export transactions",
+			Affected: []int{wall.ID},
+			Err:      err,
+		}
+	}
+
+	return &TransactionsExported{
+		Path:  path,
+		Count: count,
+	}, nil
+}
+
+// ExportTransactions writes the transactions selected by opts to a file in
+// the exports folder of the app data directory.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) ExportTransactions(opts TxExport, errChan chan error) RequestID {
+	req := wal.newRequest(OpExportTransactions)
+	go func() {
+		exported, err := wal.ExportTransactionsCtx(context.Background(), opts)
+		if err != nil {
+			sendErr(errChan, cause(err))
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(exported)
+	}()
+	return req.ID
+}
@@ -0,0 +1,185 @@
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Transaction export", func() {
+	day := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	txs := []Transaction{
+		{
+			Txn: dcrlibwallet.Transaction{
+				WalletID:  1,
+				Hash:      "vote",
+				Type:      dcrlibwallet.TxTypeVote,
+				Timestamp: day.Add(48 * time.Hour).Unix(),
+				Direction: dcrlibwallet.TxDirectionReceived,
+				Amount:    150000000,
+				Outputs:   []*dcrlibwallet.TxOutput{{AccountNumber: 0}},
+			},
+			WalletName: "default",
+			Status:     "confirmed",
+		},
+		{
+			Txn: dcrlibwallet.Transaction{
+				WalletID:  1,
+				Hash:      "payment",
+				Type:      dcrlibwallet.TxTypeRegular,
+				Timestamp: day.Unix(),
+				Direction: dcrlibwallet.TxDirectionSent,
+				Amount:    100000000,
+				Fee:       2550,
+				Inputs:    []*dcrlibwallet.TxInput{{AccountNumber: 1}},
+			},
+			WalletName: "default",
+			Status:     "confirmed",
+		},
+		{
+			Txn: dcrlibwallet.Transaction{
+				WalletID:  2,
+				Hash:      "other wallet",
+				Type:      dcrlibwallet.TxTypeRegular,
+				Timestamp: day.Unix(),
+			},
+		},
+	}
+	rate := func(t time.Time) (float64, bool) {
+		if t.Before(day.Add(24 * time.Hour)) {
+			return 120.5, true
+		}
+		return 0, false
+	}
+
+	It("writes CSV oldest first with fiat values where known", func() {
+		var buf bytes.Buffer
+		n, err := WriteTransactions(&buf, txs, TxExport{
+			Format:       ExportCSV,
+			Filter:       TxExportFilter{WalletID: 1, Account: -1},
+			FiatCurrency: "USD",
+			FiatRate:     rate,
+		})
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(2))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		Expect(err).To(BeNil())
+		Expect(rows).To(HaveLen(3))
+		Expect(rows[1][1]).To(Equal("payment"))
+		Expect(rows[1][5]).To(Equal("-1.00000000"))
+		Expect(rows[1][6]).To(Equal("0.00002550"))
+		Expect(rows[1][10:12]).To(Equal([]string{"-120.50", "USD"}))
+		Expect(rows[2][1]).To(Equal("vote"))
+		Expect(rows[2][10:12]).To(Equal([]string{"", ""}))
+	})
+
+	It("filters by account, type, direction and date", func() {
+		var records []TxExportRecord
+		decode := func(filter TxExportFilter) {
+			var buf bytes.Buffer
+			_, err := WriteTransactions(&buf, txs, TxExport{Format: ExportJSON, Filter: filter})
+			Expect(err).To(BeNil())
+			Expect(json.Unmarshal(buf.Bytes(), &records)).To(Succeed())
+		}
+
+		decode(TxExportFilter{WalletID: 1, Account: 1})
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Hash).To(Equal("payment"))
+
+		decode(TxExportFilter{WalletID: 1, Account: -1, Types: []string{dcrlibwallet.TxTypeVote}})
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Hash).To(Equal("vote"))
+
+		decode(TxExportFilter{WalletID: 1, Account: -1, TxFilter: dcrlibwallet.TxFilterSent})
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Hash).To(Equal("payment"))
+
+		decode(TxExportFilter{WalletID: 1, Account: -1, TxFilter: dcrlibwallet.TxFilterStaking})
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Hash).To(Equal("vote"))
+
+		decode(TxExportFilter{WalletID: 1, Account: -1, TxFilter: dcrlibwallet.TxFilterReceived})
+		Expect(records).To(BeEmpty())
+
+		decode(TxExportFilter{WalletID: 1, Account: -1, From: day.Add(time.Hour)})
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Hash).To(Equal("vote"))
+
+		decode(TxExportFilter{WalletID: 1, Account: -1, To: day})
+		Expect(records).To(BeEmpty())
+	})
+
+	It("writes a well formed OFX statement", func() {
+		var buf bytes.Buffer
+		_, err := WriteTransactions(&buf, txs, TxExport{
+			Format: ExportOFX,
+			Filter: TxExportFilter{WalletID: 1, Account: -1},
+		})
+		Expect(err).To(BeNil())
+
+		var doc struct {
+			Txs []struct {
+				Type   string `xml:"TRNTYPE"`
+				Amount string `xml:"TRNAMT"`
+				FITID  string `xml:"FITID"`
+			} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+			Balance string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>LEDGERBAL>BALAMT"`
+		}
+		Expect(xml.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Txs).To(HaveLen(2))
+		Expect(doc.Txs[0].Type).To(Equal("DEBIT"))
+		Expect(doc.Txs[0].FITID).To(Equal("payment"))
+		Expect(doc.Txs[1].Type).To(Equal("CREDIT"))
+		Expect(doc.Balance).To(Equal("0.50000000"))
+	})
+
+	It("rejects unknown formats", func() {
+		_, err := WriteTransactions(ioutil.Discard, txs, TxExport{Format: "xls"})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("writes the export into the app data directory", func() {
+		exported, err := wal.ExportTransactionsCtx(context.Background(), TxExport{
+			Format: ExportCSV,
+			Filter: TxExportFilter{WalletID: 1, Account: -1},
+		})
+		Expect(err).To(BeNil())
+		Expect(exported.Count).To(Equal(0))
+		Expect(filepath.Dir(exported.Path)).To(Equal(filepath.Join(testDir, "exports")))
+
+		data, err := ioutil.ReadFile(exported.Path)
+		Expect(err).To(BeNil())
+		Expect(strings.HasPrefix(string(data), "date,hash,wallet")).To(BeTrue())
+
+		_, err = wal.ExportTransactionsCtx(context.Background(), TxExport{
+			Format: ExportCSV,
+			Filter: TxExportFilter{WalletID: 99},
+		})
+		Expect(err).To(Equal(ErrIDNotExist))
+	})
+
+	It("keeps unsafe wallet names out of the export path", func() {
+		Expect(wal.RenameWalletCtx(context.Background(), 1, "../..\\my: wallet")).To(Succeed())
+		defer wal.RenameWalletCtx(context.Background(), 1, "exported")
+
+		exported, err := wal.ExportTransactionsCtx(context.Background(), TxExport{
+			Format: ExportCSV,
+			Filter: TxExportFilter{WalletID: 1, Account: -1},
+		})
+		Expect(err).To(BeNil())
+		Expect(filepath.Dir(exported.Path)).To(Equal(filepath.Join(testDir, "exports")))
+		Expect(filepath.Base(exported.Path)).To(ContainSubstring("my__wallet-transactions-"))
+	})
+})
@@ -0,0 +1,98 @@
+package wallet
+
+import (
+	"fmt"
+
+	"decred.org/dcrwallet/wallet/txrules"
+)
+
+const (
+	unsignedTxFeeRateConfigKey = "unsigned_tx_fee_rate"
+
+	// maxFeeRate is the highest custom fee rate accepted, 1 DCR/kB, to
+	// guard against typos paying away a large part of a transaction.
+	maxFeeRate = 1e8
+)
+
+// FeeRatePreset names a fee rate the user can pick.
+type FeeRatePreset string
+
+// Economy is the minimum relay fee, the only rate dcrlibwallet signs
+// transactions with, and the default. Decred blocks are rarely full so the
+// other presets are multiples of it rather than market estimates.
+const (
+	FeeRateEconomy  FeeRatePreset = "economy"
+	FeeRateNormal   FeeRatePreset = "normal"
+	FeeRatePriority FeeRatePreset = "priority"
+	FeeRateCustom   FeeRatePreset = "custom"
+)
+
+// ErrInvalidFeeRate is returned for a custom fee rate below the minimum relay
+// fee or above 1 DCR/kB.
+var ErrInvalidFeeRate = fmt.Errorf("fee rate must be between %d and %d atoms/kB",
+	int64(txrules.DefaultRelayFeePerKb), int64(maxFeeRate))
+
+// FeeRate is the fee paid per kB of a transaction.
+type FeeRate struct {
+	Preset     FeeRatePreset
+	AtomsPerKB int64
+}
+
+// Fee returns the fee of a transaction of size bytes.
+func (r FeeRate) Fee(size int) int64 {
+	return r.AtomsPerKB * int64(size) / 1000
+}
+
+func (r FeeRate) String() string {
+	return fmt.Sprintf("%d atoms/Byte", r.AtomsPerKB/1000)
+}
+
+// FeeRatePresets returns the economy, normal and priority fee rates.
+func FeeRatePresets() []FeeRate {
+	relayFee := int64(txrules.DefaultRelayFeePerKb)
+	return []FeeRate{
+		{Preset: FeeRateEconomy, AtomsPerKB: relayFee},
+		{Preset: FeeRateNormal, AtomsPerKB: 2 * relayFee},
+		{Preset: FeeRatePriority, AtomsPerKB: 5 * relayFee},
+	}
+}
+
+// NewFeeRate returns the rate of a preset, or custom for FeeRateCustom.
+func NewFeeRate(preset FeeRatePreset, custom int64) (FeeRate, error) {
+	if preset == FeeRateCustom {
+		if custom < int64(txrules.DefaultRelayFeePerKb) || custom > maxFeeRate {
+			return FeeRate{}, ErrInvalidFeeRate
+		}
+		return FeeRate{Preset: FeeRateCustom, AtomsPerKB: custom}, nil
+	}
+	for _, rate := range FeeRatePresets() {
+		if rate.Preset == preset {
+			return rate, nil
+		}
+	}
+	return FeeRate{}, fmt.Errorf("unknown fee rate preset %q", preset)
+}
+
+func (wal *Wallet) readFeeRate(key string) FeeRate {
+	rate := FeeRatePresets()[0]
+	wal.multi.ReadUserConfigValue(key, &rate)
+	return rate
+}
+
+// UnsignedTxFeeRate returns the fee rate unsigned transactions are built
+// with. dcrlibwallet signs the transactions it sends and the tickets it buys
+// at the economy rate, so only transactions built for offline signing can pay
+// another rate.
+func (wal *Wallet) UnsignedTxFeeRate() FeeRate {
+	return wal.readFeeRate(unsignedTxFeeRateConfigKey)
+}
+
+// SetUnsignedTxFeeRate saves the fee rate unsigned transactions are built
+// with.
+func (wal *Wallet) SetUnsignedTxFeeRate(rate FeeRate) error {
+	if _, err := NewFeeRate(rate.Preset, rate.AtomsPerKB); err != nil {
+		return err
+	}
+	wal.multi.SaveUserConfigValue(unsignedTxFeeRateConfigKey, rate)
+	return nil
+}
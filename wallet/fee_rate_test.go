@@ -0,0 +1,44 @@
+package wallet_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Fee rates", func() {
+	It("computes fees from presets and custom rates", func() {
+		presets := FeeRatePresets()
+		Expect(presets).To(HaveLen(3))
+		Expect(presets[0].Preset).To(Equal(FeeRateEconomy))
+		Expect(presets[0].String()).To(Equal("10 atoms/Byte"))
+		Expect(presets[0].Fee(250)).To(BeEquivalentTo(2500))
+		Expect(presets[2].AtomsPerKB).To(BeNumerically(">", presets[1].AtomsPerKB))
+
+		rate, err := NewFeeRate(FeeRateNormal, 0)
+		Expect(err).To(BeNil())
+		Expect(rate).To(Equal(presets[1]))
+
+		rate, err = NewFeeRate(FeeRateCustom, 15000)
+		Expect(err).To(BeNil())
+		Expect(rate.Fee(1000)).To(BeEquivalentTo(15000))
+
+		_, err = NewFeeRate(FeeRateCustom, 100)
+		Expect(err).To(Equal(ErrInvalidFeeRate))
+		_, err = NewFeeRate("fastest", 0)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("saves the unsigned transaction fee rate", func() {
+		Expect(wal.UnsignedTxFeeRate().Preset).To(Equal(FeeRateEconomy))
+
+		custom := FeeRate{Preset: FeeRateCustom, AtomsPerKB: 30000}
+		Expect(wal.SetUnsignedTxFeeRate(custom)).To(Succeed())
+		Expect(wal.UnsignedTxFeeRate()).To(Equal(custom))
+
+		Expect(wal.SetUnsignedTxFeeRate(FeeRate{Preset: FeeRateCustom, AtomsPerKB: 1})).To(Equal(ErrInvalidFeeRate))
+		Expect(wal.UnsignedTxFeeRate()).To(Equal(custom))
+
+		Expect(wal.SetUnsignedTxFeeRate(FeeRatePresets()[0])).To(Succeed())
+	})
+})
@@ -0,0 +1,310 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"decred.org/dcrwallet/walletseed"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+// maxSignerAccounts is the number of accounts of each coin type searched for
+// the account of an unsigned transaction.
+const maxSignerAccounts = 100
+
+// signedTxVerifyFlags are the script flags signed inputs are checked with,
+// those dcrwallet checks its own transactions with.
+const signedTxVerifyFlags = txscript.ScriptDiscourageUpgradableNops |
+	txscript.ScriptVerifyCleanStack |
+	txscript.ScriptVerifyCheckLockTimeVerify |
+	txscript.ScriptVerifyCheckSequenceVerify |
+	txscript.ScriptVerifyTreasury
+
+// ErrSeedMismatch is returned when the account of an unsigned transaction is
+// not found among the accounts of the seed it is signed with.
+var ErrSeedMismatch = errors.New("the seed does not hold the account of this transaction")
+
+// SignedTx is an unsigned transaction signed with SignUnsignedTx. It is
+// exported as JSON and broadcast with BroadcastSignedTx by the watch-only
+// wallet it was built with.
+type SignedTx struct {
+	Network string `json:"network"`
+	Hash    string `json:"hash"`
+	// Tx is the hex encoded signed transaction.
+	Tx string `json:"tx"`
+}
+
+// SignUnsignedTx signs every input of tx with the keys of seed, the words or
+// hex of a wallet seed. The account is found by the extended public key of
+// tx among the first accounts of the seed, and each input is checked to pay
+// the key it is signed with. A change output must pay the internal address
+// of the account it is listed with.
+func SignUnsignedTx(tx *UnsignedTx, seed string) (*SignedTx, error) {
+	params, err := utils.ChainParams(tx.Network)
+	if err != nil {
+		return nil, err
+	}
+	msgTx, err := tx.MsgTx()
+	if err != nil {
+		return nil, err
+	}
+
+	seedBytes, err := walletseed.DecodeUserInput(seed)
+	if err != nil {
+		return nil, err
+	}
+	master, err := hdkeychain.NewMaster(seedBytes, params)
+	zero(seedBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer master.Zero()
+
+	account, err := findAccountKey(master, params, tx.AccountXpub)
+	if err != nil {
+		return nil, err
+	}
+	defer account.Zero()
+
+	if tx.ChangeIndex >= 0 {
+		if err := checkChange(msgTx.TxOut[tx.ChangeIndex], account, tx.ChangeAddressIndex, params); err != nil {
+			return nil, err
+		}
+	}
+
+	pkScripts := make([][]byte, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		pkScripts[i], err = hex.DecodeString(in.ScriptPubKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := signInput(msgTx, i, account, in, pkScripts[i], params); err != nil {
+			return nil, fmt.Errorf("input %d: %v", i, err)
+		}
+	}
+
+	for i, pkScript := range pkScripts {
+		vm, err := txscript.NewEngine(pkScript, msgTx, i, signedTxVerifyFlags, 0, nil)
+		if err == nil {
+			err = vm.Execute()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %v", i, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := msgTx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return &SignedTx{
+		Network: tx.Network,
+		Hash:    msgTx.TxHash().String(),
+		Tx:      hex.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// findAccountKey derives the accounts of master under the SLIP0044 and legacy
+// coin types and returns the one whose extended public key is xpub.
+func findAccountKey(master *hdkeychain.ExtendedKey, params *chaincfg.Params, xpub string) (*hdkeychain.ExtendedKey, error) {
+	purpose, err := master.Child(44 + hdkeychain.HardenedKeyStart)
+	if err != nil {
+		return nil, err
+	}
+	defer purpose.Zero()
+
+	for _, coinType := range []uint32{params.SLIP0044CoinType, params.LegacyCoinType} {
+		coin, err := purpose.Child(coinType + hdkeychain.HardenedKeyStart)
+		if err != nil {
+			return nil, err
+		}
+		for i := uint32(0); i < maxSignerAccounts; i++ {
+			account, err := coin.Child(i + hdkeychain.HardenedKeyStart)
+			if err != nil {
+				continue
+			}
+			if account.Neuter().String() == xpub {
+				coin.Zero()
+				return account, nil
+			}
+			account.Zero()
+		}
+		coin.Zero()
+	}
+	return nil, ErrSeedMismatch
+}
+
+// checkChange returns ErrChangeMismatch unless out pays the address at index
+// of the internal branch of account.
+func checkChange(out *wire.TxOut, account *hdkeychain.ExtendedKey, index uint32, params *chaincfg.Params) error {
+	branch, err := account.Child(internalBranch)
+	if err != nil {
+		return err
+	}
+	defer branch.Zero()
+	key, err := branch.Child(index)
+	if err != nil {
+		return err
+	}
+	defer key.Zero()
+
+	addr, err := pubKeyHashAddress(key.SerializedPubKey(), params)
+	if err != nil {
+		return err
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(script, out.PkScript) {
+		return ErrChangeMismatch
+	}
+	return nil
+}
+
+// signInput signs input i of msgTx with the key of account at the path of
+// in, after checking that pkScript pays that key.
+func signInput(msgTx *wire.MsgTx, i int, account *hdkeychain.ExtendedKey, in UnsignedTxInput, pkScript []byte, params *chaincfg.Params) error {
+	branch, err := account.Child(in.Branch)
+	if err != nil {
+		return err
+	}
+	defer branch.Zero()
+	key, err := branch.Child(in.Index)
+	if err != nil {
+		return err
+	}
+	defer key.Zero()
+
+	addr, err := pubKeyHashAddress(key.SerializedPubKey(), params)
+	if err != nil {
+		return err
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(script, pkScript) {
+		return fmt.Errorf("the output is not paid to key %d/%d of the account", in.Branch, in.Index)
+	}
+
+	privKey, err := key.SerializedPrivKey()
+	if err != nil {
+		return err
+	}
+	defer zero(privKey)
+	sigScript, err := txscript.SignatureScript(msgTx, i, pkScript, txscript.SigHashAll, privKey, dcrec.STEcdsaSecp256k1, true)
+	if err != nil {
+		return err
+	}
+	msgTx.TxIn[i].SignatureScript = sigScript
+	return nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// MsgTx decodes the signed transaction and checks its hash.
+func (tx *SignedTx) MsgTx() (*wire.MsgTx, error) {
+	b, err := hex.DecodeString(tx.Tx)
+	if err != nil {
+		return nil, err
+	}
+	msgTx := new(wire.MsgTx)
+	if err := msgTx.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	if msgTx.TxHash().String() != tx.Hash {
+		return nil, errors.New("the signed transaction does not match its hash")
+	}
+	return msgTx, nil
+}
+
+// Write writes the signed transaction as JSON.
+func (tx *SignedTx) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tx)
+}
+
+// ReadSignedTx reads a signed transaction written by Write.
+func ReadSignedTx(r io.Reader) (*SignedTx, error) {
+	tx := new(SignedTx)
+	if err := json.NewDecoder(r).Decode(tx); err != nil {
+		return nil, err
+	}
+	if _, err := tx.MsgTx(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ExportSignedTxFile writes the signed transaction to the exports directory
+// and returns the path of the file.
+func (wal *Wallet) ExportSignedTxFile(tx *SignedTx) (string, error) {
+	return wal.exportTxFile("signed-tx", tx.Write)
+}
+
+// ReadSignedTxFile reads a signed transaction exported by ExportSignedTxFile.
+func ReadSignedTxFile(path string) (*SignedTx, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadSignedTx(file)
+}
+
+// BroadcastSignedTxCtx publishes a signed transaction of the watch-only wallet
+// identified by walletID through wal.Dcrdata.
+// It blocks until the transaction is published or ctx is canceled.
+func (wal *Wallet) BroadcastSignedTxCtx(ctx context.Context, walletID int, tx *SignedTx) (*Broadcast, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	if tx.Network != w.NetType() {
+		return nil, fmt.Errorf("the transaction is for %s, not %s", tx.Network, w.NetType())
+	}
+	if _, err := tx.MsgTx(); err != nil {
+		return nil, err
+	}
+
+	hash, err := wal.Dcrdata.PublishTx(ctx, tx.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("error broadcasting transaction: %v", err)
+	}
+	if hash != tx.Hash {
+		return nil, fmt.Errorf("dcrdata returned hash %s for transaction %s", hash, tx.Hash)
+	}
+	return &Broadcast{TxHash: hash}, nil
+}
+
+// BroadcastSignedTx publishes a signed transaction of a watch-only wallet.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) BroadcastSignedTx(walletID int, tx *SignedTx) RequestID {
+	req := wal.newRequest(OpBroadcastSignedTx)
+	go func() {
+		broadcast, err := wal.BroadcastSignedTxCtx(context.Background(), walletID, tx)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(broadcast)
+	}()
+	return req.ID
+}
@@ -0,0 +1,154 @@
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"decred.org/dcrwallet/walletseed"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+// coldUnsignedTx returns a transaction spending an output paid to the
+// address at index of branch of account 0 of coldSeed, and paying 4 DCR to
+// an address and change to the internal branch.
+func coldUnsignedTx(branch, index uint32) *UnsignedTx {
+	pkScript, err := txscript.PayToAddrScript(coldAddress(branch, index))
+	Expect(err).To(BeNil())
+	payTo, err := txscript.PayToAddrScript(coldAddress(0, 9))
+	Expect(err).To(BeNil())
+	change, err := txscript.PayToAddrScript(coldAddress(1, 0))
+	Expect(err).To(BeNil())
+
+	hash := chainhash.HashH([]byte("cold"))
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hash, 0, wire.TxTreeRegular), 5e8, nil))
+	msgTx.AddTxOut(wire.NewTxOut(4e8, payTo))
+	msgTx.AddTxOut(wire.NewTxOut(1e8-3000, change))
+	var buf bytes.Buffer
+	Expect(msgTx.Serialize(&buf)).To(Succeed())
+
+	return &UnsignedTx{
+		Network:     "testnet3",
+		AccountXpub: coldAccount(0).Neuter().String(),
+		Tx:          hex.EncodeToString(buf.Bytes()),
+		Inputs: []UnsignedTxInput{{
+			TxID:         hash.String(),
+			ScriptPubKey: hex.EncodeToString(pkScript),
+			Amount:       5e8,
+			Branch:       branch,
+			Index:        index,
+		}},
+		ChangeIndex: 1,
+	}
+}
+
+var _ = Describe("Offline signing", func() {
+	seedWords := walletseed.EncodeMnemonic(coldSeed)
+
+	It("signs unsigned transactions with the seed of the account", func() {
+		tx := coldUnsignedTx(0, 3)
+		outputs, err := tx.Outputs()
+		Expect(err).To(BeNil())
+		Expect(outputs).To(Equal([]UnsignedTxOutput{
+			{Address: coldAddress(0, 9).Address(), Amount: 4e8},
+			{Address: coldAddress(1, 0).Address(), Amount: 1e8 - 3000, Change: true},
+		}))
+		fee, err := tx.Fee()
+		Expect(err).To(BeNil())
+		Expect(fee).To(Equal(int64(3000)))
+
+		signed, err := SignUnsignedTx(tx, seedWords)
+		Expect(err).To(BeNil())
+		msgTx, err := signed.MsgTx()
+		Expect(err).To(BeNil())
+		Expect(msgTx.TxIn[0].SignatureScript).NotTo(BeEmpty())
+		Expect(signed.Hash).To(Equal(msgTx.TxHash().String()))
+
+		path, err := wal.ExportSignedTxFile(signed)
+		Expect(err).To(BeNil())
+		read, err := ReadSignedTxFile(path)
+		Expect(err).To(BeNil())
+		Expect(read).To(Equal(signed))
+	})
+
+	It("refuses keys that do not pay the inputs", func() {
+		other := walletseed.EncodeMnemonic(bytes.Repeat([]byte{8}, 32))
+		_, err := SignUnsignedTx(coldUnsignedTx(0, 3), other)
+		Expect(err).To(Equal(ErrSeedMismatch))
+
+		tx := coldUnsignedTx(0, 3)
+		tx.Inputs[0].Index = 4
+		_, err = SignUnsignedTx(tx, seedWords)
+		Expect(err).To(MatchError(ContainSubstring("not paid to key 0/4")))
+
+		_, err = SignUnsignedTx(coldUnsignedTx(1, 2), "not a seed")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("refuses change outputs that do not pay the account", func() {
+		// A tampered file listing the payment as change would hide it
+		// from the outputs shown as paid.
+		tx := coldUnsignedTx(0, 3)
+		tx.ChangeIndex = 0
+		_, err := SignUnsignedTx(tx, seedWords)
+		Expect(err).To(Equal(ErrChangeMismatch))
+
+		tx = coldUnsignedTx(0, 3)
+		tx.ChangeAddressIndex = 1
+		_, err = SignUnsignedTx(tx, seedWords)
+		Expect(err).To(Equal(ErrChangeMismatch))
+
+		tx = coldUnsignedTx(0, 3)
+		tx.ChangeIndex = 2
+		_, err = tx.Outputs()
+		Expect(err).To(Equal(ErrChangeMismatch))
+		_, err = SignUnsignedTx(tx, seedWords)
+		Expect(err).To(Equal(ErrChangeMismatch))
+	})
+
+	It("broadcasts signed transactions through dcrdata", func() {
+		signed, err := SignUnsignedTx(coldUnsignedTx(0, 3), seedWords)
+		Expect(err).To(BeNil())
+
+		reply := fmt.Sprintf(`{"txid":"%s"}`, signed.Hash)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/insight/api/tx/send"))
+			if reply == "" {
+				http.Error(w, "transaction already exists", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, reply)
+		}))
+		defer server.Close()
+		dcrdata := wal.Dcrdata
+		wal.Dcrdata = NewDcrdataClient(server.URL, nil)
+		defer func() {
+			wal.Dcrdata = dcrdata
+		}()
+
+		ctx := context.Background()
+		broadcast, err := wal.BroadcastSignedTxCtx(ctx, 1, signed)
+		Expect(err).To(BeNil())
+		Expect(broadcast.TxHash).To(Equal(signed.Hash))
+
+		reply = ""
+		_, err = wal.BroadcastSignedTxCtx(ctx, 1, signed)
+		Expect(err).To(MatchError(ContainSubstring("transaction already exists")))
+
+		_, err = wal.BroadcastSignedTxCtx(ctx, 99, signed)
+		Expect(err).To(Equal(ErrIDNotExist))
+		signed.Network = "mainnet"
+		_, err = wal.BroadcastSignedTxCtx(ctx, 1, signed)
+		Expect(err).NotTo(BeNil())
+	})
+})
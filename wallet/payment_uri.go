@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrutil"
+)
+
+// PaymentURIScheme is the URI scheme of Decred payment requests.
+const PaymentURIScheme = "decred"
+
+// ErrInvalidPaymentURI is returned when a payment request URI cannot be
+// parsed.
+var ErrInvalidPaymentURI = errors.New("invalid payment request")
+
+// PaymentRequest is a request to pay an address, encoded as a URI of the form
+// decred:<address>?amount=<DCR>&label=<label>&message=<message>.
+type PaymentRequest struct {
+	Address string
+	// Amount is the amount requested in atoms, or 0 to let the payer choose.
+	Amount  int64
+	Label   string
+	Message string
+}
+
+// URI encodes the payment request. Only the address is included if nothing
+// else is set.
+func (r PaymentRequest) URI() string {
+	var params []string
+	if r.Amount > 0 {
+		params = append(params, "amount="+strconv.FormatFloat(dcrutil.Amount(r.Amount).ToCoin(), 'f', -1, 64))
+	}
+	if r.Label != "" {
+		params = append(params, "label="+escapeURIParam(r.Label))
+	}
+	if r.Message != "" {
+		params = append(params, "message="+escapeURIParam(r.Message))
+	}
+
+	uri := PaymentURIScheme + ":" + r.Address
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri
+}
+
+// escapeURIParam escapes spaces as %20 rather than + since some wallets do not
+// decode + in payment URIs.
+func escapeURIParam(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// IsPaymentURI reports whether s looks like a payment request URI.
+func IsPaymentURI(s string) bool {
+	s = strings.TrimSpace(s)
+	return len(s) > len(PaymentURIScheme) &&
+		strings.EqualFold(s[:len(PaymentURIScheme)+1], PaymentURIScheme+":")
+}
+
+// ParsePaymentURI parses a payment request URI. The scheme is matched case
+// insensitively and unknown parameters are ignored, except those prefixed
+// with req- which the payer is required to understand.
+func ParsePaymentURI(uri string) (PaymentRequest, error) {
+	var req PaymentRequest
+	if !IsPaymentURI(uri) {
+		return req, ErrInvalidPaymentURI
+	}
+
+	rest := strings.TrimPrefix(strings.TrimSpace(uri)[len(PaymentURIScheme)+1:], "//")
+	address, query := rest, ""
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		address, query = rest[:i], rest[i+1:]
+	}
+	if _, ok := AddressNetwork(address); !ok {
+		return req, fmt.Errorf("%w: bad address %q", ErrInvalidPaymentURI, address)
+	}
+	req.Address = address
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return req, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+	}
+	for key, values := range params {
+		value := values[0]
+		switch key {
+		case "amount":
+			dcr, err := strconv.ParseFloat(value, 64)
+			if err != nil || dcr < 0 {
+				return req, fmt.Errorf("%w: bad amount %q", ErrInvalidPaymentURI, value)
+			}
+			amount, err := dcrutil.NewAmount(dcr)
+			if err != nil {
+				return req, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+			}
+			req.Amount = int64(amount)
+		case "label":
+			req.Label = value
+		case "message":
+			req.Message = value
+		default:
+			if strings.HasPrefix(key, "req-") {
+				return req, fmt.Errorf("%w: unsupported parameter %q", ErrInvalidPaymentURI, key)
+			}
+		}
+	}
+	return req, nil
+}
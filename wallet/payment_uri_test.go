@@ -0,0 +1,47 @@
+package wallet_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Payment URIs", func() {
+	const addr = "TsfDLrRkk9ciUuwfp2b8PawwnukYD7yAjGd"
+
+	It("round-trips payment requests", func() {
+		requests := []PaymentRequest{
+			{Address: addr},
+			{Address: addr, Amount: 150000000},
+			{Address: addr, Amount: 1, Label: "Alice & Bob", Message: "50% deposit for invoice #12?"},
+		}
+		for _, req := range requests {
+			parsed, err := ParsePaymentURI(req.URI())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(req))
+		}
+
+		Expect(PaymentRequest{Address: addr}.URI()).To(Equal("decred:" + addr))
+		Expect(PaymentRequest{Address: addr, Amount: 150000000, Label: "a b"}.URI()).
+			To(Equal("decred:" + addr + "?amount=1.5&label=a%20b"))
+	})
+
+	It("parses URIs from other wallets", func() {
+		req, err := ParsePaymentURI(" DECRED://" + addr + "?message=thanks+a+lot&amount=0.1&foo=bar ")
+		Expect(err).To(BeNil())
+		Expect(req).To(Equal(PaymentRequest{Address: addr, Amount: 10000000, Message: "thanks a lot"}))
+
+		Expect(IsPaymentURI(addr)).To(BeFalse())
+		for _, uri := range []string{
+			addr,
+			"decred:",
+			"decred:notanaddress",
+			"decred:" + addr + "?amount=-1",
+			"decred:" + addr + "?amount=abc",
+			"decred:" + addr + "?req-expires=1",
+		} {
+			_, err := ParsePaymentURI(uri)
+			Expect(err).To(MatchError(ErrInvalidPaymentURI))
+		}
+	})
+})
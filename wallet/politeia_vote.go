@@ -0,0 +1,435 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+var (
+	// ErrVoteNotStarted is returned when the vote of a proposal has not
+	// started, so it has no vote options or eligible tickets yet.
+	ErrVoteNotStarted = errors.New("proposal voting has not started")
+
+	// ErrNotEnoughTickets is returned when more votes are cast than a wallet
+	// has eligible tickets that have not voted.
+	ErrNotEnoughTickets = errors.New("not enough eligible tickets to cast the votes")
+)
+
+// PoliteiaClient talks to the ticketvote API of a politeiawww server, which
+// dcrlibwallet's Politeia client does not cover.
+type PoliteiaClient struct {
+	host   string
+	client *http.Client
+
+	mtx       sync.Mutex
+	csrfToken string
+	cookies   []*http.Cookie
+}
+
+// NewPoliteiaClient returns a client for the politeiawww server at host,
+// e.g. dcrlibwallet.PoliteiaMainnetHost. A nil client uses a default client.
+func NewPoliteiaClient(host string, client *http.Client) *PoliteiaClient {
+	return &PoliteiaClient{host: strings.TrimSuffix(host, "/"), client: httpClient(client)}
+}
+
+// VoteDetails returns the vote options and eligible tickets of a proposal.
+func (c *PoliteiaClient) VoteDetails(ctx context.Context, token string) (*tkv1.VoteDetails, error) {
+	var reply tkv1.DetailsReply
+	if err := c.post(ctx, tkv1.RouteDetails, tkv1.Details{Token: token}, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Vote == nil {
+		return nil, ErrVoteNotStarted
+	}
+	return reply.Vote, nil
+}
+
+// VoteResults returns the votes cast on a proposal.
+func (c *PoliteiaClient) VoteResults(ctx context.Context, token string) ([]tkv1.CastVoteDetails, error) {
+	var reply tkv1.ResultsReply
+	if err := c.post(ctx, tkv1.RouteResults, tkv1.Results{Token: token}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Votes, nil
+}
+
+// CastBallot submits signed votes and returns a receipt for each of them.
+// Votes rejected by the server have the error code set on their receipt.
+func (c *PoliteiaClient) CastBallot(ctx context.Context, votes []tkv1.CastVote) ([]tkv1.CastVoteReply, error) {
+	var reply tkv1.CastBallotReply
+	if err := c.post(ctx, tkv1.RouteCastBallot, tkv1.CastBallot{Votes: votes}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Receipts, nil
+}
+
+// post sends a ticketvote command. politeiawww rejects POST requests without
+// the CSRF token and cookies handed out with its version reply, so they are
+// fetched first and kept until the server stops accepting them.
+func (c *PoliteiaClient) post(ctx context.Context, route string, body, dest interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	for retry := true; ; retry = false {
+		if err := c.fetchCSRF(ctx, false); err != nil {
+			return err
+		}
+
+		url := c.host + "/api" + tkv1.APIRoute + route
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		c.mtx.Lock()
+		req.Header.Set(www.CsrfToken, c.csrfToken)
+		for _, cookie := range c.cookies {
+			req.AddCookie(cookie)
+		}
+		c.mtx.Unlock()
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode == http.StatusForbidden && retry {
+			res.Body.Close()
+			if err := c.fetchCSRF(ctx, true); err != nil {
+				return err
+			}
+			continue
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			var userErr tkv1.UserErrorReply
+			if res.StatusCode == http.StatusBadRequest && json.NewDecoder(res.Body).Decode(&userErr) == nil {
+				return fmt.Errorf("%s: %s %s", url, tkv1.ErrorCodes[userErr.ErrorCode], userErr.ErrorContext)
+			}
+			return fmt.Errorf("%s: %s", url, res.Status)
+		}
+		return json.NewDecoder(res.Body).Decode(dest)
+	}
+}
+
+// fetchCSRF gets a CSRF token from the version route if the client has none
+// or force is set.
+func (c *PoliteiaClient) fetchCSRF(ctx context.Context, force bool) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.csrfToken != "" && !force {
+		return nil
+	}
+
+	url := c.host + "/api" + www.PoliteiaWWWAPIRoute + www.RouteVersion
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, res.Status)
+	}
+	c.csrfToken = res.Header.Get(www.CsrfToken)
+	c.cookies = res.Cookies()
+	return nil
+}
+
+// ProposalVoteOption is a choice tickets can vote for on a proposal.
+type ProposalVoteOption struct {
+	ID          string
+	Description string
+	Bit         uint64
+}
+
+// ProposalTicket is a ticket of a wallet that is eligible to vote on a
+// proposal.
+type ProposalTicket struct {
+	WalletID int
+	Hash     string
+	// Address is the commitment address that signs the ticket's vote.
+	Address string
+	// Vote is the ID of the option the ticket voted for, or "" if it has
+	// not voted.
+	Vote string
+}
+
+// ProposalVote is the vote of a proposal with the tickets of the loaded
+// wallets that can take part in it.
+type ProposalVote struct {
+	Token            string
+	Options          []ProposalVoteOption
+	StartBlockHeight uint32
+	EndBlockHeight   uint32
+	EligibleTickets  int
+	Tickets          []ProposalTicket
+}
+
+// Unvoted returns the tickets of a wallet that have not voted yet.
+func (v *ProposalVote) Unvoted(walletID int) []ProposalTicket {
+	var tickets []ProposalTicket
+	for _, ticket := range v.Tickets {
+		if ticket.WalletID == walletID && ticket.Vote == "" {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// Voted returns the number of tickets of a wallet that voted for each option.
+func (v *ProposalVote) Voted(walletID int) map[string]int {
+	voted := make(map[string]int)
+	for _, ticket := range v.Tickets {
+		if ticket.WalletID == walletID && ticket.Vote != "" {
+			voted[ticket.Vote]++
+		}
+	}
+	return voted
+}
+
+// ProposalVotesCast is sent when votes were cast on a proposal.
+type ProposalVotesCast struct {
+	Token string
+	Cast  int
+}
+
+// ProposalVoteCtx returns the vote of the proposal with token and the
+// eligible tickets of every wallet that can sign votes, with the option each
+// ticket voted for.
+func (wal *Wallet) ProposalVoteCtx(ctx context.Context, token string) (*ProposalVote, error) {
+	details, err := wal.Politeia.VoteDetails(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	results, err := wal.Politeia.VoteResults(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	vote := &ProposalVote{
+		Token:            token,
+		StartBlockHeight: details.StartBlockHeight,
+		EndBlockHeight:   details.EndBlockHeight,
+		EligibleTickets:  len(details.EligibleTickets),
+	}
+	optionIDs := make(map[string]string)
+	for _, option := range details.Params.Options {
+		vote.Options = append(vote.Options, ProposalVoteOption{
+			ID:          option.ID,
+			Description: option.Description,
+			Bit:         option.Bit,
+		})
+		optionIDs[strconv.FormatUint(option.Bit, 16)] = option.ID
+	}
+
+	eligible := make(map[string]bool, len(details.EligibleTickets))
+	for _, hash := range details.EligibleTickets {
+		eligible[hash] = true
+	}
+	votes := make(map[string]string, len(results))
+	for _, result := range results {
+		votes[result.Ticket] = optionIDs[strings.TrimLeft(strings.ToLower(result.VoteBit), "0")]
+	}
+
+	for _, w := range wal.multi.AllWallets() {
+		if w.IsWatchingOnlyWallet() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tickets, err := w.GetTicketsForBlockHeightRange(0, w.GetBestBlock(), math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range tickets {
+			hash := info.Ticket.Hash.String()
+			if !eligible[hash] {
+				continue
+			}
+			address, err := ticketCommitmentAddress(w, info.Ticket.Hash[:])
+			if err != nil {
+				log.Errorf("No commitment address for ticket %s: %v", hash, err)
+				continue
+			}
+			vote.Tickets = append(vote.Tickets, ProposalTicket{
+				WalletID: w.ID,
+				Hash:     hash,
+				Address:  address,
+				Vote:     votes[hash],
+			})
+		}
+	}
+	return vote, nil
+}
+
+// ProposalVote fetches the vote of the proposal with token.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) ProposalVote(token string) RequestID {
+	req := wal.newRequest(OpGetProposalVote)
+	go func() {
+		vote, err := wal.ProposalVoteCtx(context.Background(), token)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(vote)
+	}()
+	return req.ID
+}
+
+// ticketCommitmentAddress returns the commitment address of a ticket owned
+// by the wallet. Politeia checks vote signatures against the largest
+// commitment; wallet tickets have a single commitment to the wallet, so the
+// first one the wallet owns is used.
+func ticketCommitmentAddress(w *dcrlibwallet.Wallet, hash []byte) (string, error) {
+	tx, err := w.GetTransactionRaw(hash)
+	if err != nil {
+		return "", err
+	}
+	for _, output := range tx.Outputs {
+		// Commitments are the odd outputs of a ticket.
+		if output.Index%2 == 1 && output.ScriptType == "stakesubmission" && w.HaveAddress(output.Address) {
+			return output.Address, nil
+		}
+	}
+	return "", errors.New("ticket has no commitment to the wallet")
+}
+
+// CastProposalVotesCtx signs votes of the wallet's eligible tickets that have
+// not voted and submits them to Politeia. counts is the number of tickets to
+// vote with for each option ID. If some votes are rejected, the number cast
+// is returned with an error describing the rejected votes.
+func (wal *Wallet) CastProposalVotesCtx(ctx context.Context, walletID int, token string, counts map[string]int, passphrase []byte) (*ProposalVotesCast, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+
+	vote, err := wal.ProposalVoteCtx(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	bits := make(map[string]uint64, len(vote.Options))
+	for _, option := range vote.Options {
+		bits[option.ID] = option.Bit
+	}
+
+	tickets := vote.Unvoted(walletID)
+	total := 0
+	for id, count := range counts {
+		if _, ok := bits[id]; !ok {
+			return nil, fmt.Errorf("unknown vote option %q", id)
+		}
+		total += count
+	}
+	if total == 0 || total > len(tickets) {
+		return nil, ErrNotEnoughTickets
+	}
+
+	// Vote in the order of the options so the same tickets vote the same
+	// way every time.
+	var ids []string
+	for _, option := range vote.Options {
+		if counts[option.ID] > 0 {
+			ids = append(ids, option.ID)
+		}
+	}
+
+	votes := make([]tkv1.CastVote, 0, total)
+	for _, id := range ids {
+		voteBit := strconv.FormatUint(bits[id], 16)
+		for i := 0; i < counts[id]; i++ {
+			ticket := tickets[len(votes)]
+			msg := token + ticket.Hash + voteBit
+			pass := append([]byte(nil), passphrase...)
+			sig, err := w.SignMessage(pass, ticket.Address, msg)
+			if err != nil {
+				return nil, err
+			}
+			votes = append(votes, tkv1.CastVote{
+				Token:     token,
+				Ticket:    ticket.Hash,
+				VoteBit:   voteBit,
+				Signature: hex.EncodeToString(sig),
+			})
+		}
+	}
+
+	receipts, err := wal.Politeia.CastBallot(ctx, votes)
+	if err != nil {
+		return nil, err
+	}
+	cast := &ProposalVotesCast{Token: token}
+	var rejected []string
+	for _, receipt := range receipts {
+		if receipt.ErrorCode != tkv1.VoteErrorInvalid {
+			rejected = append(rejected, fmt.Sprintf("%s: %s", receipt.Ticket, voteError(receipt)))
+			continue
+		}
+		cast.Cast++
+	}
+	if len(rejected) > 0 {
+		return cast, fmt.Errorf("%d of %d votes rejected: %s", len(rejected), len(votes), strings.Join(rejected, "; "))
+	}
+	return cast, nil
+}
+
+// CastProposalVotes signs and submits votes of the wallet's tickets.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) CastProposalVotes(walletID int, token string, counts map[string]int, passphrase []byte) RequestID {
+	req := wal.newRequest(OpCastProposalVotes)
+	go func() {
+		cast, err := wal.CastProposalVotesCtx(context.Background(), walletID, token, counts, passphrase)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(cast)
+	}()
+	return req.ID
+}
+
+// voteErrors describes the errors Politeia sets on rejected votes.
+var voteErrors = map[tkv1.VoteErrorT]string{
+	tkv1.VoteErrorInternalError:       "internal server error",
+	tkv1.VoteErrorTokenInvalid:        "invalid proposal token",
+	tkv1.VoteErrorRecordNotFound:      "proposal not found",
+	tkv1.VoteErrorMultipleRecordVotes: "votes for several proposals",
+	tkv1.VoteErrorVoteStatusInvalid:   "proposal is not being voted on",
+	tkv1.VoteErrorVoteBitInvalid:      "invalid vote option",
+	tkv1.VoteErrorSignatureInvalid:    "invalid signature",
+	tkv1.VoteErrorTicketNotEligible:   "ticket not eligible",
+	tkv1.VoteErrorTicketAlreadyVoted:  "ticket already voted",
+}
+
+func voteError(receipt tkv1.CastVoteReply) string {
+	msg := voteErrors[receipt.ErrorCode]
+	if msg == "" {
+		msg = fmt.Sprintf("error %d", receipt.ErrorCode)
+	}
+	if receipt.ErrorContext != "" {
+		msg += " (" + receipt.ErrorContext + ")"
+	}
+	return msg
+}
@@ -0,0 +1,163 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Politeia voting", func() {
+	const (
+		token     = "e2bb1ad9b0a0ccf0"
+		unstarted = "0123456789abcdef"
+		ticketA   = "6f1a02bd0bd2d1ce0e9f2e7e6f3e60e3c2f2ab2bb2f1c0e1d8fbbd6d91c5d4a1"
+		ticketB   = "07c8bd3f7c6a4b4f4f0dbdf0c86c2c03e3d2b7a4e3a9ab08c8f5e2d7a6c3b2a1"
+	)
+
+	var (
+		server      *httptest.Server
+		csrf        int32
+		expireFirst int32
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&csrf, 0)
+		atomic.StoreInt32(&expireFirst, 0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v1/version" {
+				n := atomic.AddInt32(&csrf, 1)
+				w.Header().Set(www.CsrfToken, "csrf")
+				http.SetCookie(w, &http.Cookie{Name: "_gorilla_csrf", Value: string(rune('a' + n))})
+				json.NewEncoder(w).Encode(www.VersionReply{Version: 1})
+				return
+			}
+
+			Expect(r.Method).To(Equal(http.MethodPost))
+			cookie, err := r.Cookie("_gorilla_csrf")
+			if r.Header.Get(www.CsrfToken) != "csrf" || err != nil || cookie.Value == "b" && atomic.LoadInt32(&expireFirst) == 1 {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+
+			switch r.URL.Path {
+			case "/api/ticketvote/v1/details":
+				var req tkv1.Details
+				Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+				if req.Token != token {
+					json.NewEncoder(w).Encode(tkv1.DetailsReply{})
+					return
+				}
+				json.NewEncoder(w).Encode(tkv1.DetailsReply{Vote: &tkv1.VoteDetails{
+					Params: tkv1.VoteParams{Token: token, Options: []tkv1.VoteOption{
+						{ID: "yes", Description: "Approve", Bit: 2},
+						{ID: "no", Description: "Reject", Bit: 1},
+					}},
+					EndBlockHeight:  600000,
+					EligibleTickets: []string{ticketA, ticketB},
+				}})
+			case "/api/ticketvote/v1/results":
+				json.NewEncoder(w).Encode(tkv1.ResultsReply{Votes: []tkv1.CastVoteDetails{
+					{Token: token, Ticket: ticketA, VoteBit: "2"},
+				}})
+			case "/api/ticketvote/v1/castballot":
+				var req tkv1.CastBallot
+				Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+				reply := tkv1.CastBallotReply{}
+				for _, vote := range req.Votes {
+					receipt := tkv1.CastVoteReply{Ticket: vote.Ticket, Receipt: "receipt"}
+					if vote.Ticket == ticketA {
+						receipt = tkv1.CastVoteReply{Ticket: vote.Ticket, ErrorCode: tkv1.VoteErrorTicketAlreadyVoted}
+					}
+					reply.Receipts = append(reply.Receipts, receipt)
+				}
+				json.NewEncoder(w).Encode(reply)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("fetches vote details and results with a CSRF token", func() {
+		ctx := context.Background()
+		client := NewPoliteiaClient(server.URL, nil)
+
+		details, err := client.VoteDetails(ctx, token)
+		Expect(err).To(BeNil())
+		Expect(details.Params.Options).To(HaveLen(2))
+		Expect(details.EligibleTickets).To(ConsistOf(ticketA, ticketB))
+
+		results, err := client.VoteResults(ctx, token)
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(1))
+		Expect(atomic.LoadInt32(&csrf)).To(Equal(int32(1)))
+
+		_, err = client.VoteDetails(ctx, unstarted)
+		Expect(err).To(Equal(ErrVoteNotStarted))
+	})
+
+	It("refreshes the CSRF token when it is rejected", func() {
+		atomic.StoreInt32(&expireFirst, 1)
+		_, err := NewPoliteiaClient(server.URL, nil).VoteResults(context.Background(), token)
+		Expect(err).To(BeNil())
+		Expect(atomic.LoadInt32(&csrf)).To(Equal(int32(2)))
+	})
+
+	It("returns receipts for cast votes", func() {
+		receipts, err := NewPoliteiaClient(server.URL, nil).CastBallot(context.Background(), []tkv1.CastVote{
+			{Token: token, Ticket: ticketA, VoteBit: "2", Signature: "00"},
+			{Token: token, Ticket: ticketB, VoteBit: "1", Signature: "00"},
+		})
+		Expect(err).To(BeNil())
+		Expect(receipts).To(HaveLen(2))
+		Expect(receipts[0].ErrorCode).To(Equal(tkv1.VoteErrorTicketAlreadyVoted))
+		Expect(receipts[1].Receipt).To(Equal("receipt"))
+	})
+
+	It("only votes with eligible tickets of the wallet", func() {
+		ctx := context.Background()
+		wal.Politeia = NewPoliteiaClient(server.URL, nil)
+
+		vote, err := wal.ProposalVoteCtx(ctx, token)
+		Expect(err).To(BeNil())
+		Expect(vote.Options).To(Equal([]ProposalVoteOption{
+			{ID: "yes", Description: "Approve", Bit: 2},
+			{ID: "no", Description: "Reject", Bit: 1},
+		}))
+		Expect(vote.EligibleTickets).To(Equal(2))
+		Expect(vote.EndBlockHeight).To(Equal(uint32(600000)))
+		Expect(vote.Tickets).To(BeEmpty())
+		Expect(vote.Unvoted(1)).To(BeEmpty())
+
+		_, err = wal.CastProposalVotesCtx(ctx, 1, token, map[string]int{"yes": 1}, []byte("password"))
+		Expect(err).To(Equal(ErrNotEnoughTickets))
+		_, err = wal.CastProposalVotesCtx(ctx, 1, token, map[string]int{"abstain": 1}, []byte("password"))
+		Expect(err).To(MatchError(`unknown vote option "abstain"`))
+		_, err = wal.CastProposalVotesCtx(ctx, 99, token, map[string]int{"yes": 1}, []byte("password"))
+		Expect(err).To(Equal(ErrIDNotExist))
+		_, err = wal.ProposalVoteCtx(ctx, unstarted)
+		Expect(err).To(Equal(ErrVoteNotStarted))
+	})
+
+	It("counts the votes of each wallet", func() {
+		vote := &ProposalVote{Tickets: []ProposalTicket{
+			{WalletID: 1, Hash: ticketA, Vote: "yes"},
+			{WalletID: 1, Hash: ticketB},
+			{WalletID: 2, Hash: "c", Vote: "no"},
+		}}
+		Expect(vote.Unvoted(1)).To(Equal([]ProposalTicket{{WalletID: 1, Hash: ticketB}}))
+		Expect(vote.Voted(1)).To(Equal(map[string]int{"yes": 1}))
+		Expect(vote.Voted(2)).To(Equal(map[string]int{"no": 1}))
+	})
+})
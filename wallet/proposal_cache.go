@@ -0,0 +1,192 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+// proposalCacheDirName is the directory in the app data folder proposal
+// descriptions are cached in, one file per token and version.
+const proposalCacheDirName = "proposals"
+
+// maxDiffCells bounds the work of a line diff. Larger texts are shown as
+// entirely replaced.
+const maxDiffCells = 4000000
+
+// Kinds of DiffLine.
+const (
+	DiffSame = iota
+	DiffAdded
+	DiffRemoved
+)
+
+// DiffLine is a line of a diff between two texts.
+type DiffLine struct {
+	Kind int
+	Text string
+}
+
+// ProposalDiff holds the changes of a proposal's description between two
+// versions.
+type ProposalDiff struct {
+	Token    string
+	From, To string
+	Lines    []DiffLine
+}
+
+func (wal *Wallet) proposalCachePath(token, version string) string {
+	return filepath.Join(wal.root, proposalCacheDirName, filepath.Base(token), filepath.Base(version)+".md")
+}
+
+// cacheProposalDescription stores the description of a proposal version.
+// Failing to cache it is not an error for callers that have the description.
+func (wal *Wallet) cacheProposalDescription(token, version, description string) {
+	path := wal.proposalCachePath(token, version)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Errorf("Error caching proposal %s: %v", token, err)
+		return
+	}
+	if err := ioutil.WriteFile(path, []byte(description), 0600); err != nil {
+		log.Errorf("Error caching proposal %s: %v", token, err)
+	}
+}
+
+// CachedProposalDescription returns the cached description of a proposal
+// version.
+func (wal *Wallet) CachedProposalDescription(token, version string) (string, error) {
+	b, err := ioutil.ReadFile(wal.proposalCachePath(token, version))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ProposalDescription returns the description of the current version of a
+// proposal. It is read from the cache when possible and fetched from
+// Politeia otherwise, and cached so it can be read offline.
+func (wal *Wallet) ProposalDescription(p dcrlibwallet.Proposal) (string, error) {
+	if description, err := wal.CachedProposalDescription(p.Token, p.Version); err == nil {
+		return description, nil
+	}
+
+	var description string
+	if p.IndexFile != "" && p.IndexFileVersion == p.Version {
+		description = p.IndexFile
+	} else {
+		var err error
+		description, err = wal.FetchProposalDescription(p.Token)
+		if err != nil {
+			return "", err
+		}
+	}
+	wal.cacheProposalDescription(p.Token, p.Version, description)
+	return description, nil
+}
+
+// CachedProposalVersions returns the versions of a proposal whose
+// description is cached, oldest first.
+func (wal *Wallet) CachedProposalVersions(token string) []string {
+	files, err := ioutil.ReadDir(filepath.Join(wal.root, proposalCacheDirName, filepath.Base(token)))
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), ".md") {
+			versions = append(versions, strings.TrimSuffix(f.Name(), ".md"))
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		a, errA := strconv.Atoi(versions[i])
+		b, errB := strconv.Atoi(versions[j])
+		if errA != nil || errB != nil {
+			return versions[i] < versions[j]
+		}
+		return a < b
+	})
+	return versions
+}
+
+// ProposalDiff returns the changes of a proposal's description from one
+// cached version to another.
+func (wal *Wallet) ProposalDiff(token, from, to string) (*ProposalDiff, error) {
+	old, err := wal.CachedProposalDescription(token, from)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := wal.CachedProposalDescription(token, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ProposalDiff{
+		Token: token,
+		From:  from,
+		To:    to,
+		Lines: DiffLines(old, updated),
+	}, nil
+}
+
+// DiffLines returns the lines removed from old and added in updated, in order,
+// with the lines they have in common.
+func DiffLines(old, updated string) []DiffLine {
+	a := strings.Split(old, "\n")
+	b := strings.Split(updated, "\n")
+
+	var lines []DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, DiffLine{Kind: DiffRemoved, Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{Kind: DiffAdded, Text: line})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Kind: DiffSame, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Kind: DiffRemoved, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Kind: DiffAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Kind: DiffRemoved, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Kind: DiffAdded, Text: b[j]})
+	}
+	return lines
+}
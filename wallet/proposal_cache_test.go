@@ -0,0 +1,57 @@
+package wallet_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Proposal description cache", func() {
+	It("diffs lines", func() {
+		lines := DiffLines("a\nb\nc\nd", "a\nc\nx\nd\ne")
+		Expect(lines).To(Equal([]DiffLine{
+			{Kind: DiffSame, Text: "a"},
+			{Kind: DiffRemoved, Text: "b"},
+			{Kind: DiffSame, Text: "c"},
+			{Kind: DiffAdded, Text: "x"},
+			{Kind: DiffSame, Text: "d"},
+			{Kind: DiffAdded, Text: "e"},
+		}))
+		Expect(DiffLines("same", "same")).To(Equal([]DiffLine{{Kind: DiffSame, Text: "same"}}))
+	})
+
+	It("caches each version of a description", func() {
+		proposal := dcrlibwallet.Proposal{
+			Token:            "cachedtoken",
+			Version:          "2",
+			IndexFile:        "# Title\nsecond",
+			IndexFileVersion: "2",
+		}
+		description, err := wal.ProposalDescription(proposal)
+		Expect(err).To(BeNil())
+		Expect(description).To(Equal("# Title\nsecond"))
+
+		proposal.Version, proposal.IndexFileVersion, proposal.IndexFile = "10", "10", "# Title\ntenth"
+		_, err = wal.ProposalDescription(proposal)
+		Expect(err).To(BeNil())
+
+		// Cached versions are read without the index file.
+		proposal.IndexFile = ""
+		description, err = wal.ProposalDescription(proposal)
+		Expect(err).To(BeNil())
+		Expect(description).To(Equal("# Title\ntenth"))
+
+		Expect(wal.CachedProposalVersions("cachedtoken")).To(Equal([]string{"2", "10"}))
+		diff, err := wal.ProposalDiff("cachedtoken", "2", "10")
+		Expect(err).To(BeNil())
+		Expect(diff.Lines).To(Equal([]DiffLine{
+			{Kind: DiffSame, Text: "# Title"},
+			{Kind: DiffRemoved, Text: "second"},
+			{Kind: DiffAdded, Text: "tenth"},
+		}))
+
+		_, err = wal.ProposalDiff("cachedtoken", "1", "10")
+		Expect(err).NotTo(BeNil())
+	})
+})
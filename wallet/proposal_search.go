@@ -0,0 +1,164 @@
+package wallet
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+// proposalBookmarksConfigKey lists the tokens of bookmarked proposals.
+const proposalBookmarksConfigKey = "proposal_bookmarks"
+
+// ProposalFilter selects proposals. Zero fields match every proposal.
+type ProposalFilter struct {
+	// Query holds words that must all appear in the title or description.
+	Query  string
+	Author string
+	// From and To bound the time the proposal was published.
+	From, To time.Time
+	// Category is one of the dcrlibwallet.ProposalCategory values.
+	Category       int32
+	BookmarkedOnly bool
+}
+
+// queryWords returns the lower case words of the query.
+func (f ProposalFilter) queryWords() []string {
+	return strings.Fields(strings.ToLower(f.Query))
+}
+
+// MatchesMetadata reports whether the proposal matches all parts of the
+// filter but the query and bookmarks.
+func (f ProposalFilter) MatchesMetadata(p dcrlibwallet.Proposal) bool {
+	if f.Category != 0 && f.Category != dcrlibwallet.ProposalCategoryAll && p.Category != f.Category {
+		return false
+	}
+	if f.Author != "" && !strings.Contains(strings.ToLower(p.Username), strings.ToLower(f.Author)) {
+		return false
+	}
+	published := time.Unix(p.PublishedAt, 0)
+	if !f.From.IsZero() && published.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !published.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// MatchesQuery reports whether every word of the query appears in the title
+// or description of the proposal.
+func (f ProposalFilter) MatchesQuery(p dcrlibwallet.Proposal, description string) bool {
+	text := strings.ToLower(p.Name + "\n" + description)
+	for _, word := range f.queryWords() {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchProposalsCtx returns the proposals that match filter, newest first.
+// Descriptions are only read for proposals whose title does not match the
+// query; proposals whose description cannot be read are matched on their
+// title alone.
+// It blocks until all proposals are searched or ctx is canceled.
+func (wal *Wallet) SearchProposalsCtx(ctx context.Context, filter ProposalFilter) (*Proposals, error) {
+	proposals, err := wal.multi.Politeia.GetProposalsRaw(dcrlibwallet.ProposalCategoryAll, 0, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := wal.ProposalBookmarks()
+	result := &Proposals{}
+	for _, p := range proposals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !filter.MatchesMetadata(p) || (filter.BookmarkedOnly && !bookmarks[p.Token]) {
+			continue
+		}
+		if !filter.MatchesQuery(p, "") {
+			description, err := wal.ProposalDescription(p)
+			if err != nil || !filter.MatchesQuery(p, description) {
+				continue
+			}
+		}
+		result.Proposals = append(result.Proposals, p)
+	}
+	return result, nil
+}
+
+// SearchProposals returns the proposals that match filter, newest first.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) SearchProposals(filter ProposalFilter) RequestID {
+	req := wal.newRequest(OpSearchProposals)
+	go func() {
+		proposals, err := wal.SearchProposalsCtx(context.Background(), filter)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(proposals)
+	}()
+	return req.ID
+}
+
+// ProposalBookmarks returns the tokens of the bookmarked proposals.
+func (wal *Wallet) ProposalBookmarks() map[string]bool {
+	wal.bookmarksMtx.Lock()
+	defer wal.bookmarksMtx.Unlock()
+
+	var tokens []string
+	wal.multi.ReadUserConfigValue(proposalBookmarksConfigKey, &tokens)
+	bookmarks := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		bookmarks[token] = true
+	}
+	return bookmarks
+}
+
+// IsProposalBookmarked reports whether the proposal with token is bookmarked.
+func (wal *Wallet) IsProposalBookmarked(token string) bool {
+	return wal.ProposalBookmarks()[token]
+}
+
+// ProposalVoteNotice reports that voting started or finished on a
+// bookmarked proposal.
+type ProposalVoteNotice struct {
+	Name     string
+	Finished bool
+}
+
+// NotifyProposalVote sends a ProposalVoteNotice to wal.Send when voting
+// started or finished on proposal and it is bookmarked. It is non-blocking
+// so it may be called from politeia notification listeners.
+func (wal *Wallet) NotifyProposalVote(proposal *dcrlibwallet.Proposal, finished bool) {
+	if !wal.IsProposalBookmarked(proposal.Token) {
+		return
+	}
+	req := wal.newRequest(OpProposalVoteNotice)
+	go func() {
+		wal.Send <- req.response(&ProposalVoteNotice{Name: proposal.Name, Finished: finished})
+	}()
+}
+
+// BookmarkProposal adds or removes the bookmark of the proposal with token.
+func (wal *Wallet) BookmarkProposal(token string, bookmark bool) {
+	wal.bookmarksMtx.Lock()
+	defer wal.bookmarksMtx.Unlock()
+
+	var tokens []string
+	wal.multi.ReadUserConfigValue(proposalBookmarksConfigKey, &tokens)
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if t != token {
+			kept = append(kept, t)
+		}
+	}
+	if bookmark {
+		kept = append(kept, token)
+	}
+	wal.multi.SaveUserConfigValue(proposalBookmarksConfigKey, kept)
+}
@@ -0,0 +1,72 @@
+package wallet_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Proposal search", func() {
+	proposal := dcrlibwallet.Proposal{
+		Token:       "abc",
+		Name:        "Decred Marketing Proposal",
+		Username:    "Alice",
+		Category:    dcrlibwallet.ProposalCategoryActive,
+		PublishedAt: time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	It("matches proposals on their metadata", func() {
+		Expect(ProposalFilter{}.MatchesMetadata(proposal)).To(BeTrue())
+		Expect(ProposalFilter{Author: "ali"}.MatchesMetadata(proposal)).To(BeTrue())
+		Expect(ProposalFilter{Author: "bob"}.MatchesMetadata(proposal)).To(BeFalse())
+		Expect(ProposalFilter{Category: dcrlibwallet.ProposalCategoryAll}.MatchesMetadata(proposal)).To(BeTrue())
+		Expect(ProposalFilter{Category: dcrlibwallet.ProposalCategoryApproved}.MatchesMetadata(proposal)).To(BeFalse())
+
+		march := ProposalFilter{
+			From: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+		}
+		Expect(march.MatchesMetadata(proposal)).To(BeTrue())
+		april := ProposalFilter{From: march.To}
+		Expect(april.MatchesMetadata(proposal)).To(BeFalse())
+	})
+
+	It("matches every word of the query", func() {
+		Expect(ProposalFilter{Query: "marketing"}.MatchesQuery(proposal, "")).To(BeTrue())
+		Expect(ProposalFilter{Query: "marketing budget"}.MatchesQuery(proposal, "")).To(BeFalse())
+		Expect(ProposalFilter{Query: "marketing budget"}.MatchesQuery(proposal, "The Budget is 10k")).To(BeTrue())
+	})
+
+	It("bookmarks proposals", func() {
+		Expect(wal.IsProposalBookmarked("abc")).To(BeFalse())
+		wal.BookmarkProposal("abc", true)
+		wal.BookmarkProposal("def", true)
+		wal.BookmarkProposal("abc", true)
+		Expect(wal.ProposalBookmarks()).To(Equal(map[string]bool{"abc": true, "def": true}))
+
+		wal.BookmarkProposal("abc", false)
+		wal.BookmarkProposal("def", false)
+		Expect(wal.ProposalBookmarks()).To(BeEmpty())
+	})
+
+	It("notifies votes on bookmarked proposals through wal.Send", func() {
+		wal.BookmarkProposal("abc", true)
+		defer wal.BookmarkProposal("abc", false)
+
+		wal.NotifyProposalVote(&dcrlibwallet.Proposal{Token: "def", Name: "Other"}, false)
+		wal.NotifyProposalVote(&dcrlibwallet.Proposal{Token: "abc", Name: "Marketing"}, true)
+		resp := <-wal.Send
+		Expect(resp.Op).To(Equal(OpProposalVoteNotice))
+		Expect(resp.Resp).To(Equal(&ProposalVoteNotice{Name: "Marketing", Finished: true}))
+	})
+
+	It("finds nothing before proposals are synced", func() {
+		proposals, err := wal.SearchProposalsCtx(context.Background(), ProposalFilter{Query: "decred"})
+		Expect(err).To(BeNil())
+		Expect(proposals.Proposals).To(BeEmpty())
+	})
+})
@@ -0,0 +1,410 @@
+package wallet
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+// rateHistoryFileName is the file in the app data directory daily rates are
+// stored in.
+const rateHistoryFileName = "fiat_rates.json"
+
+// dayFormat is the format of the days rates are stored by.
+const dayFormat = "2006-01-02"
+
+// DailyRate is the price of one DCR in a fiat currency on a UTC day.
+type DailyRate struct {
+	Day  time.Time
+	Rate float64
+}
+
+// HistoricalRateSource fetches past daily DCR prices.
+type HistoricalRateSource interface {
+	Name() string
+	// FetchDailyRates returns the DCR price in currency for the UTC days
+	// from the day of from to the day of to.
+	FetchDailyRates(ctx context.Context, currency string, from, to time.Time) ([]DailyRate, error)
+}
+
+// RateHistory is a local store of daily DCR exchange rates, keyed by currency
+// and UTC day. It is safe for concurrent use.
+type RateHistory struct {
+	path string
+
+	mtx   sync.RWMutex
+	rates map[string]map[string]float64
+}
+
+// OpenRateHistory loads the rate history stored at path. A new history is
+// returned if the file does not exist yet.
+func OpenRateHistory(path string) (*RateHistory, error) {
+	h := &RateHistory{
+		path:  path,
+		rates: make(map[string]map[string]float64),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &h.rates); err != nil {
+		return nil, fmt.Errorf("invalid rate history %s: %v", path, err)
+	}
+	return h, nil
+}
+
+func dayKey(t time.Time) string {
+	return t.UTC().Format(dayFormat)
+}
+
+// Rate returns the rate of currency on the UTC day of t.
+func (h *RateHistory) Rate(currency string, t time.Time) (float64, bool) {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	rate, ok := h.rates[currency][dayKey(t)]
+	return rate, ok
+}
+
+// Latest returns the most recent daily rate stored for currency.
+func (h *RateHistory) Latest(currency string) (DailyRate, bool) {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+
+	var latest string
+	for day := range h.rates[currency] {
+		if day > latest {
+			latest = day
+		}
+	}
+	if latest == "" {
+		return DailyRate{}, false
+	}
+
+	day, _ := time.Parse(dayFormat, latest)
+	return DailyRate{Day: day, Rate: h.rates[currency][latest]}, true
+}
+
+// RateFunc returns a FiatRateFunc that reads the rates of currency from the
+// history.
+func (h *RateHistory) RateFunc(currency string) FiatRateFunc {
+	return func(t time.Time) (float64, bool) {
+		return h.Rate(currency, t)
+	}
+}
+
+// Add stores rates for currency, replacing the rates of the same days.
+// Add does not save the history.
+func (h *RateHistory) Add(currency string, rates []DailyRate) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	days, ok := h.rates[currency]
+	if !ok {
+		days = make(map[string]float64)
+		h.rates[currency] = days
+	}
+	for _, r := range rates {
+		days[dayKey(r.Day)] = r.Rate
+	}
+}
+
+// Save writes the history to its file.
+func (h *RateHistory) Save() error {
+	h.mtx.RLock()
+	data, err := json.Marshal(h.rates)
+	h.mtx.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return err
+	}
+	tmp := h.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
+
+// missingFrom returns the first UTC day between from and to that has no rate
+// for currency.
+func (h *RateHistory) missingFrom(currency string, from, to time.Time) (time.Time, bool) {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+
+	from = from.UTC().Truncate(24 * time.Hour)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if _, ok := h.rates[currency][dayKey(day)]; !ok {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Fill fetches the rates of currency missing between from and to from src and
+// saves them. It returns the number of daily rates fetched.
+func (h *RateHistory) Fill(ctx context.Context, src HistoricalRateSource, currency string, from, to time.Time) (int, error) {
+	start, ok := h.missingFrom(currency, from, to)
+	if !ok {
+		return 0, nil
+	}
+
+	rates, err := src.FetchDailyRates(ctx, currency, start, to)
+	if err != nil {
+		return 0, err
+	}
+	if len(rates) == 0 {
+		return 0, nil
+	}
+
+	h.Add(currency, rates)
+	return len(rates), h.Save()
+}
+
+// parseRateDay parses the date column of a rate CSV, which may be a date, an
+// RFC 3339 timestamp or a unix timestamp.
+func parseRateDay(s string) (time.Time, error) {
+	if t, err := time.Parse(dayFormat, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	unix, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return time.Unix(unix, 0), nil
+}
+
+// ImportCSV adds the daily rates of currency read from r and saves them. Each
+// record holds a date and a rate. A first record that does not parse, such as
+// a header, is skipped. It returns the number of rates imported.
+func (h *RateHistory) ImportCSV(r io.Reader, currency string) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rates []DailyRate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if len(record) < 2 {
+			return 0, fmt.Errorf("line %d: expected date and rate", line)
+		}
+
+		day, err := parseRateDay(strings.TrimSpace(record[0]))
+		if err == nil {
+			var rate float64
+			rate, err = strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+			if err == nil {
+				rates = append(rates, DailyRate{Day: day, Rate: rate})
+				continue
+			}
+		}
+		if line == 1 {
+			continue
+		}
+		return 0, fmt.Errorf("line %d: %v", line, err)
+	}
+
+	h.Add(currency, rates)
+	if err := h.Save(); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// NewCoinGeckoHistory returns a historical rate source for the CoinGecko
+// market chart API at baseURL, e.g. "https://api.coingecko.com". A nil client
+// uses a default client.
+func NewCoinGeckoHistory(baseURL string, client *http.Client) HistoricalRateSource {
+	return &coinGecko{baseURL: baseURL, client: httpClient(client)}
+}
+
+// FetchDailyRates uses the first price CoinGecko reports on each day, which is
+// the price at the start of the day for ranges longer than 90 days.
+func (c *coinGecko) FetchDailyRates(ctx context.Context, currency string, from, to time.Time) ([]DailyRate, error) {
+	var chart struct {
+		Prices [][2]float64 `json:"prices"`
+	}
+	url := fmt.Sprintf("%s/api/v3/coins/decred/market_chart/range?vs_currency=%s&from=%d&to=%d",
+		c.baseURL, strings.ToLower(currency), from.Unix(), to.Unix())
+	if err := getJSON(ctx, c.client, url, &chart); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var rates []DailyRate
+	for _, point := range chart.Prices {
+		t := time.Unix(0, int64(point[0])*int64(time.Millisecond)).UTC()
+		if seen[dayKey(t)] {
+			continue
+		}
+		seen[dayKey(t)] = true
+		rates = append(rates, DailyRate{Day: t.Truncate(24 * time.Hour), Rate: point[1]})
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Day.Before(rates[j].Day)
+	})
+	return rates, nil
+}
+
+// RateHistory returns the daily rate store of the wallet, loading it from the
+// app data directory on first use.
+func (wal *Wallet) RateHistory() (*RateHistory, error) {
+	wal.rateHistoryOnce.Do(func() {
+		wal.rateHistory, wal.rateHistoryErr = OpenRateHistory(filepath.Join(wal.root, rateHistoryFileName))
+	})
+	return wal.rateHistory, wal.rateHistoryErr
+}
+
+// FiatCurrency returns the currency selected in the CurrencyConversionConfigKey
+// preference. ok is false if currency conversion is turned off.
+func (wal *Wallet) FiatCurrency() (currency string, ok bool) {
+	value := wal.ReadStringConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey)
+	_, currency, ok = ParseCurrencyConversion(value)
+	return currency, ok
+}
+
+// FiatValueFunc values amount at the rate of a fiat currency on the day of
+// the unix timestamp. ok is false if the rate of that day is not known.
+type FiatValueFunc func(amount int64, timestamp int64) (value float64, currency string, ok bool)
+
+// HistoricalFiatValuer returns a FiatValueFunc using the stored rates of the
+// fiat currency selected when it is called, for pages that value
+// transactions every frame without reading the preference each time. Rates
+// added to the history later are used. It returns nil if currency
+// conversion is turned off or the rate history cannot be loaded.
+func (wal *Wallet) HistoricalFiatValuer() FiatValueFunc {
+	currency, ok := wal.FiatCurrency()
+	if !ok {
+		return nil
+	}
+	history, err := wal.RateHistory()
+	if err != nil {
+		return nil
+	}
+
+	return func(amount int64, timestamp int64) (float64, string, bool) {
+		rate, ok := history.Rate(currency, time.Unix(timestamp, 0))
+		if !ok {
+			return 0, "", false
+		}
+		return dcrutil.Amount(amount).ToCoin() * rate, currency, true
+	}
+}
+
+// HistoricalFiatValue values amount at the stored rate of the selected fiat
+// currency on the day of the unix timestamp. ok is false if currency
+// conversion is turned off or the rate of that day is not known.
+func (wal *Wallet) HistoricalFiatValue(amount int64, timestamp int64) (value float64, currency string, ok bool) {
+	valuer := wal.HistoricalFiatValuer()
+	if valuer == nil {
+		return 0, "", false
+	}
+	return valuer(amount, timestamp)
+}
+
+// LatestFiatValue values amount at the most recent stored rate of the
+// selected fiat currency and returns the day of that rate.
+func (wal *Wallet) LatestFiatValue(amount int64) (value float64, currency string, day time.Time, ok bool) {
+	currency, ok = wal.FiatCurrency()
+	if !ok {
+		return 0, "", time.Time{}, false
+	}
+	history, err := wal.RateHistory()
+	if err != nil {
+		return 0, "", time.Time{}, false
+	}
+
+	rate, ok := history.Latest(currency)
+	if !ok {
+		return 0, "", time.Time{}, false
+	}
+	return dcrutil.Amount(amount).ToCoin() * rate.Rate, currency, rate.Day, true
+}
+
+// oldestTransactionTime returns the time of the oldest transaction in the
+// loaded wallets.
+func (wal *Wallet) oldestTransactionTime() (time.Time, bool) {
+	var oldest int64
+	for _, wall := range wal.multi.AllWallets() {
+		txs, err := wall.GetTransactionsRaw(0, 1, dcrlibwallet.TxFilterAll, false)
+		if err != nil || len(txs) == 0 {
+			continue
+		}
+		if oldest == 0 || txs[0].Timestamp < oldest {
+			oldest = txs[0].Timestamp
+		}
+	}
+	return time.Unix(oldest, 0), oldest != 0
+}
+
+// SyncRateHistoryCtx fetches the daily rates of the selected fiat currency
+// that are missing since the oldest wallet transaction from wal.HistoricalRates.
+// It returns the number of daily rates fetched, which is zero without
+// fetching anything while another sync runs.
+func (wal *Wallet) SyncRateHistoryCtx(ctx context.Context) (int, error) {
+	if !atomic.CompareAndSwapInt32(&wal.rateHistorySyncing, 0, 1) {
+		return 0, nil
+	}
+	defer atomic.StoreInt32(&wal.rateHistorySyncing, 0)
+
+	currency, ok := wal.FiatCurrency()
+	if !ok {
+		return 0, ErrNoExchangeRate
+	}
+	history, err := wal.RateHistory()
+	if err != nil {
+		return 0, err
+	}
+
+	from, ok := wal.oldestTransactionTime()
+	if !ok {
+		return 0, nil
+	}
+	return history.Fill(ctx, wal.HistoricalRates, currency, from, time.Now())
+}
+
+// ImportRateHistoryCSV imports the daily rates of currency from the CSV file
+// at path into the wallet's rate history.
+func (wal *Wallet) ImportRateHistoryCSV(path, currency string) (int, error) {
+	history, err := wal.RateHistory()
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return history.ImportCSV(f, strings.ToUpper(currency))
+}
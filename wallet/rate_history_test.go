@@ -0,0 +1,123 @@
+package wallet_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Rate history", func() {
+	var (
+		server *httptest.Server
+		path   string
+		from   int64
+	)
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		path = filepath.Join(testDir, "rates", strconv.FormatInt(time.Now().UnixNano(), 10)+".json")
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v3/coins/decred/market_chart/range"))
+			Expect(r.URL.Query().Get("vs_currency")).To(Equal("eur"))
+			from, _ = strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+
+			ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+			fmt.Fprintf(w, `{"prices":[[%d,100.5],[%d,101],[%d,110.25]]}`,
+				ms(day), ms(day.Add(time.Hour)), ms(day.Add(24*time.Hour)))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("imports daily rates from CSV", func() {
+		history, err := OpenRateHistory(path)
+		Expect(err).To(BeNil())
+
+		csv := "date,rate\n2021-03-01,120.5\n2021-03-02T00:00:00Z, 121\n"
+		n, err := history.ImportCSV(strings.NewReader(csv), "USD")
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(2))
+
+		rate, ok := history.Rate("USD", day.Add(23*time.Hour))
+		Expect(ok).To(BeTrue())
+		Expect(rate).To(Equal(120.5))
+		_, ok = history.Rate("EUR", day)
+		Expect(ok).To(BeFalse())
+
+		latest, ok := history.Latest("USD")
+		Expect(ok).To(BeTrue())
+		Expect(latest.Day).To(Equal(day.Add(24 * time.Hour)))
+		Expect(latest.Rate).To(Equal(121.0))
+
+		_, err = history.ImportCSV(strings.NewReader("2021-03-01,1\nbad,2\n"), "USD")
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("persists rates", func() {
+		history, err := OpenRateHistory(path)
+		Expect(err).To(BeNil())
+		history.Add("USD", []DailyRate{{Day: day, Rate: 99}})
+		Expect(history.Save()).To(Succeed())
+
+		reopened, err := OpenRateHistory(path)
+		Expect(err).To(BeNil())
+		rate, ok := reopened.RateFunc("USD")(day)
+		Expect(ok).To(BeTrue())
+		Expect(rate).To(Equal(99.0))
+	})
+
+	It("fills missing days from a source", func() {
+		history, err := OpenRateHistory(path)
+		Expect(err).To(BeNil())
+		history.Add("EUR", []DailyRate{{Day: day, Rate: 90}})
+
+		src := NewCoinGeckoHistory(server.URL, nil)
+		n, err := history.Fill(context.Background(), src, "EUR", day, day.Add(30*time.Hour))
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(2))
+		Expect(from).To(Equal(day.Add(24 * time.Hour).Unix()))
+
+		rate, _ := history.Rate("EUR", day)
+		Expect(rate).To(Equal(100.5))
+		rate, _ = history.Rate("EUR", day.Add(24*time.Hour))
+		Expect(rate).To(Equal(110.25))
+
+		n, err = history.Fill(context.Background(), src, "EUR", day, day.Add(30*time.Hour))
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(0))
+	})
+
+	It("values transactions at the rates of the currency selected when asked", func() {
+		defer wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "none")
+		wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "none")
+		Expect(wal.HistoricalFiatValuer()).To(BeNil())
+
+		wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "gbp_coingecko")
+		value := wal.HistoricalFiatValuer()
+		Expect(value).NotTo(BeNil())
+		wal.SaveConfigValueForKey(dcrlibwallet.CurrencyConversionConfigKey, "none")
+
+		_, _, ok := value(2e8, day.Unix())
+		Expect(ok).To(BeFalse())
+		history, err := wal.RateHistory()
+		Expect(err).To(BeNil())
+		history.Add("GBP", []DailyRate{{Day: day, Rate: 80}})
+
+		fiat, currency, ok := value(2e8, day.Add(time.Hour).Unix())
+		Expect(ok).To(BeTrue())
+		Expect(fiat).To(Equal(160.0))
+		Expect(currency).To(Equal("GBP"))
+	})
+})
@@ -0,0 +1,32 @@
+package wallet
+
+import "context"
+
+// RebroadcastTxsCtx sends the unconfirmed transactions of a wallet to the
+// network again.
+// It blocks until the transactions are sent or ctx is canceled.
+func (wal *Wallet) RebroadcastTxsCtx(ctx context.Context, walletID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	return w.PublishUnminedTransactions()
+}
+
+// RebroadcastTxs sends the unconfirmed transactions of a wallet to the
+// network again.
+// It is non-blocking and sends any error to wal.Send.
+func (wal *Wallet) RebroadcastTxs(walletID int) RequestID {
+	req := wal.newRequest(OpRebroadcastTxs)
+	go func() {
+		if err := wal.RebroadcastTxsCtx(context.Background(), walletID); err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(walletID)
+	}()
+	return req.ID
+}
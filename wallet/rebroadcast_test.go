@@ -0,0 +1,24 @@
+package wallet_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Rebroadcasting", func() {
+	// Publishing needs a synced network backend, so only the failures are
+	// covered here.
+	It("fails for unknown wallets, canceled contexts and wallets that are not synced", func() {
+		ctx := context.Background()
+		Expect(wal.RebroadcastTxsCtx(ctx, 99)).To(Equal(ErrIDNotExist))
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(wal.RebroadcastTxsCtx(canceled, 1)).To(Equal(context.Canceled))
+
+		Expect(wal.RebroadcastTxsCtx(ctx, 1)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,131 @@
+package wallet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrutil"
+)
+
+var (
+	// ErrNoRecipients is returned when a transaction has no outputs to pay.
+	ErrNoRecipients = errors.New("no recipients")
+
+	// ErrMultipleSendMax is returned when more than one recipient is set to
+	// receive the max amount.
+	ErrMultipleSendMax = errors.New("only one recipient can receive the max amount")
+)
+
+// Recipient is an output of a transaction that may pay several addresses.
+type Recipient struct {
+	Address string
+	// Amount is the amount in atoms paid to Address. It is ignored if
+	// SendMax is set.
+	Amount int64
+	// SendMax pays Address everything left in the account after the other
+	// outputs and the fee.
+	SendMax bool
+}
+
+// maxAmount is the value of the amount column of a recipients CSV that sends
+// the max amount.
+const maxAmount = "max"
+
+// ParseRecipientsCSV reads recipients from CSV records of an address and a DCR
+// amount, or "max" to send the max amount. A first record with an invalid
+// amount, such as a header, is skipped. Columns after the amount are ignored.
+func ParseRecipientsCSV(r io.Reader) ([]Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var recipients []Recipient
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected address and amount", line)
+		}
+
+		recipient, err := parseRecipient(record[0], record[1])
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err := ValidateRecipients(recipients); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+func parseRecipient(address, amount string) (Recipient, error) {
+	recipient := Recipient{Address: strings.TrimSpace(address)}
+	amount = strings.TrimSpace(amount)
+	if strings.EqualFold(amount, maxAmount) {
+		recipient.SendMax = true
+		return recipient, nil
+	}
+
+	dcr, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return recipient, fmt.Errorf("invalid amount %q", amount)
+	}
+	atoms, err := dcrutil.NewAmount(dcr)
+	if err != nil {
+		return recipient, err
+	}
+	recipient.Amount = int64(atoms)
+	return recipient, nil
+}
+
+// ValidateRecipients checks that there is at least one recipient, that every
+// recipient has an address and a positive amount and that at most one
+// recipient receives the max amount.
+func ValidateRecipients(recipients []Recipient) error {
+	if len(recipients) == 0 {
+		return ErrNoRecipients
+	}
+
+	sendMax := false
+	for i, r := range recipients {
+		if r.Address == "" {
+			return fmt.Errorf("recipient %d: missing address", i+1)
+		}
+		if r.SendMax {
+			if sendMax {
+				return ErrMultipleSendMax
+			}
+			sendMax = true
+			continue
+		}
+		if r.Amount <= 0 {
+			return fmt.Errorf("recipient %d: invalid amount", i+1)
+		}
+	}
+	return nil
+}
+
+// RecipientsTotal returns the sum of the fixed amounts paid to recipients.
+func RecipientsTotal(recipients []Recipient) int64 {
+	var total int64
+	for _, r := range recipients {
+		if !r.SendMax {
+			total += r.Amount
+		}
+	}
+	return total
+}
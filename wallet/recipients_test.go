@@ -0,0 +1,37 @@
+package wallet_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Recipients", func() {
+	It("parses a recipients CSV", func() {
+		csv := "address,amount,name\n" +
+			"TsfDLrRkk9ciUuwfp2b8PawwnukYD7yAjGd,1.5,alice\n" +
+			"TsVVtAssM1ffeCPhUSAPgsVwC7iEuKsfBLr, MAX\n"
+		recipients, err := ParseRecipientsCSV(strings.NewReader(csv))
+		Expect(err).To(BeNil())
+		Expect(recipients).To(Equal([]Recipient{
+			{Address: "TsfDLrRkk9ciUuwfp2b8PawwnukYD7yAjGd", Amount: 150000000},
+			{Address: "TsVVtAssM1ffeCPhUSAPgsVwC7iEuKsfBLr", SendMax: true},
+		}))
+		Expect(RecipientsTotal(recipients)).To(BeEquivalentTo(150000000))
+
+		_, err = ParseRecipientsCSV(strings.NewReader("a,1\nb,one\n"))
+		Expect(err).ToNot(BeNil())
+		_, err = ParseRecipientsCSV(strings.NewReader("a,max\nb,max\n"))
+		Expect(err).To(Equal(ErrMultipleSendMax))
+		_, err = ParseRecipientsCSV(strings.NewReader("address,amount\n"))
+		Expect(err).To(Equal(ErrNoRecipients))
+	})
+
+	It("validates recipients", func() {
+		Expect(ValidateRecipients([]Recipient{{Address: "a", Amount: 1}, {Address: "b", SendMax: true}})).To(Succeed())
+		Expect(ValidateRecipients([]Recipient{{Address: "a"}})).ToNot(Succeed())
+		Expect(ValidateRecipients([]Recipient{{Amount: 1}})).ToNot(Succeed())
+	})
+})
@@ -1,16 +1,107 @@
 package wallet
 
 import (
+	"sync/atomic"
+
 	"github.com/planetdecred/dcrlibwallet"
 )
 
+// RequestID identifies a single call to one of the non-blocking wallet
+// commands. The command returns it to the caller and echoes it on the
+// Response it sends to wal.Send.
+type RequestID uint64
+
+// Op is the kind of wallet command a Response was sent for.
+type Op string
+
+const (
+	OpLoadWallets             Op = "LoadWallets"
+	OpSetupListeners          Op = "SetupListeners"
+	OpCreateWallet            Op = "CreateWallet"
+	OpRestoreWallet           Op = "RestoreWallet"
+	OpDeleteWallet            Op = "DeleteWallet"
+	OpAddAccount              Op = "AddAccount"
+	OpCreateTransaction       Op = "CreateTransaction"
+	OpBroadcastTransaction    Op = "BroadcastTransaction"
+	OpGetAllTransactions      Op = "GetAllTransactions"
+	OpGetTransaction          Op = "GetTransaction"
+	OpGetMultiWalletInfo      Op = "GetMultiWalletInfo"
+	OpSignMessage             Op = "SignMessage"
+	OpRenameWallet            Op = "RenameWallet"
+	OpChangeWalletPassphrase  Op = "ChangeWalletPassphrase"
+	OpOpenWallets             Op = "OpenWallets"
+	OpSetStartupPassphrase    Op = "SetStartupPassphrase"
+	OpChangeStartupPassphrase Op = "ChangeStartupPassphrase"
+	OpRemoveStartupPassphrase Op = "RemoveStartupPassphrase"
+	OpRenameAccount           Op = "RenameAccount"
+	OpGetAllProposals         Op = "GetAllProposals"
+	OpAllUnspentOutputs       Op = "AllUnspentOutputs"
+	OpSetupAccountMixer       Op = "SetupAccountMixer"
+	OpPurchaseTicket          Op = "PurchaseTicket"
+	OpGetAllTickets           Op = "GetAllTickets"
+	OpAddVSP                  Op = "AddVSP"
+	OpGetAllVSP               Op = "GetAllVSP"
+	OpExportTransactions      Op = "ExportTransactions"
+	OpGetProposalVote         Op = "GetProposalVote"
+	OpCastProposalVotes       Op = "CastProposalVotes"
+	OpStartTicketBuyer        Op = "StartTicketBuyer"
+	OpStakingReport           Op = "StakingReport"
+	OpExportStakingReport     Op = "ExportStakingReport"
+	OpVSPTicketStatus         Op = "VSPTicketStatus"
+	OpRetryVSPFee             Op = "RetryVSPFee"
+	OpProbeVSPs               Op = "ProbeVSPs"
+	OpSetVoteChoice           Op = "SetVoteChoice"
+	OpSetTreasuryPolicy       Op = "SetTreasuryPolicy"
+	OpSearchProposals         Op = "SearchProposals"
+	OpProposalVoteNotice      Op = "ProposalVoteNotice"
+	OpRebroadcastTxs          Op = "RebroadcastTxs"
+	OpQueryTransactions       Op = "QueryTransactions"
+	OpExchangeRate            Op = "ExchangeRate"
+	OpBroadcastSignedTx       Op = "BroadcastSignedTx"
+)
+
 // Response represents a discriminated union for wallet responses.
-// Either Resp or Err must be nil.
+// Either Resp or Err must be nil. ID and Op identify the call the
+// response belongs to.
 type Response struct {
+	ID   RequestID
+	Op   Op
 	Resp interface{}
 	Err  error
 }
 
+// request is a pending call to a non-blocking wallet command.
+type request struct {
+	ID RequestID
+	Op Op
+}
+
+// newRequest allocates the ID of a call to the command op.
+func (wal *Wallet) newRequest(op Op) request {
+	return request{
+		ID: RequestID(atomic.AddUint64(&wal.lastRequestID, 1)),
+		Op: op,
+	}
+}
+
+// response wraps resp in a Response for the request.
+func (req request) response(resp interface{}) Response {
+	return Response{
+		ID:   req.ID,
+		Op:   req.Op,
+		Resp: resp,
+	}
+}
+
+// error wraps err in a Response for the request.
+func (req request) error(err error) Response {
+	return Response{
+		ID:  req.ID,
+		Op:  req.Op,
+		Err: err,
+	}
+}
+
 // ResponseError wraps err in a Response
 func ResponseError(err error) Response {
 	return Response{
@@ -132,6 +223,13 @@ type Transaction struct {
 	DateTime      string
 }
 
+// TransactionsExported is sent when Wallet.ExportTransactions has written
+// the export file.
+type TransactionsExported struct {
+	Path  string
+	Count int
+}
+
 // Transactions is sent in response to Wallet.GetAllTransactions
 type Transactions struct {
 	Total   int
@@ -177,6 +275,9 @@ type UnspentOutput struct {
 	UTXO     dcrlibwallet.UnspentOutput
 	Amount   string
 	DateTime string
+	Label    string
+	// Frozen outputs are never selected to fund a transaction.
+	Frozen bool
 }
 
 // UnspentOutputs wraps the dcrlibwallet UTXO type and adds processed data
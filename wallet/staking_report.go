@@ -0,0 +1,458 @@
+package wallet
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+// vspFeeWindow is how long after or before a ticket purchase a payment is
+// considered to be the VSP fee of the ticket.
+const vspFeeWindow = time.Hour
+
+// StakingMonth holds the staking activity of a wallet in one calendar month.
+// Tickets bought, the fees paid for them and whether they were missed or
+// expired count towards the month the ticket was bought in. Votes and their
+// rewards count towards the month of the vote.
+type StakingMonth struct {
+	// Month is midnight UTC of the first day of the month.
+	Month         time.Time
+	TicketsBought int
+	// Staked is the total price of the tickets bought.
+	Staked int64
+	Votes  int
+	// Rewards is the stake reward of the votes, before fees.
+	Rewards int64
+	// VSPFees are estimated from the payments made to VSPs around the
+	// time each ticket was bought.
+	VSPFees int64
+	// TxFees are the fees of the ticket purchases and VSP fee payments.
+	TxFees  int64
+	Missed  int
+	Expired int
+
+	daysToVote int64
+	votedStake int64
+	votedCosts int64
+}
+
+func (m *StakingMonth) add(other StakingMonth) {
+	m.TicketsBought += other.TicketsBought
+	m.Staked += other.Staked
+	m.Votes += other.Votes
+	m.Rewards += other.Rewards
+	m.VSPFees += other.VSPFees
+	m.TxFees += other.TxFees
+	m.Missed += other.Missed
+	m.Expired += other.Expired
+	m.daysToVote += other.daysToVote
+	m.votedStake += other.votedStake
+	m.votedCosts += other.votedCosts
+}
+
+// AvgDaysToVote returns the average number of days the tickets that voted in
+// the month took to vote.
+func (m StakingMonth) AvgDaysToVote() float64 {
+	if m.Votes == 0 {
+		return 0
+	}
+	return float64(m.daysToVote) / float64(m.Votes)
+}
+
+// MissedRate returns the fraction of the tickets bought in the month that
+// missed their vote.
+func (m StakingMonth) MissedRate() float64 {
+	if m.TicketsBought == 0 {
+		return 0
+	}
+	return float64(m.Missed) / float64(m.TicketsBought)
+}
+
+// ExpiredRate returns the fraction of the tickets bought in the month that
+// expired without being called to vote.
+func (m StakingMonth) ExpiredRate() float64 {
+	if m.TicketsBought == 0 {
+		return 0
+	}
+	return float64(m.Expired) / float64(m.TicketsBought)
+}
+
+// AnnualROI returns the return of the tickets that voted in the month, net of
+// their VSP and transaction fees, as a yearly rate.
+func (m StakingMonth) AnnualROI() float64 {
+	if m.votedStake == 0 {
+		return 0
+	}
+	days := math.Max(m.AvgDaysToVote(), 1)
+	return float64(m.Rewards-m.votedCosts) / float64(m.votedStake) * 365 / days
+}
+
+// StakingReport is the monthly staking activity of a wallet.
+type StakingReport struct {
+	WalletID   int
+	WalletName string
+	// Months runs from the first month with a ticket to the last month with
+	// staking activity, oldest first. Months without activity are included.
+	Months []StakingMonth
+	Total  StakingMonth
+}
+
+// StakingReports is sent when Wallet.StakingReport has read all wallets.
+type StakingReports struct {
+	Reports []StakingReport
+}
+
+// StakingReportExported is sent when Wallet.ExportStakingReport has written
+// the export file.
+type StakingReportExported struct {
+	Path string
+}
+
+type stakedTicket struct {
+	txn       *dcrlibwallet.Transaction
+	month     time.Time
+	vspFee    int64
+	txFees    int64
+	spentBy   *dcrlibwallet.Transaction
+	voteMonth time.Time
+}
+
+func monthOf(timestamp int64) time.Time {
+	t := time.Unix(timestamp, 0).UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// isFeePayment reports whether txn looks like the payment of a VSP fee: a
+// regular transaction funded by the wallet with a single output to somebody
+// else.
+func isFeePayment(txn *dcrlibwallet.Transaction) bool {
+	if txn.Type != dcrlibwallet.TxTypeRegular || txn.Direction != dcrlibwallet.TxDirectionSent {
+		return false
+	}
+	for _, input := range txn.Inputs {
+		if input.AccountNumber < 0 {
+			return false
+		}
+	}
+	external := 0
+	for _, output := range txn.Outputs {
+		if output.AccountNumber < 0 {
+			external++
+		}
+	}
+	return external == 1
+}
+
+// NewStakingReport builds the staking report of a wallet from all its
+// transactions. statuses maps ticket hashes to their dcrlibwallet status and
+// is used for tickets that missed or expired but were not revoked yet.
+// ticketLife is the number of blocks from a ticket's purchase until it
+// expires.
+func NewStakingReport(walletID int, walletName string, txs []dcrlibwallet.Transaction, statuses map[string]string, ticketLife int32) *StakingReport {
+	tickets := make(map[string]*stakedTicket)
+	var purchases []*stakedTicket
+	var payments []*dcrlibwallet.Transaction
+	for i := range txs {
+		txn := &txs[i]
+		switch {
+		case txn.Type == dcrlibwallet.TxTypeTicketPurchase:
+			ticket := &stakedTicket{txn: txn, month: monthOf(txn.Timestamp), txFees: txn.Fee}
+			tickets[txn.Hash] = ticket
+			purchases = append(purchases, ticket)
+		case isFeePayment(txn):
+			payments = append(payments, txn)
+		}
+	}
+	for i := range txs {
+		txn := &txs[i]
+		if txn.Type != dcrlibwallet.TxTypeVote && txn.Type != dcrlibwallet.TxTypeRevocation {
+			continue
+		}
+		if ticket, ok := tickets[txn.TicketSpentHash]; ok {
+			ticket.spentBy = txn
+			ticket.voteMonth = monthOf(txn.Timestamp)
+		}
+	}
+
+	// Each fee payment goes to the ticket bought closest in time to it that
+	// has no fee yet. Payments larger than the ticket are not VSP fees.
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Timestamp < payments[j].Timestamp })
+	for _, payment := range payments {
+		var closest *stakedTicket
+		var closestGap int64
+		for _, ticket := range purchases {
+			gap := payment.Timestamp - ticket.txn.Timestamp
+			if gap < 0 {
+				gap = -gap
+			}
+			if ticket.vspFee != 0 || gap > int64(vspFeeWindow.Seconds()) || payment.Amount >= ticket.txn.Amount {
+				continue
+			}
+			if closest == nil || gap < closestGap {
+				closest, closestGap = ticket, gap
+			}
+		}
+		if closest != nil {
+			closest.vspFee = payment.Amount
+			closest.txFees += payment.Fee
+		}
+	}
+
+	months := make(map[time.Time]*StakingMonth)
+	month := func(t time.Time) *StakingMonth {
+		if months[t] == nil {
+			months[t] = &StakingMonth{Month: t}
+		}
+		return months[t]
+	}
+	for _, ticket := range purchases {
+		bought := month(ticket.month)
+		bought.TicketsBought++
+		bought.Staked += ticket.txn.Amount
+		bought.TxFees += ticket.txFees
+		bought.VSPFees += ticket.vspFee
+
+		status := statuses[ticket.txn.Hash]
+		if spender := ticket.spentBy; spender != nil {
+			if spender.Type == dcrlibwallet.TxTypeVote {
+				voted := month(ticket.voteMonth)
+				voted.Votes++
+				voted.Rewards += spender.VoteReward + ticket.txn.Fee
+				voted.daysToVote += int64(spender.DaysToVoteOrRevoke)
+				voted.votedStake += ticket.txn.Amount
+				voted.votedCosts += ticket.txFees + ticket.vspFee
+				continue
+			}
+			// A ticket revoked before it could have expired missed its vote.
+			status = "MISSED"
+			if spender.BlockHeight > 0 && ticket.txn.BlockHeight > 0 &&
+				spender.BlockHeight-ticket.txn.BlockHeight > ticketLife {
+				status = "EXPIRED"
+			}
+		}
+		switch status {
+		case "MISSED":
+			bought.Missed++
+		case "EXPIRED":
+			bought.Expired++
+		}
+	}
+
+	report := &StakingReport{WalletID: walletID, WalletName: walletName, Months: monthRange(months)}
+	for _, m := range report.Months {
+		report.Total.add(m)
+	}
+	return report
+}
+
+// monthRange returns months in order from the first to the last, adding the
+// months in between that have no activity.
+func monthRange(months map[time.Time]*StakingMonth) []StakingMonth {
+	if len(months) == 0 {
+		return nil
+	}
+
+	var first, last time.Time
+	for t := range months {
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	var ordered []StakingMonth
+	for t := first; !t.After(last); t = t.AddDate(0, 1, 0) {
+		m := StakingMonth{Month: t}
+		if months[t] != nil {
+			m = *months[t]
+		}
+		ordered = append(ordered, m)
+	}
+	return ordered
+}
+
+// CombineStakingReports sums the reports of several wallets month by month.
+// The combined report has no wallet.
+func CombineStakingReports(reports []StakingReport) StakingReport {
+	var combined StakingReport
+	months := make(map[time.Time]*StakingMonth)
+	for _, report := range reports {
+		for _, m := range report.Months {
+			if months[m.Month] == nil {
+				months[m.Month] = &StakingMonth{Month: m.Month}
+			}
+			months[m.Month].add(m)
+		}
+		combined.Total.add(report.Total)
+	}
+	combined.Months = monthRange(months)
+	return combined
+}
+
+// StakingReportCtx builds the staking report of every wallet.
+// It blocks until all wallets have been read or ctx is canceled.
+func (wal *Wallet) StakingReportCtx(ctx context.Context) ([]StakingReport, error) {
+	wallets, err := wal.wallets()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]StakingReport, 0, len(wallets))
+	for _, wall := range wallets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		params, err := utils.ChainParams(wall.NetType())
+		if err != nil {
+			return nil, err
+		}
+		ticketLife := int32(params.TicketMaturity) + int32(params.TicketExpiry)
+
+		txs, err := wall.GetTransactionsRaw(0, 0, dcrlibwallet.TxFilterAll, true)
+		if err != nil {
+			return nil, err
+		}
+		ticketsInfo, err := wall.GetTicketsForBlockHeightRange(0, wall.GetBestBlock(), math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		statuses := make(map[string]string, len(ticketsInfo))
+		for _, info := range ticketsInfo {
+			statuses[info.Ticket.Hash.String()] = info.Status
+		}
+
+		reports = append(reports, *NewStakingReport(wall.ID, wall.Name, txs, statuses, ticketLife))
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].WalletID < reports[j].WalletID
+	})
+	return reports, nil
+}
+
+// StakingReport builds the staking report of every wallet.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) StakingReport() RequestID {
+	req := wal.newRequest(OpStakingReport)
+	go func() {
+		reports, err := wal.StakingReportCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(&StakingReports{Reports: reports})
+	}()
+	return req.ID
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 4, 64)
+}
+
+// WriteStakingReport writes reports to w as CSV, one row per wallet and
+// month followed by a total row for each wallet.
+func WriteStakingReport(w io.Writer, reports []StakingReport) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"wallet", "month", "tickets_bought", "staked", "votes", "rewards", "vsp_fees",
+		"tx_fees", "avg_days_to_vote", "missed", "expired", "missed_rate", "expired_rate", "annual_roi"})
+	if err != nil {
+		return err
+	}
+
+	row := func(wallet, month string, m StakingMonth) []string {
+		return []string{
+			wallet,
+			month,
+			strconv.Itoa(m.TicketsBought),
+			formatCoin(dcrutil.Amount(m.Staked).ToCoin()),
+			strconv.Itoa(m.Votes),
+			formatCoin(dcrutil.Amount(m.Rewards).ToCoin()),
+			formatCoin(dcrutil.Amount(m.VSPFees).ToCoin()),
+			formatCoin(dcrutil.Amount(m.TxFees).ToCoin()),
+			strconv.FormatFloat(m.AvgDaysToVote(), 'f', 1, 64),
+			strconv.Itoa(m.Missed),
+			strconv.Itoa(m.Expired),
+			formatRate(m.MissedRate()),
+			formatRate(m.ExpiredRate()),
+			formatRate(m.AnnualROI()),
+		}
+	}
+	for _, report := range reports {
+		for _, m := range report.Months {
+			if err := cw.Write(row(report.WalletName, m.Month.Format("2006-01"), m)); err != nil {
+				return err
+			}
+		}
+		if err := cw.Write(row(report.WalletName, "total", report.Total)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportStakingReportCtx writes the staking report of every wallet to a CSV
+// file in the exports folder of the app data directory.
+// It blocks until the file is written or ctx is canceled.
+func (wal *Wallet) ExportStakingReportCtx(ctx context.Context) (*StakingReportExported, error) {
+	reports, err := wal.StakingReportCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(wal.root, exportDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("staking-report-%s.%s", time.Now().Format("20060102-150405"), ExportCSV)
+	path := filepath.Join(dir, name)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = WriteStakingReport(file, reports)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, InternalWalletError{
+			Message: "Could not export staking report",
+			Err:     err,
+		}
+	}
+	return &StakingReportExported{Path: path}, nil
+}
+
+// ExportStakingReport writes the staking report of every wallet to a CSV file
+// in the exports folder of the app data directory.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) ExportStakingReport() RequestID {
+	req := wal.newRequest(OpExportStakingReport)
+	go func() {
+		exported, err := wal.ExportStakingReportCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(exported)
+	}()
+	return req.ID
+}
@@ -0,0 +1,119 @@
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Staking report", func() {
+	const ticketLife = 100
+
+	jan := time.Date(2021, time.January, 10, 12, 0, 0, 0, time.UTC).Unix()
+	feb := time.Date(2021, time.February, 3, 12, 0, 0, 0, time.UTC).Unix()
+	apr := time.Date(2021, time.April, 20, 12, 0, 0, 0, time.UTC).Unix()
+
+	ticket := func(hash string, timestamp int64, height int32) dcrlibwallet.Transaction {
+		return dcrlibwallet.Transaction{Hash: hash, Type: dcrlibwallet.TxTypeTicketPurchase, Timestamp: timestamp,
+			BlockHeight: height, Amount: 100e8, Fee: 3000}
+	}
+	feePayment := func(hash string, timestamp int64, amount int64) dcrlibwallet.Transaction {
+		return dcrlibwallet.Transaction{Hash: hash, Type: dcrlibwallet.TxTypeRegular, Timestamp: timestamp,
+			Direction: dcrlibwallet.TxDirectionSent, Amount: amount, Fee: 2000,
+			Inputs:  []*dcrlibwallet.TxInput{{AccountNumber: 0}},
+			Outputs: []*dcrlibwallet.TxOutput{{AccountNumber: -1}, {AccountNumber: 0}}}
+	}
+
+	txs := []dcrlibwallet.Transaction{
+		ticket("t1", jan, 10),
+		feePayment("f1", jan+30, 1e6),
+		ticket("t2", jan+600, 11),
+		ticket("t3", jan+1200, 12),
+		ticket("t4", jan+1800, 13),
+		// A payment long after any ticket is not a VSP fee.
+		feePayment("p1", apr, 1e6),
+		{Hash: "v1", Type: dcrlibwallet.TxTypeVote, Timestamp: feb, BlockHeight: 50, TicketSpentHash: "t1",
+			VoteReward: 2e8 - 3000, DaysToVoteOrRevoke: 24},
+		{Hash: "r2", Type: dcrlibwallet.TxTypeRevocation, Timestamp: feb, BlockHeight: 60, TicketSpentHash: "t2"},
+		{Hash: "r3", Type: dcrlibwallet.TxTypeRevocation, Timestamp: apr, BlockHeight: 200, TicketSpentHash: "t3"},
+		{Hash: "v4", Type: dcrlibwallet.TxTypeVote, Timestamp: apr, BlockHeight: 90, TicketSpentHash: "t4",
+			VoteReward: 2e8 - 3000, DaysToVoteOrRevoke: 100},
+	}
+	statuses := map[string]string{"t1": "VOTED", "t2": "REVOKED", "t3": "REVOKED", "t4": "VOTED"}
+
+	It("sums staking activity per month", func() {
+		report := NewStakingReport(1, "default", txs, statuses, ticketLife)
+		Expect(report.WalletName).To(Equal("default"))
+		Expect(report.Months).To(HaveLen(4))
+
+		bought := report.Months[0]
+		Expect(bought.Month).To(Equal(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(bought.TicketsBought).To(Equal(4))
+		Expect(bought.Staked).To(Equal(int64(400e8)))
+		Expect(bought.VSPFees).To(Equal(int64(1e6)))
+		Expect(bought.TxFees).To(Equal(int64(4*3000 + 2000)))
+		Expect(bought.Missed).To(Equal(1))
+		Expect(bought.Expired).To(Equal(1))
+		Expect(bought.MissedRate()).To(Equal(0.25))
+		Expect(bought.Votes).To(BeZero())
+
+		voted := report.Months[1]
+		Expect(voted.Votes).To(Equal(1))
+		Expect(voted.Rewards).To(Equal(int64(2e8)))
+		Expect(voted.AvgDaysToVote()).To(Equal(24.0))
+		Expect(voted.AnnualROI()).To(BeNumerically("~", float64(2e8-1e6-5000)/100e8*365/24, 1e-9))
+
+		Expect(report.Months[2].TicketsBought).To(BeZero())
+		Expect(report.Total.TicketsBought).To(Equal(4))
+		Expect(report.Total.Votes).To(Equal(2))
+		Expect(report.Total.AvgDaysToVote()).To(Equal(62.0))
+		Expect(report.Total.AnnualROI()).To(BeNumerically("~", float64(4e8-1e6-8000)/200e8*365/62, 1e-9))
+	})
+
+	It("has no months without tickets", func() {
+		report := NewStakingReport(1, "default", txs[5:6], statuses, ticketLife)
+		Expect(report.Months).To(BeEmpty())
+		Expect(report.Total.AnnualROI()).To(BeZero())
+	})
+
+	It("combines the reports of wallets", func() {
+		first := NewStakingReport(1, "default", txs[:3], statuses, ticketLife)
+		second := NewStakingReport(2, "savings", []dcrlibwallet.Transaction{ticket("t1", jan, 10), txs[6]}, statuses, ticketLife)
+
+		combined := CombineStakingReports([]StakingReport{*first, *second})
+		Expect(combined.Months).To(HaveLen(2))
+		Expect(combined.Months[0].TicketsBought).To(Equal(3))
+		Expect(combined.Months[1].Votes).To(Equal(1))
+		Expect(combined.Total.TicketsBought).To(Equal(3))
+		Expect(CombineStakingReports(nil).Months).To(BeEmpty())
+	})
+
+	It("writes the report as CSV", func() {
+		var buf bytes.Buffer
+		report := NewStakingReport(1, "default", txs, statuses, ticketLife)
+		Expect(WriteStakingReport(&buf, []StakingReport{*report})).To(Succeed())
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		Expect(err).To(BeNil())
+		Expect(rows).To(HaveLen(6))
+		Expect(rows[0][1]).To(Equal("month"))
+		Expect(rows[1][:8]).To(Equal([]string{"default", "2021-01", "4", "400.00000000", "0",
+			"0.00000000", "0.01000000", "0.00014000"}))
+		Expect(rows[2][8]).To(Equal("24.0"))
+		Expect(rows[5][1]).To(Equal("total"))
+	})
+
+	It("reads the wallets", func() {
+		reports, err := wal.StakingReportCtx(context.Background())
+		Expect(err).To(BeNil())
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].WalletID).To(Equal(1))
+		Expect(reports[0].Months).To(BeEmpty())
+	})
+})
@@ -0,0 +1,368 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+const (
+	// TicketBuyerConfigKey is the wallet config key the auto-buyer settings
+	// of a wallet are stored under.
+	TicketBuyerConfigKey = "ticket_buyer_config"
+
+	// ticketBuyerMaxPerBlock limits the tickets bought on one block so a
+	// misconfigured balance cannot spend the account in a single purchase.
+	ticketBuyerMaxPerBlock = 5
+
+	// ticketBuyerLogSize is the number of log entries kept per wallet.
+	ticketBuyerLogSize = 100
+
+	ticketBuyerListenerID = syncID + "-ticket-buyer"
+)
+
+var (
+	// ErrTicketBuyerNotConfigured is returned when the auto-buyer of a wallet
+	// is started before its settings were saved.
+	ErrTicketBuyerNotConfigured = errors.New("ticket auto-buyer is not configured")
+
+	// ErrTicketBuyerRunning is returned when the auto-buyer of a wallet is
+	// started twice or its settings are changed while it runs.
+	ErrTicketBuyerRunning = errors.New("ticket auto-buyer is already running")
+)
+
+// TicketBuyerConfig are the auto-buyer settings of a wallet.
+type TicketBuyerConfig struct {
+	// Account is the account tickets are bought from.
+	Account int32 `json:"account"`
+	// VSPHost is the VSP the tickets are registered with.
+	VSPHost string `json:"vspHost"`
+	// BalanceToMaintain is the spendable balance of the account, in atoms,
+	// that is never spent on tickets.
+	BalanceToMaintain int64 `json:"balanceToMaintain"`
+}
+
+// TicketBuyerLogEntry is a purchase made or attempted by the auto-buyer.
+type TicketBuyerLogEntry struct {
+	Time     time.Time
+	WalletID int
+	Height   int32
+	// Tickets is the number of tickets bought, or tried to buy if Err is set.
+	Tickets int
+	Price   int64
+	Err     string
+}
+
+// String describes the entry for the purchase log.
+func (e TicketBuyerLogEntry) String() string {
+	if e.Err != "" {
+		return fmt.Sprintf("Block %d: could not buy %d ticket(s): %s", e.Height, e.Tickets, e.Err)
+	}
+	return fmt.Sprintf("Block %d: bought %d ticket(s) at %s", e.Height, e.Tickets, dcrutil.Amount(e.Price))
+}
+
+// ticketBuyer buys tickets for a wallet on new blocks while it runs.
+type ticketBuyer struct {
+	walletID   int
+	cfg        TicketBuyerConfig
+	passphrase []byte
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	// buying is set while a purchase is made, so blocks attached meanwhile
+	// buy no tickets.
+	buying bool
+	vspd   *dcrlibwallet.VSP
+}
+
+// ticketBuyers holds the running auto-buyers and their logs.
+type ticketBuyers struct {
+	mtx     sync.Mutex
+	running map[int]*ticketBuyer
+	logs    map[int][]TicketBuyerLogEntry
+}
+
+// TicketBuyerConfig returns the auto-buyer settings of a wallet, or nil if
+// none were saved.
+func (wal *Wallet) TicketBuyerConfig(walletID int) (*TicketBuyerConfig, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	var cfg TicketBuyerConfig
+	if err := w.ReadUserConfigValue(TicketBuyerConfigKey, &cfg); err != nil || cfg.VSPHost == "" {
+		return nil, nil
+	}
+	return &cfg, nil
+}
+
+// SetTicketBuyerConfig saves the auto-buyer settings of a wallet. They cannot
+// be changed while its auto-buyer runs.
+func (wal *Wallet) SetTicketBuyerConfig(walletID int, cfg TicketBuyerConfig) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	if cfg.VSPHost == "" {
+		return fmt.Errorf("a VSP is required")
+	}
+	if cfg.BalanceToMaintain < 0 {
+		return fmt.Errorf("balance to maintain cannot be negative")
+	}
+	if _, err := w.GetAccount(cfg.Account); err != nil {
+		return err
+	}
+	if wal.TicketBuyerRunning(walletID) {
+		return ErrTicketBuyerRunning
+	}
+
+	w.SaveUserConfigValue(TicketBuyerConfigKey, cfg)
+	return nil
+}
+
+// TicketBuyerStarted is sent when the auto-buyer of a wallet was started.
+type TicketBuyerStarted struct {
+	WalletID int
+}
+
+// StartTicketBuyerCtx starts buying tickets for a wallet with its saved
+// settings whenever a block is attached. The passphrase is kept in memory
+// until the auto-buyer is stopped, so it does not resume when the app
+// restarts.
+func (wal *Wallet) StartTicketBuyerCtx(ctx context.Context, walletID int, passphrase []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	if w.IsWatchingOnlyWallet() {
+		return fmt.Errorf("watch-only wallets cannot buy tickets")
+	}
+	cfg, err := wal.TicketBuyerConfig(walletID)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return ErrTicketBuyerNotConfigured
+	}
+
+	// Check the passphrase now rather than on the next block.
+	wasLocked := w.IsLocked()
+	if err := w.UnlockWallet(append([]byte(nil), passphrase...)); err != nil {
+		return err
+	}
+	if wasLocked {
+		w.LockWallet()
+	}
+
+	wal.ticketBuyers.mtx.Lock()
+	defer wal.ticketBuyers.mtx.Unlock()
+	if wal.ticketBuyers.running == nil {
+		wal.ticketBuyers.running = make(map[int]*ticketBuyer)
+	}
+	if _, ok := wal.ticketBuyers.running[walletID]; ok {
+		return ErrTicketBuyerRunning
+	}
+	if len(wal.ticketBuyers.running) == 0 {
+		if err := wal.multi.AddTxAndBlockNotificationListener(&ticketBuyerListener{wal}, ticketBuyerListenerID); err != nil {
+			return err
+		}
+	}
+
+	// The auto-buyer outlives ctx, which only covers starting it.
+	buyerCtx, cancel := context.WithCancel(context.Background())
+	wal.ticketBuyers.running[walletID] = &ticketBuyer{
+		walletID:   walletID,
+		cfg:        *cfg,
+		passphrase: append([]byte(nil), passphrase...),
+		ctx:        buyerCtx,
+		cancel:     cancel,
+	}
+	log.Infof("Ticket auto-buyer started for wallet %d", walletID)
+	return nil
+}
+
+// StartTicketBuyer starts the auto-buyer of a wallet after checking the
+// passphrase. It is non-blocking and sends its result or any error to
+// wal.Send.
+func (wal *Wallet) StartTicketBuyer(walletID int, passphrase []byte) RequestID {
+	req := wal.newRequest(OpStartTicketBuyer)
+	go func() {
+		if err := wal.StartTicketBuyerCtx(context.Background(), walletID, passphrase); err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(&TicketBuyerStarted{WalletID: walletID})
+	}()
+	return req.ID
+}
+
+// StopTicketBuyer stops the auto-buyer of a wallet and forgets its passphrase.
+func (wal *Wallet) StopTicketBuyer(walletID int) {
+	wal.ticketBuyers.mtx.Lock()
+	defer wal.ticketBuyers.mtx.Unlock()
+	tb, ok := wal.ticketBuyers.running[walletID]
+	if !ok {
+		return
+	}
+	tb.cancel()
+	for i := range tb.passphrase {
+		tb.passphrase[i] = 0
+	}
+	delete(wal.ticketBuyers.running, walletID)
+	if len(wal.ticketBuyers.running) == 0 {
+		wal.multi.RemoveTxAndBlockNotificationListener(ticketBuyerListenerID)
+	}
+	log.Infof("Ticket auto-buyer stopped for wallet %d", walletID)
+}
+
+// TicketBuyerRunning reports whether the auto-buyer of a wallet runs.
+func (wal *Wallet) TicketBuyerRunning(walletID int) bool {
+	wal.ticketBuyers.mtx.Lock()
+	defer wal.ticketBuyers.mtx.Unlock()
+	_, ok := wal.ticketBuyers.running[walletID]
+	return ok
+}
+
+// TicketBuyerLog returns the purchases and failures of a wallet's auto-buyer
+// since the app started, newest first.
+func (wal *Wallet) TicketBuyerLog(walletID int) []TicketBuyerLogEntry {
+	wal.ticketBuyers.mtx.Lock()
+	defer wal.ticketBuyers.mtx.Unlock()
+	entries := wal.ticketBuyers.logs[walletID]
+	logs := make([]TicketBuyerLogEntry, len(entries))
+	for i, entry := range entries {
+		logs[len(entries)-1-i] = entry
+	}
+	return logs
+}
+
+func (wal *Wallet) addTicketBuyerLog(entry TicketBuyerLogEntry) {
+	if entry.Err != "" {
+		log.Errorf("Ticket auto-buyer for wallet %d: %s", entry.WalletID, entry)
+	} else {
+		log.Infof("Ticket auto-buyer for wallet %d: %s", entry.WalletID, entry)
+	}
+
+	wal.ticketBuyers.mtx.Lock()
+	defer wal.ticketBuyers.mtx.Unlock()
+	if wal.ticketBuyers.logs == nil {
+		wal.ticketBuyers.logs = make(map[int][]TicketBuyerLogEntry)
+	}
+	logs := append(wal.ticketBuyers.logs[entry.WalletID], entry)
+	if len(logs) > ticketBuyerLogSize {
+		logs = logs[len(logs)-ticketBuyerLogSize:]
+	}
+	wal.ticketBuyers.logs[entry.WalletID] = logs
+}
+
+// ticketBuyerListener runs the auto-buyer of a wallet when a block is
+// attached to it.
+type ticketBuyerListener struct {
+	wal *Wallet
+}
+
+func (l *ticketBuyerListener) OnTransaction(transaction string) {}
+
+func (l *ticketBuyerListener) OnTransactionConfirmed(walletID int, hash string, blockHeight int32) {}
+
+func (l *ticketBuyerListener) OnBlockAttached(walletID int, blockHeight int32) {
+	wal := l.wal
+	wal.ticketBuyers.mtx.Lock()
+	tb, ok := wal.ticketBuyers.running[walletID]
+	wal.ticketBuyers.mtx.Unlock()
+	if !ok || !wal.multi.IsSynced() {
+		return
+	}
+	go wal.buyTickets(tb, blockHeight)
+}
+
+// TicketsToBuy returns the number of tickets the auto-buyer buys on a block:
+// as many as the spendable balance above the balance to maintain pays for at
+// price, up to ticketBuyerMaxPerBlock. None are bought while an earlier
+// purchase is still being made.
+func TicketsToBuy(spendable, balanceToMaintain, price int64, buying bool) int {
+	if buying || price <= 0 || spendable <= balanceToMaintain {
+		return 0
+	}
+	tickets := (spendable - balanceToMaintain) / price
+	if tickets > ticketBuyerMaxPerBlock {
+		return ticketBuyerMaxPerBlock
+	}
+	return int(tickets)
+}
+
+// claimTickets returns the tickets TicketsToBuy allows for the account of tb
+// and their price, and marks tb as buying them if there are any. The balance
+// is read under the lock so it is never one a running purchase is about to
+// spend.
+func (wal *Wallet) claimTickets(w *dcrlibwallet.Wallet, tb *ticketBuyer) (int, int64, error) {
+	wal.ticketBuyers.mtx.Lock()
+	defer wal.ticketBuyers.mtx.Unlock()
+
+	balance, err := w.GetAccountBalance(tb.cfg.Account)
+	if err != nil {
+		return 0, 0, err
+	}
+	price, err := w.TicketPrice()
+	if err != nil {
+		return 0, 0, err
+	}
+	tickets := TicketsToBuy(balance.Spendable, tb.cfg.BalanceToMaintain, price.TicketPrice, tb.buying)
+	tb.buying = tickets > 0
+	return tickets, price.TicketPrice, nil
+}
+
+// buyTickets buys the tickets claimTickets allows for the account of tb.
+func (wal *Wallet) buyTickets(tb *ticketBuyer, height int32) {
+	w := wal.multi.WalletWithID(tb.walletID)
+	if w == nil || tb.ctx.Err() != nil {
+		return
+	}
+	entry := TicketBuyerLogEntry{Time: time.Now(), WalletID: tb.walletID, Height: height}
+	fail := func(err error) {
+		entry.Err = err.Error()
+		wal.addTicketBuyerLog(entry)
+	}
+
+	var err error
+	entry.Tickets, entry.Price, err = wal.claimTickets(w, tb)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if entry.Tickets == 0 {
+		return
+	}
+	defer func() {
+		wal.ticketBuyers.mtx.Lock()
+		tb.buying = false
+		wal.ticketBuyers.mtx.Unlock()
+	}()
+
+	if tb.vspd == nil {
+		tb.vspd, err = wal.NewVSPD(tb.cfg.VSPHost, tb.walletID, tb.cfg.Account)
+		if err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	passphrase := append([]byte(nil), tb.passphrase...)
+	err = wal.PurchaseTicketCtx(tb.ctx, tb.walletID, tb.cfg.Account, uint32(entry.Tickets), passphrase, tb.vspd)
+	if err != nil {
+		// Ask the VSP for its details again on the next attempt in case
+		// they changed.
+		tb.vspd = nil
+		fail(err)
+		return
+	}
+	wal.addTicketBuyerLog(entry)
+}
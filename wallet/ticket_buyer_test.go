@@ -0,0 +1,62 @@
+package wallet_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Ticket auto-buyer", func() {
+	It("saves settings per wallet", func() {
+		cfg, err := wal.TicketBuyerConfig(1)
+		Expect(err).To(BeNil())
+		Expect(cfg).To(BeNil())
+		Expect(wal.StartTicketBuyerCtx(context.Background(), 1, []byte("password"))).To(Equal(ErrTicketBuyerNotConfigured))
+
+		Expect(wal.SetTicketBuyerConfig(1, TicketBuyerConfig{})).To(MatchError("a VSP is required"))
+		Expect(wal.SetTicketBuyerConfig(1, TicketBuyerConfig{VSPHost: "https://vsp.example", BalanceToMaintain: -1})).ToNot(Succeed())
+		Expect(wal.SetTicketBuyerConfig(1, TicketBuyerConfig{VSPHost: "https://vsp.example", Account: 7})).ToNot(Succeed())
+		Expect(wal.SetTicketBuyerConfig(99, TicketBuyerConfig{VSPHost: "https://vsp.example"})).To(Equal(ErrIDNotExist))
+
+		saved := TicketBuyerConfig{VSPHost: "https://vsp.example", BalanceToMaintain: 5e8}
+		Expect(wal.SetTicketBuyerConfig(1, saved)).To(Succeed())
+		cfg, err = wal.TicketBuyerConfig(1)
+		Expect(err).To(BeNil())
+		Expect(*cfg).To(Equal(saved))
+	})
+
+	It("starts and stops with the spending passphrase", func() {
+		Expect(wal.SetTicketBuyerConfig(1, TicketBuyerConfig{VSPHost: "https://vsp.example"})).To(Succeed())
+		Expect(wal.StartTicketBuyerCtx(context.Background(), 1, []byte("wrong"))).ToNot(Succeed())
+		Expect(wal.TicketBuyerRunning(1)).To(BeFalse())
+
+		Expect(wal.StartTicketBuyerCtx(context.Background(), 1, []byte("password"))).To(Succeed())
+		Expect(wal.TicketBuyerRunning(1)).To(BeTrue())
+		Expect(wal.StartTicketBuyerCtx(context.Background(), 1, []byte("password"))).To(Equal(ErrTicketBuyerRunning))
+		Expect(wal.SetTicketBuyerConfig(1, TicketBuyerConfig{VSPHost: "https://other.example"})).To(Equal(ErrTicketBuyerRunning))
+
+		wal.StopTicketBuyer(1)
+		Expect(wal.TicketBuyerRunning(1)).To(BeFalse())
+		Expect(wal.TicketBuyerLog(1)).To(BeEmpty())
+	})
+
+	It("buys the tickets the balance above the balance to maintain pays for", func() {
+		const price = 100
+		Expect(TicketsToBuy(350, 100, price, false)).To(Equal(2))
+		Expect(TicketsToBuy(199, 100, price, false)).To(Equal(0))
+		Expect(TicketsToBuy(50, 100, price, false)).To(Equal(0))
+		Expect(TicketsToBuy(10000, 0, price, false)).To(Equal(5))
+		Expect(TicketsToBuy(350, 100, 0, false)).To(Equal(0))
+		Expect(TicketsToBuy(350, 100, price, true)).To(Equal(0))
+	})
+
+	It("describes log entries", func() {
+		entry := TicketBuyerLogEntry{Time: time.Now(), Height: 100, Tickets: 2, Price: 15000000000}
+		Expect(entry.String()).To(Equal("Block 100: bought 2 ticket(s) at 150 DCR"))
+		entry.Err = "insufficient balance"
+		Expect(entry.String()).To(Equal("Block 100: could not buy 2 ticket(s): insufficient balance"))
+	})
+})
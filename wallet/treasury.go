@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+// treasuryPoliciesConfigKey holds the treasury policies of a wallet.
+const treasuryPoliciesConfigKey = "treasury_policies"
+
+// Treasury spend policies.
+const (
+	TreasuryAbstain = "abstain"
+	TreasuryYes     = "yes"
+	TreasuryNo      = "no"
+)
+
+// treasuryPolicies maps tspend hashes and treasury keys to the wallet's
+// policy on them.
+type treasuryPolicies struct {
+	TSpends map[string]string
+	Keys    map[string]string
+}
+
+func readTreasuryPolicies(w *dcrlibwallet.Wallet) treasuryPolicies {
+	var policies treasuryPolicies
+	w.ReadUserConfigValue(treasuryPoliciesConfigKey, &policies)
+	if policies.TSpends == nil {
+		policies.TSpends = make(map[string]string)
+	}
+	if policies.Keys == nil {
+		policies.Keys = make(map[string]string)
+	}
+	return policies
+}
+
+// TreasuryKey is a key that can sign treasury spends.
+type TreasuryKey struct {
+	// PiKey is the hex encoded public key.
+	PiKey  string
+	Policy string
+}
+
+// TSpend is a treasury spend voted on by tickets.
+type TSpend struct {
+	Hash   string
+	Policy string
+}
+
+// TreasuryPolicies holds the treasury keys of the network and the tspends
+// the wallet has a policy on.
+type TreasuryPolicies struct {
+	WalletID int
+	Keys     []TreasuryKey
+	TSpends  []TSpend
+}
+
+// TreasuryPolicySet is sent when Wallet.SetTreasuryPolicy has saved a policy
+// and pushed it to the VSPs.
+type TreasuryPolicySet struct {
+	WalletID int
+	// ID is the tspend hash or treasury key the policy applies to.
+	ID     string
+	Policy string
+	// Updated is the number of tickets whose VSP now votes with the policy.
+	Updated int
+	// Failed holds the error of each ticket whose VSP was not updated.
+	Failed map[string]string
+}
+
+func policyOrAbstain(policies map[string]string, id string) string {
+	if policy, ok := policies[id]; ok {
+		return policy
+	}
+	return TreasuryAbstain
+}
+
+// TreasuryPolicies returns the treasury keys of the network and the tspends
+// the wallet has a policy on. dcrlibwallet does not list the tspends it sees,
+// so tspends are only known once a policy was set on their hash.
+func (wal *Wallet) TreasuryPolicies(walletID int) (*TreasuryPolicies, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	params, err := utils.ChainParams(w.NetType())
+	if err != nil {
+		return nil, err
+	}
+
+	saved := readTreasuryPolicies(w)
+	policies := &TreasuryPolicies{WalletID: walletID}
+	for _, key := range params.PiKeys {
+		piKey := hex.EncodeToString(key)
+		policies.Keys = append(policies.Keys, TreasuryKey{
+			PiKey:  piKey,
+			Policy: policyOrAbstain(saved.Keys, piKey),
+		})
+	}
+
+	for hash, policy := range saved.TSpends {
+		policies.TSpends = append(policies.TSpends, TSpend{Hash: hash, Policy: policy})
+	}
+	sort.SliceStable(policies.TSpends, func(i, j int) bool {
+		return policies.TSpends[i].Hash < policies.TSpends[j].Hash
+	})
+	return policies, nil
+}
+
+// SetTreasuryPolicyCtx saves the wallet's policy on a tspend or treasury key
+// and asks the VSP of each ticket that has not voted yet to vote with it.
+// id is either a tspend hash or the hex encoded key of the network. Tickets
+// whose VSP could not be updated are listed in the result.
+// It blocks until all VSPs are updated or ctx is canceled.
+func (wal *Wallet) SetTreasuryPolicyCtx(ctx context.Context, walletID int, id, policy string, passphrase []byte) (*TreasuryPolicySet, error) {
+	if policy != TreasuryAbstain && policy != TreasuryYes && policy != TreasuryNo {
+		return nil, fmt.Errorf("invalid treasury policy %s", policy)
+	}
+	policies, err := wal.TreasuryPolicies(walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	isKey := false
+	for _, key := range policies.Keys {
+		isKey = isKey || key.PiKey == id
+	}
+	if !isKey {
+		hash, err := chainhash.NewHashFromStr(id)
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither a tspend hash nor a treasury key", id)
+		}
+		id = hash.String()
+	}
+
+	updated, failed, err := wal.syncVSPVoteChoices(ctx, walletID, passphrase, func(w *dcrlibwallet.Wallet) {
+		saved := readTreasuryPolicies(w)
+		if isKey {
+			saved.Keys[id] = policy
+		} else {
+			saved.TSpends[id] = policy
+		}
+		w.SaveUserConfigValue(treasuryPoliciesConfigKey, saved)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TreasuryPolicySet{
+		WalletID: walletID,
+		ID:       id,
+		Policy:   policy,
+		Updated:  updated,
+		Failed:   failed,
+	}, nil
+}
+
+// SetTreasuryPolicy saves the wallet's policy on a tspend or treasury key and
+// pushes it to the VSPs of its tickets.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) SetTreasuryPolicy(walletID int, id, policy string, passphrase []byte) RequestID {
+	req := wal.newRequest(OpSetTreasuryPolicy)
+	go func() {
+		set, err := wal.SetTreasuryPolicyCtx(context.Background(), walletID, id, policy, passphrase)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(set)
+	}()
+	return req.ID
+}
@@ -0,0 +1,54 @@
+package wallet_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Treasury policies", func() {
+	const tspendHash = "2f1e3d9a4c7b6a5d8e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f"
+
+	It("lists the treasury keys of the network", func() {
+		policies, err := wal.TreasuryPolicies(1)
+		Expect(err).To(BeNil())
+		Expect(policies.Keys).NotTo(BeEmpty())
+		for _, key := range policies.Keys {
+			Expect(key.Policy).To(Equal(TreasuryAbstain))
+		}
+
+		_, err = wal.TreasuryPolicies(99)
+		Expect(err).To(Equal(ErrIDNotExist))
+	})
+
+	It("saves policies on keys and tspends", func() {
+		ctx := context.Background()
+		policies, err := wal.TreasuryPolicies(1)
+		Expect(err).To(BeNil())
+		piKey := policies.Keys[0].PiKey
+
+		_, err = wal.SetTreasuryPolicyCtx(ctx, 1, piKey, "maybe", []byte("password"))
+		Expect(err).NotTo(BeNil())
+		_, err = wal.SetTreasuryPolicyCtx(ctx, 1, "not a hash", TreasuryYes, []byte("password"))
+		Expect(err).NotTo(BeNil())
+		_, err = wal.SetTreasuryPolicyCtx(ctx, 1, piKey, TreasuryNo, []byte("wrong"))
+		Expect(err).To(MatchError(dcrlibwallet.ErrInvalidPassphrase))
+
+		set, err := wal.SetTreasuryPolicyCtx(ctx, 1, piKey, TreasuryNo, []byte("password"))
+		Expect(err).To(BeNil())
+		Expect(set.Updated).To(BeZero())
+		_, err = wal.SetTreasuryPolicyCtx(ctx, 1, tspendHash, TreasuryYes, []byte("password"))
+		Expect(err).To(BeNil())
+
+		policies, err = wal.TreasuryPolicies(1)
+		Expect(err).To(BeNil())
+		Expect(policies.Keys[0].Policy).To(Equal(TreasuryNo))
+		Expect(policies.TSpends).To(ContainElement(TSpend{Hash: tspendHash, Policy: TreasuryYes}))
+
+		_, err = wal.SetTreasuryPolicyCtx(ctx, 1, piKey, TreasuryAbstain, []byte("password"))
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+const (
+	txNotesConfigKey      = "tx_notes"
+	addressNotesConfigKey = "address_notes"
+)
+
+// TxNote is a free-text note and tags the user attached to a transaction or
+// a receive address.
+type TxNote struct {
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// NewTxNote returns a note with its text trimmed and its tags normalized:
+// lower case, without a leading '#', sorted and without duplicates.
+func NewTxNote(note string, tags []string) TxNote {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return TxNote{Note: strings.TrimSpace(note), Tags: normalized}
+}
+
+// ParseTags splits a comma or space separated list of tags.
+func ParseTags(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// IsEmpty reports whether the note has neither text nor tags.
+func (n TxNote) IsEmpty() bool {
+	return n.Note == "" && len(n.Tags) == 0
+}
+
+// TagsString returns the tags of the note separated by spaces, each with a
+// leading '#'.
+func (n TxNote) TagsString() string {
+	tags := make([]string, len(n.Tags))
+	for i, tag := range n.Tags {
+		tags[i] = "#" + tag
+	}
+	return strings.Join(tags, " ")
+}
+
+// Matches reports whether query is found in the note text or tags, ignoring
+// case. A query starting with '#' only matches a tag in full.
+func (n TxNote) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	if strings.HasPrefix(query, "#") {
+		for _, tag := range n.Tags {
+			if tag == query[1:] {
+				return true
+			}
+		}
+		return false
+	}
+	if strings.Contains(strings.ToLower(n.Note), query) {
+		return true
+	}
+	for _, tag := range n.Tags {
+		if strings.Contains(tag, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// merge appends the text and tags of other to n.
+func (n TxNote) merge(other TxNote) TxNote {
+	text := n.Note
+	if other.Note != "" && !strings.Contains(text, other.Note) {
+		if text != "" {
+			text += "; "
+		}
+		text += other.Note
+	}
+	return NewTxNote(text, append(append([]string(nil), n.Tags...), other.Tags...))
+}
+
+// TxNotes holds the transaction and receive address notes of a wallet.
+type TxNotes struct {
+	// Txs is keyed by transaction hash.
+	Txs map[string]TxNote
+	// Addresses is keyed by address.
+	Addresses map[string]TxNote
+}
+
+// For returns the note of tx. A transaction without a note of its own takes
+// the notes of the wallet addresses it pays.
+func (n *TxNotes) For(tx *dcrlibwallet.Transaction) TxNote {
+	if note, ok := n.Txs[tx.Hash]; ok {
+		return note
+	}
+	var note TxNote
+	for _, output := range tx.Outputs {
+		if output.AccountNumber < 0 {
+			continue
+		}
+		if addressNote, ok := n.Addresses[output.Address]; ok {
+			note = note.merge(addressNote)
+		}
+	}
+	return note
+}
+
+func readNotes(w *dcrlibwallet.Wallet, key string) map[string]TxNote {
+	notes := make(map[string]TxNote)
+	w.ReadUserConfigValue(key, &notes)
+	return notes
+}
+
+func saveNote(w *dcrlibwallet.Wallet, key, id string, note TxNote) {
+	notes := readNotes(w, key)
+	note = NewTxNote(note.Note, note.Tags)
+	if note.IsEmpty() {
+		delete(notes, id)
+	} else {
+		notes[id] = note
+	}
+	w.SaveUserConfigValue(key, notes)
+}
+
+// TxNotes returns the transaction and receive address notes of a wallet.
+func (wal *Wallet) TxNotes(walletID int) *TxNotes {
+	notes := &TxNotes{Txs: map[string]TxNote{}, Addresses: map[string]TxNote{}}
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return notes
+	}
+	notes.Txs = readNotes(w, txNotesConfigKey)
+	notes.Addresses = readNotes(w, addressNotesConfigKey)
+	return notes
+}
+
+// SetTxNote attaches a note to a transaction of a wallet. An empty note
+// removes it, after which the transaction takes the notes of the addresses
+// it pays again.
+func (wal *Wallet) SetTxNote(walletID int, txHash string, note TxNote) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	saveNote(w, txNotesConfigKey, txHash, note)
+	return nil
+}
+
+// SetAddressNote attaches a note to a receive address of a wallet. It is
+// carried over to the transactions paying the address. An empty note
+// removes it.
+func (wal *Wallet) SetAddressNote(walletID int, address string, note TxNote) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	saveNote(w, addressNotesConfigKey, address, note)
+	return nil
+}
+
+// FilterTxsByNote returns the transactions of txs whose note matches query.
+func FilterTxsByNote(txs []dcrlibwallet.Transaction, notes *TxNotes, query string) []dcrlibwallet.Transaction {
+	if strings.TrimSpace(query) == "" {
+		return txs
+	}
+	var matched []dcrlibwallet.Transaction
+	for i := range txs {
+		if notes.For(&txs[i]).Matches(query) {
+			matched = append(matched, txs[i])
+		}
+	}
+	return matched
+}
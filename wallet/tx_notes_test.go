@@ -0,0 +1,88 @@
+package wallet_test
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("Transaction notes", func() {
+	payment := dcrlibwallet.Transaction{
+		WalletID: 1,
+		Hash:     "payment",
+		Outputs: []*dcrlibwallet.TxOutput{
+			{Address: "Tsexternal", AccountNumber: -1},
+			{Address: "Tsrent", AccountNumber: 0},
+		},
+	}
+
+	It("normalizes and matches notes", func() {
+		note := NewTxNote("  Rent for March ", ParseTags("#Home, rent home"))
+		Expect(note.Note).To(Equal("Rent for March"))
+		Expect(note.Tags).To(Equal([]string{"home", "rent"}))
+		Expect(note.TagsString()).To(Equal("#home #rent"))
+
+		Expect(note.Matches("march")).To(BeTrue())
+		Expect(note.Matches("hom")).To(BeTrue())
+		Expect(note.Matches("#hom")).To(BeFalse())
+		Expect(note.Matches("#HOME")).To(BeTrue())
+		Expect(note.Matches("car")).To(BeFalse())
+		Expect(NewTxNote(" ", []string{"#"}).IsEmpty()).To(BeTrue())
+	})
+
+	It("carries receive address notes over to transactions", func() {
+		notes := &TxNotes{
+			Txs:       map[string]TxNote{},
+			Addresses: map[string]TxNote{"Tsrent": NewTxNote("rent", []string{"home"})},
+		}
+		Expect(notes.For(&payment)).To(Equal(NewTxNote("rent", []string{"home"})))
+
+		notes.Addresses["Tsexternal"] = NewTxNote("not ours", nil)
+		Expect(notes.For(&payment).Note).To(Equal("rent"))
+
+		notes.Txs["payment"] = NewTxNote("paid", nil)
+		Expect(notes.For(&payment).Note).To(Equal("paid"))
+
+		txs := []dcrlibwallet.Transaction{payment, {Hash: "other"}}
+		Expect(FilterTxsByNote(txs, notes, "PAID")).To(Equal(txs[:1]))
+		Expect(FilterTxsByNote(txs, notes, "")).To(HaveLen(2))
+	})
+
+	It("saves notes per wallet", func() {
+		Expect(wal.SetTxNote(1, "payment", NewTxNote("paid", []string{"bills"}))).To(Succeed())
+		Expect(wal.SetAddressNote(1, "Tsrent", NewTxNote("rent", nil))).To(Succeed())
+
+		notes := wal.TxNotes(1)
+		Expect(notes.Txs).To(Equal(map[string]TxNote{"payment": NewTxNote("paid", []string{"bills"})}))
+		Expect(notes.Addresses).To(Equal(map[string]TxNote{"Tsrent": NewTxNote("rent", nil)}))
+
+		Expect(wal.SetTxNote(1, "payment", TxNote{})).To(Succeed())
+		Expect(wal.SetAddressNote(1, "Tsrent", TxNote{})).To(Succeed())
+		Expect(wal.TxNotes(1).Txs).To(BeEmpty())
+		Expect(wal.TxNotes(1).Addresses).To(BeEmpty())
+
+		Expect(wal.SetTxNote(99, "payment", TxNote{})).To(Equal(ErrIDNotExist))
+		Expect(wal.SetAddressNote(99, "Tsrent", TxNote{})).To(Equal(ErrIDNotExist))
+	})
+
+	It("exports notes and tags", func() {
+		var buf bytes.Buffer
+		_, err := WriteTransactions(&buf, []Transaction{{Txn: payment}}, TxExport{
+			Format: ExportCSV,
+			Filter: TxExportFilter{WalletID: 1, Account: -1},
+			Notes: &TxNotes{Txs: map[string]TxNote{
+				"payment": NewTxNote("paid", []string{"bills", "home"}),
+			}},
+		})
+		Expect(err).To(BeNil())
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		Expect(err).To(BeNil())
+		Expect(rows[0][12:]).To(Equal([]string{"note", "tags"}))
+		Expect(rows[1][12:]).To(Equal([]string{"paid", "bills home"}))
+	})
+})
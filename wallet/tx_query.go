@@ -0,0 +1,369 @@
+package wallet
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+// txQueryBatchSize is the number of transactions read from a wallet at a
+// time while looking for matches.
+const txQueryBatchSize = 500
+
+// TxQuery selects a page of transactions across wallets.
+type TxQuery struct {
+	// WalletIDs limits the query to these wallets. All wallets are
+	// searched if it is empty.
+	WalletIDs []int
+	// Account limits the query to transactions with an input or output in
+	// the account. A negative account matches all accounts.
+	Account int32
+	// TxFilter is one of the dcrlibwallet.TxFilter* values, e.g.
+	// TxFilterSent, TxFilterReceived or TxFilterStaking.
+	TxFilter int32
+	// MinAmount and MaxAmount bound the transaction amount in atoms. Zero
+	// leaves that end of the range open.
+	MinAmount, MaxAmount int64
+	// From and To bound the transaction time to [From, To). A zero time
+	// leaves that end of the range open.
+	From, To time.Time
+	// Address matches transactions with an output paying it.
+	Address string
+	// HashPrefix matches transactions whose hash starts with it.
+	HashPrefix string
+	// Search matches a hash prefix, part of an address or the note and
+	// tags of a transaction, see TxNote.Matches.
+	Search      string
+	NewestFirst bool
+	// After resumes the query where the page it was returned with ended.
+	// The first page is read if it is nil.
+	After TxCursor
+	// Limit is the most transactions returned. A zero limit returns all
+	// matches.
+	Limit int
+}
+
+// TxPage is sent when Wallet.QueryTransactions has read a page of
+// transactions.
+type TxPage struct {
+	Txs []dcrlibwallet.Transaction
+	// HasMore is set if more transactions match after this page.
+	HasMore bool
+	// Cursor is set with HasMore, it is passed as TxQuery.After to read the
+	// next page.
+	Cursor TxCursor
+}
+
+// TxCursor is where a page of transactions ended in each wallet, keyed by
+// wallet ID.
+type TxCursor map[int]TxWalletCursor
+
+// TxWalletCursor is the last transaction of a wallet read for a page. Every
+// transaction of the wallet up to it was either returned or did not match.
+type TxWalletCursor struct {
+	Timestamp int64
+	// Hash is empty if no transaction of the wallet was read.
+	Hash string
+	// Offset is the position after the transaction in the order the wallet
+	// lists transactions for the query. The transaction is looked up there
+	// first and only searched for from the start if it moved.
+	Offset int32
+	// Done is set once all transactions of the wallet were read.
+	Done bool
+}
+
+// paysAddress reports whether an output of txn pays an address matched by
+// match. Inputs are not checked as dcrlibwallet does not record the address
+// they spend from.
+func paysAddress(txn *dcrlibwallet.Transaction, match func(string) bool) bool {
+	for _, output := range txn.Outputs {
+		if match(output.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether txn is selected by the query. notes are the notes of
+// the wallet of txn, they are only used to search.
+func (q *TxQuery) Match(txn *dcrlibwallet.Transaction, notes *TxNotes) bool {
+	if !q.matchesWallet(txn.WalletID) {
+		return false
+	}
+
+	filter := TxExportFilter{WalletID: txn.WalletID, Account: q.Account, From: q.From, To: q.To}
+	if !filter.Match(txn) {
+		return false
+	}
+	if q.MinAmount > 0 && txn.Amount < q.MinAmount {
+		return false
+	}
+	if q.MaxAmount > 0 && txn.Amount > q.MaxAmount {
+		return false
+	}
+	if q.Address != "" && !paysAddress(txn, func(address string) bool { return address == q.Address }) {
+		return false
+	}
+	if q.HashPrefix != "" && !strings.HasPrefix(txn.Hash, strings.ToLower(q.HashPrefix)) {
+		return false
+	}
+
+	search := strings.TrimSpace(q.Search)
+	if search == "" {
+		return true
+	}
+	if strings.HasPrefix(txn.Hash, strings.ToLower(search)) {
+		return true
+	}
+	if paysAddress(txn, func(address string) bool { return address != "" && strings.Contains(address, search) }) {
+		return true
+	}
+	return notes != nil && notes.For(txn).Matches(search)
+}
+
+// pastRange reports whether txn and all transactions after it in the read
+// order are outside the time range of the query.
+func (q *TxQuery) pastRange(txn *dcrlibwallet.Transaction) bool {
+	t := time.Unix(txn.Timestamp, 0)
+	if q.NewestFirst {
+		return !q.From.IsZero() && t.Before(q.From)
+	}
+	return !q.To.IsZero() && !t.Before(q.To)
+}
+
+// txCursor reads the transactions of a wallet in batches, in the order of
+// the query.
+type txCursor struct {
+	walletID int
+	source   TxSource
+	notes    *TxNotes
+	query    *TxQuery
+	// offset is the position of the next batch.
+	offset int32
+	buf    []dcrlibwallet.Transaction
+	done   bool
+	// last is the last transaction dropped, either returned or not matching.
+	last TxWalletCursor
+	// after is the last transaction of the previous page. seeking is set
+	// while reading up to it and restarted once it was searched for from
+	// the start.
+	after     TxWalletCursor
+	seeking   bool
+	restarted bool
+}
+
+// newTxCursor returns a cursor reading the transactions of a wallet after
+// the cursor of the previous page.
+func newTxCursor(walletID int, source TxSource, notes *TxNotes, q *TxQuery, after TxWalletCursor) *txCursor {
+	c := &txCursor{walletID: walletID, source: source, notes: notes, query: q, last: after, after: after, done: after.Done}
+	if after.Hash != "" && !after.Done {
+		c.seeking = true
+		// Read from the last transaction itself to check it did not move.
+		c.offset = after.Offset - 1
+		if c.offset < 0 {
+			c.offset = 0
+		}
+	}
+	return c
+}
+
+// beforeLast reports whether txn comes before the last transaction of the
+// previous page in the order of the query.
+func (c *txCursor) beforeLast(txn *dcrlibwallet.Transaction) bool {
+	if c.query.NewestFirst {
+		return txn.Timestamp >= c.after.Timestamp
+	}
+	return txn.Timestamp <= c.after.Timestamp
+}
+
+// seek drops the transactions up to the last one of the previous page. It
+// reports false if the last transaction was not found where it was, in which
+// case the wallet is read again from the start.
+func (c *txCursor) seek(txn *dcrlibwallet.Transaction) (dropped bool) {
+	if txn.Hash == c.after.Hash {
+		c.seeking = false
+		return true
+	}
+	if c.beforeLast(txn) {
+		// A transaction with the same time as the last one, or one added
+		// since the previous page was read.
+		return true
+	}
+	if !c.restarted && c.offset-int32(len(c.buf)) > 0 {
+		// Transactions were removed before the last one, read from the
+		// start to find it.
+		c.offset, c.buf, c.done, c.restarted = 0, nil, false, true
+		return false
+	}
+	// The last transaction is gone.
+	c.seeking = false
+	return false
+}
+
+// next returns the next matching transaction of the wallet, or nil once
+// there is none.
+func (c *txCursor) next(ctx context.Context) (*dcrlibwallet.Transaction, error) {
+	for {
+		for len(c.buf) > 0 {
+			txn := &c.buf[0]
+			if c.seeking {
+				if c.seek(txn) {
+					c.drop()
+				}
+				continue
+			}
+			if c.query.pastRange(txn) {
+				c.buf, c.done = nil, true
+				return nil, nil
+			}
+			if c.query.Match(txn, c.notes) {
+				return txn, nil
+			}
+			c.drop()
+		}
+		if c.done {
+			c.seeking = false
+			return nil, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		txs, err := c.source.GetTransactionsRaw(c.offset, txQueryBatchSize, c.query.TxFilter, c.query.NewestFirst)
+		if err != nil {
+			return nil, err
+		}
+		c.offset += int32(len(txs))
+		c.buf = txs
+		c.done = len(txs) < txQueryBatchSize
+	}
+}
+
+// drop moves past the transaction last returned by next, or the one being
+// looked at.
+func (c *txCursor) drop() {
+	txn := &c.buf[0]
+	c.buf = c.buf[1:]
+	c.last = TxWalletCursor{
+		Timestamp: txn.Timestamp,
+		Hash:      txn.Hash,
+		Offset:    c.offset - int32(len(c.buf)),
+	}
+}
+
+// cursor returns where the wallet was read up to.
+func (c *txCursor) cursor() TxWalletCursor {
+	last := c.last
+	last.Done = c.done && len(c.buf) == 0
+	return last
+}
+
+// TxSource lists the transactions of a wallet. It is implemented by
+// *dcrlibwallet.Wallet.
+type TxSource interface {
+	GetTransactionsRaw(offset, limit, txFilter int32, newestFirst bool) ([]dcrlibwallet.Transaction, error)
+}
+
+// QueryTransactionsCtx returns the page of transactions of q from the
+// wallets, see ReadTxPage.
+// It blocks until the page is read or ctx is canceled.
+func (wal *Wallet) QueryTransactionsCtx(ctx context.Context, q TxQuery) (*TxPage, error) {
+	wallets, err := wal.wallets()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[int]TxSource)
+	notes := make(map[int]*TxNotes)
+	for i := range wallets {
+		wall := &wallets[i]
+		if q.matchesWallet(wall.ID) {
+			sources[wall.ID] = wall
+			notes[wall.ID] = wal.TxNotes(wall.ID)
+		}
+	}
+	return ReadTxPage(ctx, sources, notes, q)
+}
+
+// ReadTxPage returns the page of transactions of q from sources, keyed by
+// wallet ID, merging the transactions of the wallets in time order. Wallets
+// are read in batches until the page is full so large wallets are not loaded
+// whole, and the next page resumes from the cursor of the previous one.
+// notes are the notes of each wallet, they are only used to search.
+func ReadTxPage(ctx context.Context, sources map[int]TxSource, notes map[int]*TxNotes, q TxQuery) (*TxPage, error) {
+	ids := make([]int, 0, len(sources))
+	for id := range sources {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	cursors := make([]*txCursor, len(ids))
+	for i, id := range ids {
+		cursors[i] = newTxCursor(id, sources[id], notes[id], &q, q.After[id])
+	}
+
+	page := &TxPage{}
+	for {
+		var next *txCursor
+		var nextTxn *dcrlibwallet.Transaction
+		for _, c := range cursors {
+			txn, err := c.next(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if txn == nil {
+				continue
+			}
+			if nextTxn == nil || (q.NewestFirst && txn.Timestamp > nextTxn.Timestamp) ||
+				(!q.NewestFirst && txn.Timestamp < nextTxn.Timestamp) {
+				next, nextTxn = c, txn
+			}
+		}
+		if next == nil {
+			return page, nil
+		}
+
+		if q.Limit > 0 && len(page.Txs) == q.Limit {
+			page.HasMore = true
+			cursor := make(TxCursor, len(cursors))
+			for _, c := range cursors {
+				cursor[c.walletID] = c.cursor()
+			}
+			page.Cursor = cursor
+			return page, nil
+		}
+		page.Txs = append(page.Txs, *nextTxn)
+		next.drop()
+	}
+}
+
+// matchesWallet reports whether the query includes the wallet.
+func (q *TxQuery) matchesWallet(walletID int) bool {
+	if len(q.WalletIDs) == 0 {
+		return true
+	}
+	for _, id := range q.WalletIDs {
+		if id == walletID {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryTransactions returns the page of transactions of q.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) QueryTransactions(q TxQuery) RequestID {
+	req := wal.newRequest(OpQueryTransactions)
+	go func() {
+		page, err := wal.QueryTransactionsCtx(context.Background(), q)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(page)
+	}()
+	return req.ID
+}
@@ -0,0 +1,177 @@
+package wallet_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+// txList lists transactions newest first and records the offsets read.
+type txList struct {
+	txs     []dcrlibwallet.Transaction
+	offsets []int32
+}
+
+func (l *txList) GetTransactionsRaw(offset, limit, _ int32, newestFirst bool) ([]dcrlibwallet.Transaction, error) {
+	l.offsets = append(l.offsets, offset)
+	txs := make([]dcrlibwallet.Transaction, len(l.txs))
+	copy(txs, l.txs)
+	if !newestFirst {
+		for i, j := 0, len(txs)-1; i < j; i, j = i+1, j-1 {
+			txs[i], txs[j] = txs[j], txs[i]
+		}
+	}
+	if int(offset) >= len(txs) {
+		return nil, nil
+	}
+	txs = txs[offset:]
+	if int(limit) < len(txs) {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
+
+func listTxs(walletID int, hashes ...string) *txList {
+	l := &txList{}
+	for i, hash := range hashes {
+		l.txs = append(l.txs, dcrlibwallet.Transaction{
+			WalletID:  walletID,
+			Hash:      hash,
+			Timestamp: int64(100 - 10*i),
+		})
+	}
+	return l
+}
+
+func pageHashes(page *TxPage) []string {
+	var hashes []string
+	for _, txn := range page.Txs {
+		hashes = append(hashes, txn.Hash)
+	}
+	return hashes
+}
+
+var _ = Describe("Transaction queries", func() {
+	day := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	txn := &dcrlibwallet.Transaction{
+		WalletID:  1,
+		Hash:      "ab12cd",
+		Timestamp: day.Unix(),
+		Amount:    5e8,
+		Inputs:    []*dcrlibwallet.TxInput{{AccountNumber: 1}},
+		Outputs: []*dcrlibwallet.TxOutput{
+			{Address: "TsRecipient", AccountNumber: -1},
+			{Address: "TsChange", AccountNumber: 1},
+		},
+	}
+	notes := &TxNotes{Txs: map[string]TxNote{"ab12cd": NewTxNote("rent", []string{"home"})}}
+
+	It("matches transactions by each filter", func() {
+		match := func(q TxQuery) bool {
+			return q.Match(txn, notes)
+		}
+		Expect(match(TxQuery{Account: -1})).To(BeTrue())
+
+		Expect(match(TxQuery{Account: -1, WalletIDs: []int{1, 2}})).To(BeTrue())
+		Expect(match(TxQuery{Account: -1, WalletIDs: []int{2}})).To(BeFalse())
+		Expect(match(TxQuery{Account: 1})).To(BeTrue())
+		Expect(match(TxQuery{Account: 0})).To(BeFalse())
+
+		Expect(match(TxQuery{Account: -1, MinAmount: 5e8, MaxAmount: 6e8})).To(BeTrue())
+		Expect(match(TxQuery{Account: -1, MinAmount: 6e8})).To(BeFalse())
+		Expect(match(TxQuery{Account: -1, MaxAmount: 4e8})).To(BeFalse())
+
+		Expect(match(TxQuery{Account: -1, From: day, To: day.Add(time.Hour)})).To(BeTrue())
+		Expect(match(TxQuery{Account: -1, From: day.Add(time.Second)})).To(BeFalse())
+		Expect(match(TxQuery{Account: -1, To: day})).To(BeFalse())
+
+		Expect(match(TxQuery{Account: -1, Address: "TsChange"})).To(BeTrue())
+		Expect(match(TxQuery{Account: -1, Address: "TsChang"})).To(BeFalse())
+		Expect(match(TxQuery{Account: -1, HashPrefix: "AB12"})).To(BeTrue())
+		Expect(match(TxQuery{Account: -1, HashPrefix: "cd"})).To(BeFalse())
+	})
+
+	It("searches hashes, addresses and notes", func() {
+		search := func(s string) bool {
+			q := TxQuery{Account: -1, Search: s}
+			return q.Match(txn, notes)
+		}
+		Expect(search("ab1")).To(BeTrue())
+		Expect(search("Recip")).To(BeTrue())
+		Expect(search("RENT")).To(BeTrue())
+		Expect(search("#home")).To(BeTrue())
+		Expect(search("12cd")).To(BeFalse())
+		Expect(search("groceries")).To(BeFalse())
+
+		q := TxQuery{Account: -1, Search: "rent"}
+		Expect(q.Match(txn, nil)).To(BeFalse())
+	})
+
+	It("pages through the wallets from a cursor", func() {
+		ctx := context.Background()
+		first := listTxs(1, "a1", "a2", "a3", "a4", "a5")
+		second := listTxs(2, "b1", "b2")
+		second.txs[0].Timestamp, second.txs[1].Timestamp = 95, 65
+		sources := map[int]TxSource{1: first, 2: second}
+		q := TxQuery{Account: -1, NewestFirst: true, Limit: 3}
+
+		page, err := ReadTxPage(ctx, sources, nil, q)
+		Expect(err).To(BeNil())
+		Expect(pageHashes(page)).To(Equal([]string{"a1", "b1", "a2"}))
+		Expect(page.HasMore).To(BeTrue())
+		Expect(page.Cursor[1]).To(Equal(TxWalletCursor{Timestamp: 90, Hash: "a2", Offset: 2}))
+
+		// A transaction received in between is not read into the next page.
+		first.txs = append([]dcrlibwallet.Transaction{{WalletID: 1, Hash: "a0", Timestamp: 110}}, first.txs...)
+		first.offsets = nil
+		q.After = page.Cursor
+		page, err = ReadTxPage(ctx, sources, nil, q)
+		Expect(err).To(BeNil())
+		Expect(pageHashes(page)).To(Equal([]string{"a3", "a4", "b2"}))
+		Expect(first.offsets[0]).To(BeEquivalentTo(1))
+
+		q.After = page.Cursor
+		page, err = ReadTxPage(ctx, sources, nil, q)
+		Expect(err).To(BeNil())
+		Expect(pageHashes(page)).To(Equal([]string{"a5"}))
+		Expect(page.HasMore).To(BeFalse())
+		Expect(page.Cursor).To(BeNil())
+	})
+
+	It("finds the cursor again after transactions before it are removed", func() {
+		ctx := context.Background()
+		list := listTxs(1, "a1", "a2", "a3", "a4")
+		sources := map[int]TxSource{1: list}
+		q := TxQuery{Account: -1, NewestFirst: true, Limit: 2}
+
+		page, err := ReadTxPage(ctx, sources, nil, q)
+		Expect(err).To(BeNil())
+		Expect(pageHashes(page)).To(Equal([]string{"a1", "a2"}))
+
+		list.txs = list.txs[1:]
+		q.After = page.Cursor
+		page, err = ReadTxPage(ctx, sources, nil, q)
+		Expect(err).To(BeNil())
+		Expect(pageHashes(page)).To(Equal([]string{"a3", "a4"}))
+	})
+
+	It("reads an empty page from a wallet without transactions", func() {
+		page, err := wal.QueryTransactionsCtx(context.Background(), TxQuery{
+			Account:     -1,
+			NewestFirst: true,
+			Limit:       20,
+		})
+		Expect(err).To(BeNil())
+		Expect(page.Txs).To(BeEmpty())
+		Expect(page.HasMore).To(BeFalse())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = wal.QueryTransactionsCtx(ctx, TxQuery{Account: -1})
+		Expect(err).To(Equal(context.Canceled))
+	})
+})
@@ -0,0 +1,357 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	werrors "decred.org/dcrwallet/errors"
+	"decred.org/dcrwallet/wallet/txauthor"
+	"decred.org/dcrwallet/wallet/txsizes"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/dcrlibwallet/txhelper"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+// maxUnsignedTxSize is the largest signed size of a transaction built for
+// offline signing, the standard transaction size limit of dcrd.
+const maxUnsignedTxSize = 100000
+
+var (
+	// ErrUnsignedTxMismatch is returned when the inputs listed with an
+	// unsigned transaction do not match the transaction.
+	ErrUnsignedTxMismatch = errors.New("unsigned transaction inputs do not match the transaction")
+
+	// ErrChangeMismatch is returned when the change output of an unsigned
+	// transaction does not pay the internal address it is listed with.
+	ErrChangeMismatch = errors.New("the change output does not pay the account of this transaction")
+)
+
+// UnsignedTx is a transaction built from the outputs of a watch-only wallet,
+// which cannot sign it. It is exported as JSON, to a file or a QR code, and
+// signed with SignUnsignedTx by a godcr instance that holds the seed.
+type UnsignedTx struct {
+	Network string `json:"network"`
+	// AccountXpub is the extended public key of the account spent from,
+	// which the signer looks up among the accounts of its seed.
+	AccountXpub string `json:"accountXpub"`
+	// Tx is the hex encoded unsigned transaction.
+	Tx     string            `json:"tx"`
+	Inputs []UnsignedTxInput `json:"inputs"`
+	// ChangeIndex is the output paying change back to the wallet, or -1.
+	// SignUnsignedTx refuses to sign unless it pays the internal address
+	// at ChangeAddressIndex of the account.
+	ChangeIndex        int    `json:"changeIndex"`
+	ChangeAddressIndex uint32 `json:"changeAddressIndex"`
+}
+
+// UnsignedTxOutput is an output of an unsigned transaction.
+type UnsignedTxOutput struct {
+	Address string
+	Amount  int64
+	// Change is true for the output listed as change, which is only known
+	// to pay the wallet once SignUnsignedTx has checked it.
+	Change bool
+}
+
+// UnsignedTxInput is the previous output spent by an input of an unsigned
+// transaction and the key path of the address it pays, which the signer needs
+// to sign the input.
+type UnsignedTxInput struct {
+	TxID         string `json:"txid"`
+	Vout         uint32 `json:"vout"`
+	Tree         int8   `json:"tree"`
+	ScriptPubKey string `json:"scriptPubKey"`
+	Amount       int64  `json:"amount"`
+	Branch       uint32 `json:"branch"`
+	Index        uint32 `json:"index"`
+}
+
+// CreateUnsignedTx builds a transaction paying recipients from the confirmed,
+// unfrozen outputs of account 0 of a watch-only wallet without signing it,
+// paying the fee rate from UnsignedTxFeeRate. Change goes to an unused
+// internal address derived from the extended public key of the account
+// unless a recipient receives the max amount.
+func (wal *Wallet) CreateUnsignedTx(walletID int, account int32, recipients []Recipient) (*UnsignedTx, error) {
+	if err := ValidateRecipients(recipients); err != nil {
+		return nil, err
+	}
+
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	keys, err := wal.watchOnlyKeys(w, account)
+	if err != nil {
+		return nil, err
+	}
+	params := keys.params
+	externalCount, internalCount, err := keyCounts(w, account)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := keys.scriptPaths(externalCount, internalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []*wire.TxOut
+	var changeSource txauthor.ChangeSource
+	sendMax := false
+	for _, r := range recipients {
+		if r.SendMax {
+			sendMax = true
+			changeSource, err = txhelper.MakeTxChangeSource(r.Address, params)
+		} else {
+			var output *wire.TxOut
+			output, err = txhelper.MakeTxOutput(r.Address, r.Amount, params)
+			outputs = append(outputs, output)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", r.Address, err)
+		}
+	}
+
+	utxos, err := w.UnspentOutputs(account)
+	if err != nil {
+		return nil, err
+	}
+	frozen := readFrozenUTXOs(w)
+	confirmed := utxos[:0]
+	for _, utxo := range utxos {
+		_, known := paths[hex.EncodeToString(utxo.PkScript)]
+		if known && utxo.Confirmations >= w.RequiredConfirmations() && !frozen[utxo.OutputKey] {
+			confirmed = append(confirmed, utxo)
+		}
+	}
+	sort.Slice(confirmed, func(i, j int) bool {
+		return confirmed[i].Amount > confirmed[j].Amount
+	})
+
+	changeIndex := nextChangeIndex(w, internalCount)
+	if changeSource == nil {
+		address, err := keys.address(internalBranch, changeIndex)
+		if err != nil {
+			return nil, err
+		}
+		changeSource, err = txhelper.MakeTxChangeSource(address.Address(), params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var spent []*dcrlibwallet.UnspentOutput
+	inputSource := func(target dcrutil.Amount) (*txauthor.InputDetail, error) {
+		detail := new(txauthor.InputDetail)
+		spent = spent[:0]
+		for _, utxo := range confirmed {
+			if !sendMax && detail.Amount >= target {
+				break
+			}
+			hash, err := chainhash.NewHash(utxo.TransactionHash)
+			if err != nil {
+				return nil, err
+			}
+			op := wire.NewOutPoint(hash, utxo.OutputIndex, int8(utxo.Tree))
+			detail.Inputs = append(detail.Inputs, wire.NewTxIn(op, utxo.Amount, nil))
+			detail.Scripts = append(detail.Scripts, utxo.PkScript)
+			detail.RedeemScriptSizes = append(detail.RedeemScriptSizes, txsizes.RedeemP2PKHSigScriptSize)
+			detail.Amount += dcrutil.Amount(utxo.Amount)
+			spent = append(spent, utxo)
+		}
+		return detail, nil
+	}
+
+	feeRate := dcrutil.Amount(wal.UnsignedTxFeeRate().AtomsPerKB)
+	authored, err := txauthor.NewUnsignedTransaction(outputs, feeRate, inputSource, changeSource, maxUnsignedTxSize)
+	if err != nil {
+		if werrors.Is(err, werrors.InsufficientBalance) {
+			return nil, errors.New(dcrlibwallet.ErrInsufficientBalance)
+		}
+		return nil, err
+	}
+	if authored.ChangeIndex >= 0 {
+		authored.RandomizeChangePosition()
+	}
+
+	var buf bytes.Buffer
+	if err := authored.Tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	tx := &UnsignedTx{
+		Network:     w.NetType(),
+		AccountXpub: keys.xpub,
+		Tx:          hex.EncodeToString(buf.Bytes()),
+		Inputs:      make([]UnsignedTxInput, len(spent)),
+		ChangeIndex: authored.ChangeIndex,
+	}
+	if sendMax {
+		// The max amount recipient is paid as change but is a payment.
+		tx.ChangeIndex = -1
+	} else {
+		tx.ChangeAddressIndex = changeIndex
+	}
+	for i, utxo := range spent {
+		in := authored.Tx.TxIn[i].PreviousOutPoint
+		script := hex.EncodeToString(utxo.PkScript)
+		tx.Inputs[i] = UnsignedTxInput{
+			TxID:         in.Hash.String(),
+			Vout:         in.Index,
+			Tree:         in.Tree,
+			ScriptPubKey: script,
+			Amount:       utxo.Amount,
+			Branch:       paths[script].branch,
+			Index:        paths[script].index,
+		}
+	}
+
+	// The next transaction pays change to the next address so that both
+	// can be signed and broadcast in any order.
+	if !sendMax && authored.ChangeIndex >= 0 {
+		w.SaveUserConfigValue(changeIndexConfigKey, changeIndex+1)
+	}
+	return tx, nil
+}
+
+// MsgTx decodes the transaction and checks that it spends the listed inputs.
+func (tx *UnsignedTx) MsgTx() (*wire.MsgTx, error) {
+	b, err := hex.DecodeString(tx.Tx)
+	if err != nil {
+		return nil, err
+	}
+	msgTx := new(wire.MsgTx)
+	if err := msgTx.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	if len(msgTx.TxIn) != len(tx.Inputs) {
+		return nil, ErrUnsignedTxMismatch
+	}
+	for i, in := range msgTx.TxIn {
+		op := in.PreviousOutPoint
+		if op.Hash.String() != tx.Inputs[i].TxID || op.Index != tx.Inputs[i].Vout || op.Tree != tx.Inputs[i].Tree ||
+			in.ValueIn != tx.Inputs[i].Amount {
+			return nil, ErrUnsignedTxMismatch
+		}
+	}
+	if tx.ChangeIndex < -1 || tx.ChangeIndex >= len(msgTx.TxOut) {
+		return nil, ErrChangeMismatch
+	}
+	return msgTx, nil
+}
+
+// Fee returns the listed input amounts less the outputs of the transaction.
+// Signatures do not commit to input amounts and the signer has no copy of
+// the previous outputs, so the fee is only as accurate as the file.
+func (tx *UnsignedTx) Fee() (int64, error) {
+	msgTx, err := tx.MsgTx()
+	if err != nil {
+		return 0, err
+	}
+	var fee int64
+	for _, in := range tx.Inputs {
+		fee += in.Amount
+	}
+	for _, out := range msgTx.TxOut {
+		fee -= out.Value
+	}
+	if fee < 0 {
+		return 0, errors.New("the transaction pays more than its inputs")
+	}
+	return fee, nil
+}
+
+// Write writes the unsigned transaction as JSON.
+func (tx *UnsignedTx) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tx)
+}
+
+// ReadUnsignedTx reads an unsigned transaction written by Write.
+func ReadUnsignedTx(r io.Reader) (*UnsignedTx, error) {
+	tx := new(UnsignedTx)
+	if err := json.NewDecoder(r).Decode(tx); err != nil {
+		return nil, err
+	}
+	if _, err := tx.MsgTx(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Outputs returns every output of the transaction, including the one listed
+// as change.
+func (tx *UnsignedTx) Outputs() ([]UnsignedTxOutput, error) {
+	msgTx, err := tx.MsgTx()
+	if err != nil {
+		return nil, err
+	}
+	params, err := utils.ChainParams(tx.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]UnsignedTxOutput, len(msgTx.TxOut))
+	for i, out := range msgTx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(out.Version, out.PkScript, params, false)
+		if err != nil || len(addrs) != 1 {
+			return nil, fmt.Errorf("output %d does not pay an address", i)
+		}
+		outputs[i] = UnsignedTxOutput{Address: addrs[0].Address(), Amount: out.Value, Change: i == tx.ChangeIndex}
+	}
+	return outputs, nil
+}
+
+// ExportUnsignedTxFile writes the unsigned transaction to the exports
+// directory and returns the path of the file.
+func (wal *Wallet) ExportUnsignedTxFile(tx *UnsignedTx) (string, error) {
+	return wal.exportTxFile("unsigned-tx", tx.Write)
+}
+
+// ReadUnsignedTxFile reads an unsigned transaction exported by
+// ExportUnsignedTxFile.
+func ReadUnsignedTxFile(path string) (*UnsignedTx, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadUnsignedTx(file)
+}
+
+// exportTxFile writes a transaction with write to a new file of the exports
+// directory named after prefix and returns the path of the file.
+func (wal *Wallet) exportTxFile(prefix string, write func(io.Writer) error) (string, error) {
+	dir := filepath.Join(wal.root, exportDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.json", prefix, time.Now().Format("20060102-150405")))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,137 @@
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/dcrd/wire"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+// coldSeed is the seed of the offline wallet the watch-only wallets of these
+// tests watch.
+var coldSeed = bytes.Repeat([]byte{7}, 32)
+
+// coldAccount derives account of coldSeed under the testnet SLIP0044 coin
+// type.
+func coldAccount(account uint32) *hdkeychain.ExtendedKey {
+	key, err := hdkeychain.NewMaster(coldSeed, chaincfg.TestNet3Params())
+	Expect(err).To(BeNil())
+	for _, i := range []uint32{44, 1, account} {
+		key, err = key.Child(i + hdkeychain.HardenedKeyStart)
+		Expect(err).To(BeNil())
+	}
+	return key
+}
+
+// coldAddress returns the address at index of branch of account 0.
+func coldAddress(branch, index uint32) dcrutil.Address {
+	key, err := coldAccount(0).Child(branch)
+	Expect(err).To(BeNil())
+	key, err = key.Child(index)
+	Expect(err).To(BeNil())
+	addr, err := dcrutil.NewAddressPubKeyHash(dcrutil.Hash160(key.SerializedPubKey()),
+		chaincfg.TestNet3Params(), dcrec.STEcdsaSecp256k1)
+	Expect(err).To(BeNil())
+	return addr
+}
+
+// importColdWallet imports a watch-only wallet of account 0 of coldSeed and
+// returns its ID.
+func importColdWallet() int {
+	xpub := coldAccount(0).Neuter().String()
+	Expect(wal.ImportWatchOnlyWallet("cold", xpub)).To(Succeed())
+	info, err := wal.GetMultiWalletInfoCtx(context.Background())
+	Expect(err).To(BeNil())
+	for _, w := range info.Wallets {
+		if w.Name == "cold" {
+			return w.ID
+		}
+	}
+	Fail("the watch-only wallet was not imported")
+	return 0
+}
+
+var _ = Describe("Unsigned transactions", func() {
+	recipients := []Recipient{{Address: "TsfDLrRkk9ciUuwfp2b8PawwnukYD7yAjGd", Amount: 1e8}}
+
+	It("builds unsigned transactions from watch-only wallets", func() {
+		ctx := context.Background()
+		id := importColdWallet()
+		defer func() {
+			Expect(wal.DeleteWalletCtx(ctx, id, nil)).To(Succeed())
+		}()
+
+		xpub, err := wal.AccountXpub(id)
+		Expect(err).To(BeNil())
+		Expect(xpub).To(Equal(coldAccount(0).Neuter().String()))
+		Expect(wal.SetAccountXpub(id, coldAccount(1).Neuter().String())).To(Equal(ErrXpubMismatch))
+		Expect(wal.SetAccountXpub(id, coldAccount(0).String())).NotTo(Succeed())
+		Expect(wal.SetAccountXpub(id, xpub)).To(Succeed())
+
+		// Change goes to an internal address of the account, not to an
+		// external one that would be handed out to payers.
+		change, err := wal.ChangeAddress(id, 0)
+		Expect(err).To(BeNil())
+		Expect(change).To(Equal(coldAddress(1, 0).Address()))
+		_, err = wal.ChangeAddress(id, 1)
+		Expect(err).To(MatchError(ContainSubstring(ErrNoAccountXpub.Error())))
+
+		_, err = wal.CreateUnsignedTx(id, 0, recipients)
+		Expect(err).To(MatchError(dcrlibwallet.ErrInsufficientBalance))
+		_, err = wal.CreateUnsignedTx(id, 0, nil)
+		Expect(err).To(Equal(ErrNoRecipients))
+		_, err = wal.CreateUnsignedTx(99, 0, recipients)
+		Expect(err).To(Equal(ErrIDNotExist))
+	})
+
+	It("needs a watch-only wallet", func() {
+		_, err := wal.CreateUnsignedTx(1, 0, recipients)
+		Expect(err).NotTo(BeNil())
+		_, err = wal.AccountXpub(1)
+		Expect(err).To(Equal(ErrNoAccountXpub))
+	})
+
+	It("writes and reads unsigned transactions", func() {
+		hash := chainhash.HashH([]byte("prev"))
+		msgTx := wire.NewMsgTx()
+		msgTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hash, 2, wire.TxTreeRegular), 5e8, nil))
+		msgTx.AddTxOut(wire.NewTxOut(4e8, []byte{0x76}))
+		var buf bytes.Buffer
+		Expect(msgTx.Serialize(&buf)).To(Succeed())
+
+		tx := &UnsignedTx{
+			Network:     "testnet3",
+			AccountXpub: coldAccount(0).Neuter().String(),
+			Tx:          hex.EncodeToString(buf.Bytes()),
+			Inputs:      []UnsignedTxInput{{TxID: hash.String(), Vout: 2, ScriptPubKey: "76", Amount: 5e8, Index: 4}},
+			ChangeIndex: -1,
+		}
+		buf.Reset()
+		Expect(tx.Write(&buf)).To(Succeed())
+		read, err := ReadUnsignedTx(&buf)
+		Expect(err).To(BeNil())
+		Expect(read).To(Equal(tx))
+
+		fee, err := read.Fee()
+		Expect(err).To(BeNil())
+		Expect(fee).To(Equal(int64(1e8)))
+
+		tx.Inputs[0].Amount = 3e8
+		_, err = tx.MsgTx()
+		Expect(err).To(Equal(ErrUnsignedTxMismatch))
+		tx.Inputs[0].Amount = 5e8
+		tx.Inputs[0].Vout = 1
+		_, err = tx.MsgTx()
+		Expect(err).To(Equal(ErrUnsignedTxMismatch))
+	})
+})
@@ -0,0 +1,247 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+const (
+	// vspCacheConfigKey holds the last info fetched from each VSP.
+	vspCacheConfigKey = "vsp_info_cache"
+	// vspCacheTTL is how long fetched VSP info is used before it is fetched
+	// again.
+	vspCacheTTL = time.Hour
+)
+
+// VSPRecord is the cached info of a VSP and the result of the last probe.
+type VSPRecord struct {
+	Host string
+	// Info is the last info fetched from the VSP. It is nil if the VSP was
+	// never reached.
+	Info      *dcrlibwallet.VspInfoResponse
+	FetchedAt time.Time
+
+	LastProbe time.Time
+	Latency   time.Duration
+	Available bool
+	Err       string
+
+	// PubKeyChanged is set when the VSP signs with another key than the one
+	// it was first seen with, which was kept in PreviousPubKey.
+	PubKeyChanged  bool
+	PreviousPubKey []byte
+}
+
+// Stale reports whether the info of the VSP should be fetched again.
+func (r *VSPRecord) Stale() bool {
+	return r.Info == nil || time.Since(r.FetchedAt) > vspCacheTTL
+}
+
+// VSPRecords is sent when the saved VSPs have been read or probed.
+type VSPRecords struct {
+	List []VSPRecord
+	// Default is the VSP tickets are bought through unless another is chosen.
+	Default string
+}
+
+// ErrVSPPubKeyChanged is returned when a VSP is used after its public key
+// changed, until the new key is accepted with Wallet.TrustVSPPubKey.
+var ErrVSPPubKeyChanged = errors.New("the public key of the VSP changed, trust the new key before using the VSP")
+
+type vspHostList struct {
+	Remember string
+	List     []string
+}
+
+func (wal *Wallet) readVSPCache() map[string]*VSPRecord {
+	cache := make(map[string]*VSPRecord)
+	wal.multi.ReadUserConfigValue(vspCacheConfigKey, &cache)
+	return cache
+}
+
+// fetchVSP fetches the info of the VSP at host and caches it with the time
+// it took. The VSP's signature only proves it holds the key it sends, so a
+// key that differs from the cached one is flagged.
+func (wal *Wallet) fetchVSP(ctx context.Context, host string) (*VSPRecord, error) {
+	start := time.Now()
+	info, err := getVSPInfo(ctx, host)
+	latency := time.Since(start)
+
+	wal.vspCacheMtx.Lock()
+	defer wal.vspCacheMtx.Unlock()
+
+	cache := wal.readVSPCache()
+	record, ok := cache[host]
+	if !ok {
+		record = &VSPRecord{Host: host}
+		cache[host] = record
+	}
+	record.LastProbe = start
+	record.Latency = latency
+	if err != nil {
+		record.Available = false
+		record.Err = err.Error()
+	} else {
+		if record.Info != nil && !record.PubKeyChanged && !bytes.Equal(record.Info.PubKey, info.PubKey) {
+			record.PubKeyChanged = true
+			record.PreviousPubKey = record.Info.PubKey
+		}
+		record.Info = info
+		record.FetchedAt = start
+		record.Available = !info.VspClosed
+		record.Err = ""
+	}
+	wal.multi.SaveUserConfigValue(vspCacheConfigKey, cache)
+	return record, err
+}
+
+// cachedVSP returns the cached info of the VSP at host, fetching it if it is
+// stale.
+func (wal *Wallet) cachedVSP(ctx context.Context, host string) (*VSPRecord, error) {
+	wal.vspCacheMtx.Lock()
+	record, ok := wal.readVSPCache()[host]
+	wal.vspCacheMtx.Unlock()
+	if ok && !record.Stale() {
+		return record, nil
+	}
+	return wal.fetchVSP(ctx, host)
+}
+
+// trustedVSP returns the cached info of the VSP at host like cachedVSP, or
+// fetches it again if refresh is set. A VSP whose public key changed is
+// refused until TrustVSPPubKey accepts the new key.
+func (wal *Wallet) trustedVSP(ctx context.Context, host string, refresh bool) (*VSPRecord, error) {
+	var record *VSPRecord
+	var err error
+	if refresh {
+		record, err = wal.fetchVSP(ctx, host)
+	} else {
+		record, err = wal.cachedVSP(ctx, host)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.PubKeyChanged {
+		return nil, ErrVSPPubKeyChanged
+	}
+	return record, nil
+}
+
+// vspClient returns a client for the VSP at host that checks its replies
+// against the trusted public key of the VSP.
+func (wal *Wallet) vspClient(ctx context.Context, host string) (*VSPClient, error) {
+	record, err := wal.trustedVSP(ctx, host, false)
+	if err != nil {
+		return nil, err
+	}
+	return NewVSPClient(host, record.Info.PubKey, nil), nil
+}
+
+// SavedVSPs returns the cached info of the saved VSPs without reaching them.
+func (wal *Wallet) SavedVSPs() *VSPRecords {
+	var saved vspHostList
+	wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &saved)
+
+	wal.vspCacheMtx.Lock()
+	cache := wal.readVSPCache()
+	wal.vspCacheMtx.Unlock()
+
+	records := &VSPRecords{Default: saved.Remember}
+	for _, host := range saved.List {
+		if record, ok := cache[host]; ok {
+			records.List = append(records.List, *record)
+		} else {
+			records.List = append(records.List, VSPRecord{Host: host})
+		}
+	}
+	return records
+}
+
+// ProbeVSPsCtx fetches the info of all saved VSPs to measure their latency
+// and availability.
+// It blocks until all VSPs have answered or ctx is canceled.
+func (wal *Wallet) ProbeVSPsCtx(ctx context.Context) (*VSPRecords, error) {
+	var saved vspHostList
+	wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &saved)
+
+	var wg sync.WaitGroup
+	for _, host := range saved.List {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			wal.fetchVSP(ctx, host)
+		}(host)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return wal.SavedVSPs(), nil
+}
+
+// ProbeVSPs fetches the info of all saved VSPs to measure their latency and
+// availability.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) ProbeVSPs() RequestID {
+	req := wal.newRequest(OpProbeVSPs)
+	go func() {
+		records, err := wal.ProbeVSPsCtx(context.Background())
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(records)
+	}()
+	return req.ID
+}
+
+// RemoveVSP removes host from the saved VSPs. It is no longer the default
+// VSP if it was.
+func (wal *Wallet) RemoveVSP(host string) error {
+	var saved vspHostList
+	wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &saved)
+
+	found := false
+	for i, h := range saved.List {
+		if h == host {
+			saved.List = append(saved.List[:i], saved.List[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("VSP %s is not saved", host)
+	}
+	if saved.Remember == host {
+		saved.Remember = ""
+	}
+	wal.multi.SaveUserConfigValue(dcrlibwallet.VSPHostConfigKey, saved)
+
+	wal.vspCacheMtx.Lock()
+	defer wal.vspCacheMtx.Unlock()
+	cache := wal.readVSPCache()
+	delete(cache, host)
+	wal.multi.SaveUserConfigValue(vspCacheConfigKey, cache)
+	return nil
+}
+
+// TrustVSPPubKey accepts the current public key of the VSP at host, clearing
+// the warning raised when it changed.
+func (wal *Wallet) TrustVSPPubKey(host string) {
+	wal.vspCacheMtx.Lock()
+	defer wal.vspCacheMtx.Unlock()
+
+	cache := wal.readVSPCache()
+	if record, ok := cache[host]; ok {
+		record.PubKeyChanged = false
+		record.PreviousPubKey = nil
+		wal.multi.SaveUserConfigValue(vspCacheConfigKey, cache)
+	}
+}
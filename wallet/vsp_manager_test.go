@@ -0,0 +1,103 @@
+package wallet_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/planetdecred/dcrlibwallet"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("VSP management", func() {
+	var (
+		privKey ed25519.PrivateKey
+		server  *httptest.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		_, privKey, err = ed25519.GenerateKey(nil)
+		Expect(err).To(BeNil())
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := json.Marshal(dcrlibwallet.VspInfoResponse{
+				PubKey:        privKey.Public().(ed25519.PublicKey),
+				FeePercentage: 2,
+				Network:       "testnet3",
+				Voting:        10,
+			})
+			w.Header().Set("VSP-Server-Signature", base64.StdEncoding.EncodeToString(ed25519.Sign(privKey, b)))
+			w.Write(b)
+		}))
+	})
+
+	AfterEach(func() {
+		wal.RemoveVSP(server.URL)
+		server.Close()
+	})
+
+	It("caches the info of saved VSPs", func() {
+		_, err := wal.AddVSPCtx(context.Background(), server.URL)
+		Expect(err).To(BeNil())
+
+		records := wal.SavedVSPs()
+		Expect(records.List).To(HaveLen(1))
+		record := records.List[0]
+		Expect(record.Host).To(Equal(server.URL))
+		Expect(record.Info.FeePercentage).To(Equal(2.0))
+		Expect(record.Info.Voting).To(Equal(int64(10)))
+		Expect(record.Available).To(BeTrue())
+		Expect(record.Stale()).To(BeFalse())
+	})
+
+	It("warns when the public key of a VSP changes", func() {
+		ctx := context.Background()
+		_, err := wal.AddVSPCtx(ctx, server.URL)
+		Expect(err).To(BeNil())
+		oldKey := privKey.Public().(ed25519.PublicKey)
+
+		_, privKey, err = ed25519.GenerateKey(nil)
+		Expect(err).To(BeNil())
+		records, err := wal.ProbeVSPsCtx(ctx)
+		Expect(err).To(BeNil())
+		Expect(records.List[0].PubKeyChanged).To(BeTrue())
+		Expect(records.List[0].PreviousPubKey).To(BeEquivalentTo(oldKey))
+
+		_, err = wal.NewVSPD(server.URL, 1, 0)
+		Expect(err).To(Equal(ErrVSPPubKeyChanged))
+
+		wal.TrustVSPPubKey(server.URL)
+		Expect(wal.SavedVSPs().List[0].PubKeyChanged).To(BeFalse())
+	})
+
+	It("removes the default VSP", func() {
+		_, err := wal.AddVSPCtx(context.Background(), server.URL)
+		Expect(err).To(BeNil())
+		wal.RememberVSP(server.URL)
+		Expect(wal.SavedVSPs().Default).To(Equal(server.URL))
+
+		Expect(wal.RemoveVSP(server.URL)).To(Succeed())
+		records := wal.SavedVSPs()
+		Expect(records.List).To(BeEmpty())
+		Expect(records.Default).To(BeEmpty())
+		Expect(wal.RemoveVSP(server.URL)).NotTo(Succeed())
+	})
+
+	It("marks unreachable VSPs unavailable", func() {
+		_, err := wal.AddVSPCtx(context.Background(), server.URL)
+		Expect(err).To(BeNil())
+		server.Close()
+
+		records, err := wal.ProbeVSPsCtx(context.Background())
+		Expect(err).To(BeNil())
+		Expect(records.List[0].Available).To(BeFalse())
+		Expect(records.List[0].Err).NotTo(BeEmpty())
+		Expect(records.List[0].Info).NotTo(BeNil())
+	})
+})
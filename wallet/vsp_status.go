@@ -0,0 +1,450 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+	"github.com/planetdecred/dcrlibwallet"
+)
+
+const (
+	// vspTicketHostsConfigKey maps the tickets of a wallet to the VSP they
+	// were found at.
+	vspTicketHostsConfigKey = "vsp_ticket_hosts"
+	// usedVSPHostsConfigKey lists the VSPs tickets were bought through with
+	// a wallet.
+	usedVSPHostsConfigKey = "used_vsp_hosts"
+)
+
+// Fee states of a ticket reported by vspd.
+const (
+	VSPFeeNone      = "none"
+	VSPFeeReceived  = "received"
+	VSPFeeBroadcast = "broadcast"
+	VSPFeeConfirmed = "confirmed"
+	VSPFeeError     = "error"
+)
+
+// ErrTicketNotAtVSP is returned when none of the known VSPs manages a ticket.
+var ErrTicketNotAtVSP = errors.New("no known VSP manages the ticket")
+
+// VSPError is an error reported by a VSP about a request.
+type VSPError struct {
+	HTTPStatus int    `json:"-"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+}
+
+// VSPErrUnknownTicket is the code of the error vspd returns for a ticket it
+// does not manage.
+const VSPErrUnknownTicket = 6
+
+func (e *VSPError) Error() string {
+	return fmt.Sprintf("VSP error %d: %s", e.Code, e.Message)
+}
+
+// UnknownTicket reports whether the VSP does not manage the ticket of the
+// request.
+func (e *VSPError) UnknownTicket() bool {
+	return e.Code == VSPErrUnknownTicket
+}
+
+// VSPClient talks to the parts of the vspd API that dcrlibwallet's VSP
+// client does not cover. Responses are checked against the VSP's public key.
+type VSPClient struct {
+	host   string
+	pubKey []byte
+	client *http.Client
+}
+
+// NewVSPClient returns a client for the vspd server at host whose responses
+// are signed with pubKey. A nil client uses a default client.
+func NewVSPClient(host string, pubKey []byte, client *http.Client) *VSPClient {
+	return &VSPClient{host: strings.TrimSuffix(host, "/"), pubKey: pubKey, client: httpClient(client)}
+}
+
+type vspTicketStatusRequest struct {
+	Timestamp  int64  `json:"timestamp"`
+	TicketHash string `json:"tickethash"`
+}
+
+// VSPTicketStatusReply is the status of a ticket returned by vspd.
+type VSPTicketStatusReply struct {
+	Timestamp       int64             `json:"timestamp"`
+	TicketConfirmed bool              `json:"ticketconfirmed"`
+	FeeTxStatus     string            `json:"feetxstatus"`
+	FeeTxHash       string            `json:"feetxhash"`
+	VoteChoices     map[string]string `json:"votechoices"`
+	Request         []byte            `json:"request"`
+}
+
+// TicketStatus asks the VSP for the status of a ticket. sign must sign the
+// request with the commitment address of the ticket.
+func (c *VSPClient) TicketStatus(ctx context.Context, ticketHash string, sign func(message string) ([]byte, error)) (*VSPTicketStatusReply, error) {
+	request := vspTicketStatusRequest{
+		Timestamp:  time.Now().Unix(),
+		TicketHash: ticketHash,
+	}
+	var reply VSPTicketStatusReply
+	if err := c.post(ctx, "/api/v3/ticketstatus", request, sign, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VSPVoteChoices are the choices a VSP votes with on a ticket. Each maps
+// an agenda ID, tspend hash or treasury key to the choice.
+type VSPVoteChoices struct {
+	VoteChoices    map[string]string
+	TSpendPolicy   map[string]string
+	TreasuryPolicy map[string]string
+}
+
+type vspSetVoteChoicesRequest struct {
+	Timestamp      int64             `json:"timestamp"`
+	TicketHash     string            `json:"tickethash"`
+	VoteChoices    map[string]string `json:"votechoices"`
+	TSpendPolicy   map[string]string `json:"tspendpolicy,omitempty"`
+	TreasuryPolicy map[string]string `json:"treasurypolicy,omitempty"`
+}
+
+// SetVoteChoices asks the VSP to vote on a ticket with choices. sign must
+// sign the request with the commitment address of the ticket.
+func (c *VSPClient) SetVoteChoices(ctx context.Context, ticketHash string, choices VSPVoteChoices, sign func(message string) ([]byte, error)) error {
+	request := vspSetVoteChoicesRequest{
+		Timestamp:      time.Now().Unix(),
+		TicketHash:     ticketHash,
+		VoteChoices:    choices.VoteChoices,
+		TSpendPolicy:   choices.TSpendPolicy,
+		TreasuryPolicy: choices.TreasuryPolicy,
+	}
+	var reply struct{}
+	return c.post(ctx, "/api/v3/setvotechoices", request, sign, &reply)
+}
+
+// post sends a signed request to the VSP and decodes its reply after checking
+// that the VSP signed it and that it answers this request.
+func (c *VSPClient) post(ctx context.Context, path string, request interface{}, sign func(message string) ([]byte, error), reply interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	sig, err := sign(string(body))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("VSP-Client-Signature", base64.StdEncoding.EncodeToString(sig))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		vspErr := &VSPError{HTTPStatus: resp.StatusCode}
+		if err := json.Unmarshal(b, vspErr); err != nil {
+			return fmt.Errorf("non 200 response from server: %v", string(b))
+		}
+		return vspErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non 200 response from server: %v", string(b))
+	}
+
+	if err := validateVSPServerSignature(resp, c.pubKey, b); err != nil {
+		return err
+	}
+	var echo struct {
+		Request []byte `json:"request"`
+	}
+	if err := json.Unmarshal(b, &echo); err != nil {
+		return err
+	}
+	if !bytes.Equal(echo.Request, body) {
+		return errors.New("VSP replied to a different request")
+	}
+	return json.Unmarshal(b, reply)
+}
+
+// VSPTicketStatus is the status of a ticket at the VSP that manages it.
+type VSPTicketStatus struct {
+	WalletID   int
+	TicketHash string
+	// Host is empty if no known VSP manages the ticket.
+	Host            string
+	TicketConfirmed bool
+	FeeTxStatus     string
+	FeeTxHash       string
+	VoteChoices     map[string]string
+	// Err is set if the status could not be read.
+	Err string
+}
+
+// FeeStuck reports whether the fee of the ticket has to be paid again.
+func (s *VSPTicketStatus) FeeStuck() bool {
+	return s.Host != "" && (s.FeeTxStatus == VSPFeeNone || s.FeeTxStatus == VSPFeeError)
+}
+
+// VSPTicketStatuses is sent when Wallet.VSPTicketStatus has checked the
+// tickets of a wallet.
+type VSPTicketStatuses struct {
+	WalletID int
+	Statuses []VSPTicketStatus
+}
+
+// VSPFeePaid is sent when Wallet.RetryVSPFee has paid the fee of a ticket.
+type VSPFeePaid struct {
+	WalletID   int
+	TicketHash string
+}
+
+// rememberVSPHost records that tickets of the wallet were bought through
+// host so their status can be looked up there.
+func rememberVSPHost(w *dcrlibwallet.Wallet, host string) {
+	var hosts []string
+	w.ReadUserConfigValue(usedVSPHostsConfigKey, &hosts)
+	for _, h := range hosts {
+		if h == host {
+			return
+		}
+	}
+	w.SaveUserConfigValue(usedVSPHostsConfigKey, append(hosts, host))
+}
+
+// vspHosts returns the VSPs that may manage the tickets of a wallet: the ones
+// it bought tickets through, the auto-buyer's and the saved ones.
+func (wal *Wallet) vspHosts(w *dcrlibwallet.Wallet) []string {
+	var hosts []string
+	w.ReadUserConfigValue(usedVSPHostsConfigKey, &hosts)
+
+	if cfg, err := wal.TicketBuyerConfig(w.ID); err == nil && cfg != nil {
+		hosts = append(hosts, cfg.VSPHost)
+	}
+
+	var saved vspHostList
+	wal.multi.ReadUserConfigValue(dcrlibwallet.VSPHostConfigKey, &saved)
+	hosts = append(hosts, saved.Remember)
+	hosts = append(hosts, saved.List...)
+
+	seen := make(map[string]bool)
+	unique := hosts[:0]
+	for _, host := range hosts {
+		if host != "" && !seen[host] {
+			seen[host] = true
+			unique = append(unique, host)
+		}
+	}
+	return unique
+}
+
+// ticketSigner returns a function that signs VSP requests about a ticket with
+// its commitment address.
+func ticketSigner(w *dcrlibwallet.Wallet, ticketHash []byte, passphrase []byte) (func(message string) ([]byte, error), error) {
+	commitment, err := ticketCommitmentAddress(w, ticketHash)
+	if err != nil {
+		return nil, err
+	}
+	return func(message string) ([]byte, error) {
+		return w.SignMessage(append([]byte(nil), passphrase...), commitment, message)
+	}, nil
+}
+
+// VSPTicketStatusCtx asks the VSPs for the status of the wallet's tickets that
+// have not voted or been revoked. The requests are signed with the
+// commitment address of each ticket, which requires the spending passphrase.
+// Tickets that are not found at any known VSP have no host.
+// It blocks until all tickets are checked or ctx is canceled.
+func (wal *Wallet) VSPTicketStatusCtx(ctx context.Context, walletID int, passphrase []byte) (*VSPTicketStatuses, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return nil, ErrIDNotExist
+	}
+	// Check the passphrase once rather than failing on every ticket.
+	wasLocked := w.IsLocked()
+	if err := w.UnlockWallet(append([]byte(nil), passphrase...)); err != nil {
+		return nil, err
+	}
+	if wasLocked {
+		w.LockWallet()
+	}
+
+	ticketsInfo, err := w.GetTicketsForBlockHeightRange(0, w.GetBestBlock(), math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketHosts := make(map[string]string)
+	w.ReadUserConfigValue(vspTicketHostsConfigKey, &ticketHosts)
+	hosts := wal.vspHosts(w)
+	clients := make(map[string]*VSPClient)
+	client := func(host string) (*VSPClient, error) {
+		if c, ok := clients[host]; ok {
+			return c, nil
+		}
+		c, err := wal.vspClient(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		clients[host] = c
+		return c, nil
+	}
+
+	result := &VSPTicketStatuses{WalletID: walletID}
+	for _, info := range ticketsInfo {
+		if info.Status != "UNMINED" && info.Status != "IMMATURE" && info.Status != "LIVE" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		status := VSPTicketStatus{WalletID: walletID, TicketHash: info.Ticket.Hash.String()}
+		sign, err := ticketSigner(w, info.Ticket.Hash[:], passphrase)
+		if err != nil {
+			status.Err = err.Error()
+			result.Statuses = append(result.Statuses, status)
+			continue
+		}
+
+		candidates := hosts
+		if host, ok := ticketHosts[status.TicketHash]; ok {
+			candidates = []string{host}
+		}
+		for _, host := range candidates {
+			c, err := client(host)
+			if err != nil {
+				status.Err = err.Error()
+				continue
+			}
+			reply, err := c.TicketStatus(ctx, status.TicketHash, sign)
+			if vspErr, ok := err.(*VSPError); ok && vspErr.UnknownTicket() {
+				continue
+			}
+			if err != nil {
+				status.Err = err.Error()
+				continue
+			}
+
+			status.Host = host
+			status.Err = ""
+			status.TicketConfirmed = reply.TicketConfirmed
+			status.FeeTxStatus = reply.FeeTxStatus
+			status.FeeTxHash = reply.FeeTxHash
+			status.VoteChoices = reply.VoteChoices
+			ticketHosts[status.TicketHash] = host
+			break
+		}
+		result.Statuses = append(result.Statuses, status)
+	}
+
+	w.SaveUserConfigValue(vspTicketHostsConfigKey, ticketHosts)
+	return result, nil
+}
+
+// VSPTicketStatus asks the VSPs for the status of the wallet's tickets that
+// have not voted or been revoked.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) VSPTicketStatus(walletID int, passphrase []byte) RequestID {
+	req := wal.newRequest(OpVSPTicketStatus)
+	go func() {
+		statuses, err := wal.VSPTicketStatusCtx(context.Background(), walletID, passphrase)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(statuses)
+	}()
+	return req.ID
+}
+
+// RetryVSPFeeCtx pays the VSP fee of a ticket again from the account that
+// bought it, unless that account has frozen outputs. The ticket must have
+// been found at a VSP by VSPTicketStatusCtx.
+// It blocks until the fee is paid or ctx is canceled.
+func (wal *Wallet) RetryVSPFeeCtx(ctx context.Context, walletID int, ticketHash string, passphrase []byte) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+
+	ticketHosts := make(map[string]string)
+	w.ReadUserConfigValue(vspTicketHostsConfigKey, &ticketHosts)
+	host, ok := ticketHosts[ticketHash]
+	if !ok {
+		return ErrTicketNotAtVSP
+	}
+
+	hash, err := chainhash.NewHashFromStr(ticketHash)
+	if err != nil {
+		return err
+	}
+	ticket, err := w.GetTransactionRaw(hash[:])
+	if err != nil {
+		return err
+	}
+	account := int32(-1)
+	for _, input := range ticket.Inputs {
+		if input.AccountNumber >= 0 {
+			account = input.AccountNumber
+			break
+		}
+	}
+	if account < 0 {
+		return errors.New("ticket was not bought by the wallet")
+	}
+	if err := checkNoFrozenUTXOs(w, account); err != nil {
+		return err
+	}
+
+	vspd, err := wal.NewVSPD(host, walletID, account)
+	if err != nil {
+		return err
+	}
+
+	wasLocked := w.IsLocked()
+	if err := w.UnlockWallet(append([]byte(nil), passphrase...)); err != nil {
+		return err
+	}
+	if wasLocked {
+		defer w.LockWallet()
+	}
+	return vspd.ProcessFee(ctx, hash, wire.NewMsgTx())
+}
+
+// RetryVSPFee pays the VSP fee of a ticket again from the account that
+// bought it.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) RetryVSPFee(walletID int, ticketHash string, passphrase []byte) RequestID {
+	req := wal.newRequest(OpRetryVSPFee)
+	go func() {
+		err := wal.RetryVSPFeeCtx(context.Background(), walletID, ticketHash, passphrase)
+		if err != nil {
+			wal.Send <- req.error(err)
+			return
+		}
+		wal.Send <- req.response(&VSPFeePaid{WalletID: walletID, TicketHash: ticketHash})
+	}()
+	return req.ID
+}
@@ -0,0 +1,148 @@
+package wallet_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/planetdecred/godcr/wallet"
+)
+
+var _ = Describe("VSP ticket status", func() {
+	var (
+		pubKey  ed25519.PublicKey
+		privKey ed25519.PrivateKey
+		server  *httptest.Server
+		// reply builds the response to the request body.
+		reply     func(body []byte) (int, interface{})
+		clientSig string
+		path      string
+		request   map[string]interface{}
+	)
+
+	sign := func(message string) ([]byte, error) {
+		return []byte("signed " + message), nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		pubKey, privKey, err = ed25519.GenerateKey(nil)
+		Expect(err).To(BeNil())
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			clientSig = r.Header.Get("VSP-Client-Signature")
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &request)
+			status, resp := reply(body)
+			b, _ := json.Marshal(resp)
+			w.Header().Set("VSP-Server-Signature", base64.StdEncoding.EncodeToString(ed25519.Sign(privKey, b)))
+			w.WriteHeader(status)
+			w.Write(b)
+		}))
+		reply = func(body []byte) (int, interface{}) {
+			return http.StatusOK, map[string]interface{}{
+				"ticketconfirmed": true,
+				"feetxstatus":     VSPFeeConfirmed,
+				"feetxhash":       "feehash",
+				"votechoices":     map[string]string{"autorevocations": "yes"},
+				"request":         body,
+			}
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reads the status of a ticket", func() {
+		client := NewVSPClient(server.URL, pubKey, nil)
+		status, err := client.TicketStatus(context.Background(), "tickethash", sign)
+		Expect(err).To(BeNil())
+		Expect(path).To(Equal("/api/v3/ticketstatus"))
+		Expect(status.TicketConfirmed).To(BeTrue())
+		Expect(status.FeeTxStatus).To(Equal(VSPFeeConfirmed))
+		Expect(status.FeeTxHash).To(Equal("feehash"))
+		Expect(status.VoteChoices).To(Equal(map[string]string{"autorevocations": "yes"}))
+
+		sig, err := base64.StdEncoding.DecodeString(clientSig)
+		Expect(err).To(BeNil())
+		Expect(string(sig)).To(HavePrefix("signed "))
+		Expect(string(sig)).To(ContainSubstring(`"tickethash":"tickethash"`))
+	})
+
+	It("sets the vote choices of a ticket", func() {
+		client := NewVSPClient(server.URL, pubKey, nil)
+		err := client.SetVoteChoices(context.Background(), "tickethash", VSPVoteChoices{
+			VoteChoices:    map[string]string{"autorevocations": "no"},
+			TreasuryPolicy: map[string]string{"key": "yes"},
+		}, sign)
+		Expect(err).To(BeNil())
+		Expect(path).To(Equal("/api/v3/setvotechoices"))
+		Expect(request["tickethash"]).To(Equal("tickethash"))
+		Expect(request["votechoices"]).To(Equal(map[string]interface{}{"autorevocations": "no"}))
+		Expect(request["treasurypolicy"]).To(Equal(map[string]interface{}{"key": "yes"}))
+		Expect(request).NotTo(HaveKey("tspendpolicy"))
+	})
+
+	It("rejects replies not signed by the VSP", func() {
+		otherKey, _, err := ed25519.GenerateKey(nil)
+		Expect(err).To(BeNil())
+		client := NewVSPClient(server.URL, otherKey, nil)
+		_, err = client.TicketStatus(context.Background(), "tickethash", sign)
+		Expect(err).To(MatchError("bad signature from VSP"))
+	})
+
+	It("rejects replies to another request", func() {
+		reply = func([]byte) (int, interface{}) {
+			return http.StatusOK, map[string]interface{}{"feetxstatus": VSPFeeNone, "request": []byte("{}")}
+		}
+		client := NewVSPClient(server.URL, pubKey, nil)
+		_, err := client.TicketStatus(context.Background(), "tickethash", sign)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("returns VSP errors", func() {
+		reply = func([]byte) (int, interface{}) {
+			return http.StatusBadRequest, map[string]interface{}{"code": 9, "message": "invalid vote choices"}
+		}
+		client := NewVSPClient(server.URL, pubKey, nil)
+		_, err := client.TicketStatus(context.Background(), "tickethash", sign)
+		Expect(err).To(BeAssignableToTypeOf(&VSPError{}))
+		Expect(err.(*VSPError).Code).To(Equal(9))
+		Expect(err.(*VSPError).HTTPStatus).To(Equal(http.StatusBadRequest))
+		Expect(err.(*VSPError).UnknownTicket()).To(BeFalse())
+
+		reply = func([]byte) (int, interface{}) {
+			return http.StatusBadRequest, map[string]interface{}{"code": VSPErrUnknownTicket, "message": "unknown ticket"}
+		}
+		_, err = client.TicketStatus(context.Background(), "tickethash", sign)
+		Expect(err.(*VSPError).UnknownTicket()).To(BeTrue())
+	})
+
+	It("reports stuck fees", func() {
+		Expect((&VSPTicketStatus{Host: "vsp", FeeTxStatus: VSPFeeError}).FeeStuck()).To(BeTrue())
+		Expect((&VSPTicketStatus{Host: "vsp", FeeTxStatus: VSPFeeNone}).FeeStuck()).To(BeTrue())
+		Expect((&VSPTicketStatus{Host: "vsp", FeeTxStatus: VSPFeeBroadcast}).FeeStuck()).To(BeFalse())
+		Expect((&VSPTicketStatus{FeeTxStatus: VSPFeeNone}).FeeStuck()).To(BeFalse())
+	})
+
+	It("checks the tickets of a wallet", func() {
+		ctx := context.Background()
+		_, err := wal.VSPTicketStatusCtx(ctx, 99, []byte("password"))
+		Expect(err).To(Equal(ErrIDNotExist))
+
+		statuses, err := wal.VSPTicketStatusCtx(ctx, 1, []byte("password"))
+		Expect(err).To(BeNil())
+		Expect(statuses.Statuses).To(BeEmpty())
+
+		err = wal.RetryVSPFeeCtx(ctx, 1, "tickethash", []byte("password"))
+		Expect(err).To(Equal(ErrTicketNotAtVSP))
+	})
+})
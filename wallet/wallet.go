@@ -3,10 +3,10 @@
 package wallet
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"sort"
+	"sync"
 
 	"github.com/planetdecred/dcrlibwallet"
 )
@@ -15,12 +15,41 @@ const syncID = "godcr"
 
 // Wallet represents the wallet back end of the app
 type Wallet struct {
+	lastRequestID      uint64 // accessed atomically, kept first for alignment
 	multi              *dcrlibwallet.MultiWallet
 	root, Net          string
 	Send               chan Response
 	Sync               chan SyncStatusUpdate
 	confirms           int32
 	OverallBlockHeight int32
+
+	// ExchangeRates fetches the DCR exchange rates used for currency
+	// conversion.
+	ExchangeRates *ExchangeRates
+
+	// HistoricalRates fetches the daily rates stored in the rate history.
+	HistoricalRates HistoricalRateSource
+
+	// Politeia casts proposal votes. It defaults to the mainnet server that
+	// proposals are synced from.
+	Politeia *PoliteiaClient
+
+	// Dcrdata publishes transactions signed offline. It defaults to the
+	// dcrdata explorer of the network.
+	Dcrdata *DcrdataClient
+
+	rateHistoryOnce sync.Once
+	rateHistory     *RateHistory
+	rateHistoryErr  error
+	// rateHistorySyncing is set while SyncRateHistoryCtx runs.
+	rateHistorySyncing int32
+
+	addressBookMtx sync.Mutex
+	vspCacheMtx    sync.Mutex
+
+	bookmarksMtx sync.Mutex
+
+	ticketBuyers ticketBuyers
 }
 
 // NewWallet initializies an new Wallet instance.
@@ -35,6 +64,13 @@ func NewWallet(root string, net string, send chan Response, confirms int32) (*Wa
 		Sync:     make(chan SyncStatusUpdate, 2),
 		Send:     send,
 		confirms: confirms,
+		ExchangeRates: NewExchangeRates(DefaultRateMaxAge,
+			NewBittrexSource("https://api.bittrex.com", nil),
+			NewCoinGeckoSource("https://api.coingecko.com", nil),
+			NewDcrdataSource("https://explorer.dcrdata.org", nil)),
+		HistoricalRates: NewCoinGeckoHistory("https://api.coingecko.com", nil),
+		Politeia:        NewPoliteiaClient(dcrlibwallet.PoliteiaMainnetHost, nil),
+		Dcrdata:         NewDcrdataClient(dcrdataURL(net), nil),
 	}
 
 	return wal, nil
@@ -50,10 +86,9 @@ func (wal *Wallet) InitMultiWallet() error {
 	return nil
 }
 
-func (wal *Wallet) SetupListeners() {
-	resp := Response{
-		Resp: LoadedWallets{},
-	}
+func (wal *Wallet) SetupListeners() RequestID {
+	req := wal.newRequest(OpSetupListeners)
+	resp := req.response(LoadedWallets{})
 	l := &listener{
 		Send: wal.Sync,
 	}
@@ -61,14 +96,14 @@ func (wal *Wallet) SetupListeners() {
 	if err != nil {
 		resp.Err = err
 		wal.Send <- resp
-		return
+		return req.ID
 	}
 
 	err = wal.multi.AddTxAndBlockNotificationListener(l, syncID)
 	if err != nil {
 		resp.Err = err
 		wal.Send <- resp
-		return
+		return req.ID
 	}
 
 	wal.multi.AddAccountMixerNotificationListener(l, syncID)
@@ -82,22 +117,21 @@ func (wal *Wallet) SetupListeners() {
 		StartUpSecuritySet: startupPassSet,
 	}
 	wal.Send <- resp
+	return req.ID
 }
 
-// LoadWallets loads the wallets for network in the root directory.
+// LoadWalletsCtx loads the wallets for network in the root directory.
 // It adds a SyncProgressListener to the multiwallet and opens the wallets if no
 // startup passphrase was set.
-// It is non-blocking and sends its result or any erro to wal.Send.
-func (wal *Wallet) LoadWallets() {
-	resp := Response{
-		Resp: LoadedWallets{},
+func (wal *Wallet) LoadWalletsCtx(ctx context.Context) (*LoadedWallets, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+
 	multiWal, err := dcrlibwallet.NewMultiWallet(wal.root, "bdb", wal.Net)
 	if err != nil {
-		resp.Err = err
 		log.Error("Wallet not loaded. Is another process using the data directory?")
-		wal.Send <- resp
-		return
+		return nil, err
 	}
 	wal.multi = multiWal
 	l := &listener{
@@ -105,16 +139,12 @@ func (wal *Wallet) LoadWallets() {
 	}
 	err = wal.multi.AddSyncProgressListener(l, syncID)
 	if err != nil {
-		resp.Err = err
-		wal.Send <- resp
-		return
+		return nil, err
 	}
 
 	err = wal.multi.AddTxAndBlockNotificationListener(l, syncID)
 	if err != nil {
-		resp.Err = err
-		wal.Send <- resp
-		return
+		return nil, err
 	}
 
 	wal.multi.AddAccountMixerNotificationListener(l, syncID)
@@ -125,17 +155,33 @@ func (wal *Wallet) LoadWallets() {
 	if !startupPassSet {
 		err = wal.multi.OpenWallets(nil)
 		if err != nil {
-			resp.Err = err
-			wal.Send <- resp
-			return
+			return nil, err
 		}
 	}
 
-	resp.Resp = LoadedWallets{
+	return &LoadedWallets{
 		Count:              wal.multi.LoadedWalletsCount(),
 		StartUpSecuritySet: startupPassSet,
-	}
-	wal.Send <- resp
+	}, nil
+}
+
+// LoadWallets loads the wallets for network in the root directory.
+// It adds a SyncProgressListener to the multiwallet and opens the wallets if no
+// startup passphrase was set.
+// It is non-blocking and sends its result or any error to wal.Send.
+func (wal *Wallet) LoadWallets() RequestID {
+	req := wal.newRequest(OpLoadWallets)
+	go func() {
+		loaded, err := wal.LoadWalletsCtx(context.Background())
+		if err != nil {
+			resp := req.error(err)
+			resp.Resp = LoadedWallets{}
+			wal.Send <- resp
+			return
+		}
+		wal.Send <- req.response(*loaded)
+	}()
+	return req.ID
 }
 
 // wallets returns an up-to-date map of all opened wallets
@@ -199,17 +245,3 @@ func (wal *Wallet) GetBlockExplorerURL(txnHash string) string {
 		return ""
 	}
 }
-
-//GetUSDExchangeValues gets the exchange rate of DCR - USDT from a specified endpoint
-func (wal *Wallet) GetUSDExchangeValues(target interface{}) error {
-	url := "https://api.bittrex.com/v3/markets/DCR-USDT/ticker"
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-
-	defer resp.Body.Close()
-
-	json.NewDecoder(resp.Body).Decode(target)
-	return nil
-}
@@ -1,6 +1,7 @@
 package wallet_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -31,11 +32,13 @@ func getTestDir() string {
 }
 
 var (
-	wal *Wallet
+	wal     *Wallet
+	testDir string
 )
 var _ = BeforeSuite(func() {
 	var err error
-	wal, err = NewWallet(getTestDir(), testnet, make(chan Response), 2)
+	testDir = getTestDir()
+	wal, err = NewWallet(testDir, testnet, make(chan Response), 2)
 	Expect(err).To(BeNil())
 	wal.LoadWallets()
 	resp := <-wal.Send
@@ -52,6 +55,7 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterSuite(func() {
 	wal.Shutdown()
+	os.RemoveAll(testDir)
 })
 
 var _ = Describe("Wallet", func() {
@@ -72,8 +76,62 @@ var _ = Describe("Wallet", func() {
 			Expect(err).To(BeNil())
 		}()
 		resp := <-wal.Send
+		Expect(resp.Op).To(Equal(OpRenameWallet))
 		Expect(resp.Resp).To(BeAssignableToTypeOf(Renamed{}))
 	})
+	It("echoes the request ID and operation on responses", func() {
+		id := wal.AllUnspentOutputs(1, 0)
+		resp := <-wal.Send
+		Expect(resp.ID).To(Equal(id))
+		Expect(resp.Op).To(Equal(OpAllUnspentOutputs))
+
+		errChan := make(chan error, 1)
+		failedID := wal.AddAccount(99, "missing", []byte("password"), errChan, nil)
+		Expect(failedID).ToNot(Equal(id))
+		resp = <-wal.Send
+		Expect(resp.ID).To(Equal(failedID))
+		Expect(resp.Op).To(Equal(OpAddAccount))
+		Expect(resp.Err).ToNot(BeNil())
+		Expect(<-errChan).To(Equal(ErrIDNotExist))
+	})
+	It("can load and create wallets synchronously", func() {
+		dir := getTestDir()
+		defer os.RemoveAll(dir)
+		sync, err := NewWallet(dir, testnet, make(chan Response), 2)
+		Expect(err).To(BeNil())
+		loaded, err := sync.LoadWalletsCtx(context.Background())
+		Expect(err).To(BeNil())
+		defer sync.Shutdown()
+		Expect(loaded.Count).To(BeEquivalentTo(0))
+
+		seed, err := sync.CreateWalletCtx(context.Background(), "123", "password")
+		Expect(err).To(BeNil())
+		Expect(seed.Seed).ToNot(BeEmpty())
+		inf, err := sync.GetMultiWalletInfoCtx(context.Background())
+		Expect(err).To(BeNil())
+		Expect(inf.LoadedWallets).To(BeEquivalentTo(1))
+	})
+	It("can get the multi wallet info synchronously", func() {
+		inf, err := wal.GetMultiWalletInfoCtx(context.Background())
+		Expect(err).To(BeNil())
+		Expect(inf.LoadedWallets).To(BeEquivalentTo(1))
+		Expect(inf.Wallets[0].Accounts).ToNot(BeEmpty())
+	})
+	It("can add an account synchronously", func() {
+		acct, err := wal.AddAccountCtx(context.Background(), 1, "savings", []byte("password"))
+		Expect(err).To(BeNil())
+		Expect(acct.Name).To(Equal("savings"))
+		_, err = wal.AddAccountCtx(context.Background(), 99, "savings", []byte("password"))
+		Expect(err).To(Equal(ErrIDNotExist))
+	})
+	It("stops when the context is canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := wal.GetAllTransactionsCtx(ctx, 0, 0, 0)
+		Expect(err).To(Equal(context.Canceled))
+		err = wal.RenameWalletCtx(ctx, 1, "canceled")
+		Expect(err).To(Equal(context.Canceled))
+	})
 	It("can get the current address", func() {
 		addr, err := wal.CurrentAddress(1, 0)
 		Expect(err).To(BeNil())
@@ -87,4 +145,16 @@ var _ = Describe("Wallet", func() {
 		Expect(err).To(BeNil())
 		Expect(wal.IsAddressValid(addr)).To(Equal(true))
 	})
+	It("returns an error for the ticket price without wallets", func() {
+		dir := getTestDir()
+		defer os.RemoveAll(dir)
+		empty, err := NewWallet(dir, testnet, make(chan Response), 2)
+		Expect(err).To(BeNil())
+		_, err = empty.LoadWalletsCtx(context.Background())
+		Expect(err).To(BeNil())
+		defer empty.Shutdown()
+
+		_, _, err = empty.TicketPrice()
+		Expect(err).To(Equal(ErrNoWallets))
+	})
 })
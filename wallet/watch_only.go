@@ -0,0 +1,229 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/planetdecred/dcrlibwallet"
+	"github.com/planetdecred/dcrlibwallet/utils"
+)
+
+const (
+	// accountXpubConfigKey holds the extended public key a watch-only wallet
+	// was imported with, which dcrlibwallet does not return.
+	accountXpubConfigKey = "account_xpub"
+
+	// changeIndexConfigKey holds the next internal address index to pay the
+	// change of an unsigned transaction to.
+	changeIndexConfigKey = "unsigned_tx_change_index"
+
+	// Address branches of BIP0044 accounts.
+	externalBranch uint32 = 0
+	internalBranch uint32 = 1
+)
+
+var (
+	// ErrNoAccountXpub is returned when an unsigned transaction is built
+	// from an account whose extended public key is not known.
+	ErrNoAccountXpub = errors.New("the extended public key of the account is not known")
+
+	// ErrXpubMismatch is returned when an extended public key does not
+	// derive the addresses of the wallet it is set for.
+	ErrXpubMismatch = errors.New("the extended public key does not belong to this wallet")
+)
+
+// accountKeys derives the addresses of the account of a watch-only wallet.
+type accountKeys struct {
+	xpub   string
+	key    *hdkeychain.ExtendedKey
+	params *chaincfg.Params
+}
+
+func newAccountKeys(xpub string, params *chaincfg.Params) (*accountKeys, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub, params)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsPrivate() {
+		return nil, errors.New("the extended key is private, enter the extended public key")
+	}
+	return &accountKeys{xpub: xpub, key: key, params: params}, nil
+}
+
+// address returns the P2PKH address at index of branch.
+func (k *accountKeys) address(branch, index uint32) (*dcrutil.AddressPubKeyHash, error) {
+	branchKey, err := k.key.Child(branch)
+	if err != nil {
+		return nil, err
+	}
+	child, err := branchKey.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	return pubKeyHashAddress(child.SerializedPubKey(), k.params)
+}
+
+// pubKeyHashAddress returns the P2PKH address of a serialized secp256k1
+// public key.
+func pubKeyHashAddress(pubKey []byte, params *chaincfg.Params) (*dcrutil.AddressPubKeyHash, error) {
+	return dcrutil.NewAddressPubKeyHash(dcrutil.Hash160(pubKey), params, dcrec.STEcdsaSecp256k1)
+}
+
+// keyPath is the branch and index of the key of an address.
+type keyPath struct {
+	branch, index uint32
+}
+
+// scriptPaths maps the hex encoded scripts paying the first count addresses
+// of each branch to their key paths.
+func (k *accountKeys) scriptPaths(externalCount, internalCount uint32) (map[string]keyPath, error) {
+	paths := make(map[string]keyPath)
+	for branch, count := range map[uint32]uint32{externalBranch: externalCount, internalBranch: internalCount} {
+		for index := uint32(0); index < count; index++ {
+			addr, err := k.address(branch, index)
+			if err != nil {
+				return nil, err
+			}
+			script, err := txscript.PayToAddrScript(addr)
+			if err != nil {
+				return nil, err
+			}
+			paths[hex.EncodeToString(script)] = keyPath{branch: branch, index: index}
+		}
+	}
+	return paths, nil
+}
+
+// keyCounts returns the number of addresses of each branch of the account
+// that the wallet watches, the last used address of each plus the gap limit.
+func keyCounts(w *dcrlibwallet.Wallet, account int32) (external, internal uint32, err error) {
+	acct, err := w.GetAccount(account)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint32(acct.ExternalKeyCount), uint32(acct.InternalKeyCount), nil
+}
+
+// AccountXpub returns the extended public key a watch-only wallet was
+// imported with, or ErrNoAccountXpub for wallets imported before it was
+// saved.
+func (wal *Wallet) AccountXpub(walletID int) (string, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return "", ErrIDNotExist
+	}
+	var xpub string
+	w.ReadUserConfigValue(accountXpubConfigKey, &xpub)
+	if xpub == "" {
+		return "", ErrNoAccountXpub
+	}
+	return xpub, nil
+}
+
+// SetAccountXpub saves the extended public key of a watch-only wallet after
+// checking that it derives the current address of the wallet.
+func (wal *Wallet) SetAccountXpub(walletID int, xpub string) error {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return ErrIDNotExist
+	}
+	if _, err := wal.verifiedAccountKeys(w, xpub); err != nil {
+		return err
+	}
+	w.SaveUserConfigValue(accountXpubConfigKey, xpub)
+	return nil
+}
+
+// verifiedAccountKeys parses the extended public key of account 0 of w and
+// checks that the wallet's current address is one of its external addresses.
+func (wal *Wallet) verifiedAccountKeys(w *dcrlibwallet.Wallet, xpub string) (*accountKeys, error) {
+	params, err := utils.ChainParams(w.NetType())
+	if err != nil {
+		return nil, err
+	}
+	keys, err := newAccountKeys(xpub, params)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := w.CurrentAddress(0)
+	if err != nil {
+		return nil, err
+	}
+	external, _, err := keyCounts(w, 0)
+	if err != nil {
+		return nil, err
+	}
+	// The current address may have been returned without being used, up to
+	// the gap limit past the watched addresses.
+	for index := uint32(0); index < external+dcrlibwallet.AddressGapLimit; index++ {
+		addr, err := keys.address(externalBranch, index)
+		if err != nil {
+			return nil, err
+		}
+		if addr.Address() == current {
+			return keys, nil
+		}
+	}
+	return nil, ErrXpubMismatch
+}
+
+// watchOnlyKeys returns the keys of an account of a watch-only wallet. Only
+// account 0 is derived from the imported extended public key.
+func (wal *Wallet) watchOnlyKeys(w *dcrlibwallet.Wallet, account int32) (*accountKeys, error) {
+	if !w.IsWatchingOnlyWallet() {
+		return nil, errors.New("only watch-only wallets build unsigned transactions")
+	}
+	if account != 0 {
+		return nil, fmt.Errorf("account %d: %v", account, ErrNoAccountXpub)
+	}
+	xpub, err := wal.AccountXpub(w.ID)
+	if err != nil {
+		return nil, err
+	}
+	return wal.verifiedAccountKeys(w, xpub)
+}
+
+// nextChangeIndex returns the internal address index to pay change to: the
+// first one after the last used address that no unsigned transaction paid
+// change to yet. It starts over after the last used address once the gap
+// limit is reached, since the wallet would not find change paid beyond it.
+func nextChangeIndex(w *dcrlibwallet.Wallet, internalCount uint32) uint32 {
+	// internalCount is the last used index plus the gap limit, which wraps
+	// around to the gap limit less one when no address was used.
+	firstUnused := internalCount - dcrlibwallet.AddressGapLimit + 1
+	var index uint32
+	w.ReadUserConfigValue(changeIndexConfigKey, &index)
+	if index < firstUnused || index >= internalCount {
+		index = firstUnused
+	}
+	return index
+}
+
+// ChangeAddress returns the internal address the next unsigned transaction
+// built from account of a watch-only wallet pays change to.
+func (wal *Wallet) ChangeAddress(walletID int, account int32) (string, error) {
+	w := wal.multi.WalletWithID(walletID)
+	if w == nil {
+		return "", ErrIDNotExist
+	}
+	keys, err := wal.watchOnlyKeys(w, account)
+	if err != nil {
+		return "", err
+	}
+	_, internalCount, err := keyCounts(w, account)
+	if err != nil {
+		return "", err
+	}
+	addr, err := keys.address(internalBranch, nextChangeIndex(w, internalCount))
+	if err != nil {
+		return "", err
+	}
+	return addr.Address(), nil
+}